@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/agentplexus/agent-team-release/pkg/actions"
+)
+
+// Bump command flags
+var (
+	bumpDryRun bool
+)
+
+// bumpCmd represents the bump command
+var bumpCmd = &cobra.Command{
+	Use:   "bump <version> [directory]",
+	Short: "Set the version in package manifests and version.go",
+	Long: `Set the version fields of the project's manifests to a release version.
+
+This command updates:
+  - "version" in package.json
+  - version in the [package] or [workspace.package] table of Cargo.toml
+  - version in the [project] or [tool.poetry] table of pyproject.toml
+  - *Version string constants in Go version.go files
+
+Manifests get the version without the "v" prefix; Go constants keep the
+form they already use. Files in nested Go modules are left alone. The
+release workflow runs this before creating the release commit.
+
+Examples:
+  atrelease bump v2.3.0              # Update version fields
+  atrelease bump v2.3.0 --dry-run    # Show what would change`,
+	Args: cobra.RangeArgs(1, 2),
+	Run:  runBump,
+}
+
+func init() {
+	bumpCmd.Flags().BoolVar(&bumpDryRun, "dry-run", false, "Show what would be done without making changes")
+
+	rootCmd.AddCommand(bumpCmd)
+}
+
+func runBump(cmd *cobra.Command, args []string) {
+	version := args[0]
+
+	// Get directory
+	dir := "."
+	if len(args) > 1 {
+		dir = args[1]
+	}
+
+	// Make sure directory exists
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Error: directory %s does not exist\n", dir)
+		os.Exit(1)
+	}
+
+	fmt.Println("=== Bump ===")
+	fmt.Println()
+
+	action := &actions.BumpAction{}
+	opts := actions.Options{
+		Version: version,
+		DryRun:  bumpDryRun,
+		Verbose: cfgVerbose,
+	}
+
+	result := action.Run(dir, opts)
+
+	if result.Output != "" {
+		fmt.Println(result.Output)
+	}
+
+	if !result.Success {
+		if result.Error != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", result.Error)
+		}
+		os.Exit(1)
+	}
+
+	fmt.Println()
+	fmt.Println("Bump action completed successfully.")
+}
@@ -7,36 +7,58 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/agentplexus/agent-team-release/pkg/actions"
+	"github.com/agentplexus/agent-team-release/pkg/changelog"
+	"github.com/agentplexus/agent-team-release/pkg/interactive"
 )
 
 // Changelog command flags
 var (
-	changelogSince  string
-	changelogDryRun bool
+	changelogSince   string
+	changelogVersion string
+	changelogNotes   bool
+	changelogDryRun  bool
 )
 
 // changelogCmd represents the changelog command
 var changelogCmd = &cobra.Command{
 	Use:   "changelog [directory]",
 	Short: "Generate or update changelog",
-	Long: `Generate or update CHANGELOG.md using schangelog.
-
-This command parses git commits since the specified tag (or latest tag)
-and can regenerate CHANGELOG.md from CHANGELOG.json.
-
-Requires schangelog to be installed:
-  go install github.com/grokify/schangelog/cmd/schangelog@latest
+	Long: `Validate CHANGELOG.json and regenerate CHANGELOG.md from it.
+
+This command lists the git commits since the specified tag (or latest tag),
+checks CHANGELOG.json (versions, dates, release order, descriptions), and
+renders CHANGELOG.md in the Keep a Changelog layout.
+
+With --version, a release entry that CHANGELOG.json doesn't have yet is
+drafted from the commits: each commit is classified as added, changed,
+deprecated, removed, fixed or security from its conventional type, subject
+and changed files. With --interactive, you are asked about each commit whose
+classification is uncertain before anything is written. Issue and pull
+request references in commit messages ("#12", "Fixes #45", "owner/repo#3",
+GitLab "!7", or their URLs) are kept on each change, linked to the remote's
+GitHub, GitLab or Gitea repository. The entry also acknowledges the
+commits' authors and co-authors, merged through .mailmap, flagging
+first-time contributors.
+
+With --notes, the release notes for --version are written to
+docs/releases/<version>.md, or RELEASE_NOTES_<version>.md if there is no docs
+directory.
 
 Examples:
-  atrelease changelog                    # Parse commits since latest tag
-  atrelease changelog --since=v0.2.0     # Parse commits since v0.2.0
-  atrelease changelog --dry-run          # Show what would be done`,
+  atrelease changelog                           # Regenerate CHANGELOG.md
+  atrelease changelog --version=v0.3.0          # Draft the v0.3.0 entry
+  atrelease changelog --version=v0.3.0 -i       # Review uncertain commits
+  atrelease changelog --version=v0.3.0 --notes  # Also write release notes
+  atrelease changelog --since=v0.2.0            # List commits since v0.2.0
+  atrelease changelog --dry-run                 # Show what would be done`,
 	Args: cobra.MaximumNArgs(1),
 	Run:  runChangelog,
 }
 
 func init() {
-	changelogCmd.Flags().StringVar(&changelogSince, "since", "", "Parse commits since this tag (default: latest tag)")
+	changelogCmd.Flags().StringVar(&changelogSince, "since", "", "List commits since this tag (default: latest tag)")
+	changelogCmd.Flags().StringVar(&changelogVersion, "version", "", "Draft a CHANGELOG.json entry for this version (e.g., v0.3.0)")
+	changelogCmd.Flags().BoolVar(&changelogNotes, "notes", false, "Also write the release notes for --version")
 	changelogCmd.Flags().BoolVar(&changelogDryRun, "dry-run", false, "Show what would be done without making changes")
 
 	rootCmd.AddCommand(changelogCmd)
@@ -58,11 +80,18 @@ func runChangelog(cmd *cobra.Command, args []string) {
 	fmt.Println("=== Changelog ===")
 	fmt.Println()
 
-	action := &actions.ChangelogAction{}
+	action := &actions.ChangelogAction{ReleaseNotes: changelogNotes}
+	if prompter := approvalPrompter(); prompter != nil {
+		action.Classify = func(d *changelog.Draft) error {
+			return interactive.ClassifyChanges(prompter, d)
+		}
+	}
 	opts := actions.Options{
-		Since:   changelogSince,
-		DryRun:  changelogDryRun,
-		Verbose: cfgVerbose,
+		Since:       changelogSince,
+		Version:     changelogVersion,
+		DryRun:      changelogDryRun,
+		Verbose:     cfgVerbose,
+		Interactive: cfgInteractive,
 	}
 
 	result := action.Run(dir, opts)
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/agentplexus/agent-team-release/pkg/ledger"
+	"github.com/agentplexus/agent-team-release/pkg/workflow"
+)
+
+// History command flags
+var (
+	historyVersion string
+	historyCommand string
+	historyUser    string
+	historySince   string
+	historyFailed  bool
+	historyLimit   int
+)
+
+// historyCmd shows the release ledger.
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the ledger of release and validation runs",
+	Long: `Show who ran which release or validation, on which commit, with which
+flags, and how each step and validation area fared.
+
+Every release, release branch, hotfix, promote, resume and validate run
+appends a record to .git/atrelease/ledger.jsonl, one JSON object per
+line. Records are never rewritten.
+
+Runs are listed newest first. With --verbose, each run's steps, rollback
+and validation area statuses are shown; --json outputs the records.
+
+Examples:
+  atrelease history                       # The 20 most recent runs
+  atrelease history --version v1.2.0 -v   # Every run for v1.2.0, in detail
+  atrelease history --command release --failed
+  atrelease history --since 7d --user alice
+  atrelease history --json --format json --limit 0`,
+	Args: cobra.NoArgs,
+	Run:  runHistory,
+}
+
+func init() {
+	historyCmd.Flags().StringVar(&historyVersion, "version", "", "Only runs for this version")
+	historyCmd.Flags().StringVar(&historyCommand, "command", "", "Only runs of this command (e.g., release, validate, hotfix)")
+	historyCmd.Flags().StringVar(&historyUser, "user", "", "Only runs by users matching this (case-insensitive)")
+	historyCmd.Flags().StringVar(&historySince, "since", "", "Only runs since a date (2006-01-02) or age (e.g., 7d, 12h)")
+	historyCmd.Flags().BoolVar(&historyFailed, "failed", false, "Only runs that failed or were interrupted")
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 20, "Maximum number of runs to show (0 = all)")
+
+	rootCmd.AddCommand(historyCmd)
+}
+
+// HistoryResult is the structured output of the history command.
+type HistoryResult struct {
+	Type    string          `json:"type" toon:"type"`
+	Records []ledger.Record `json:"records" toon:"records"`
+}
+
+func runHistory(cmd *cobra.Command, args []string) {
+	path, err := ledger.Path(".")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	query := ledger.Query{
+		Command: historyCommand,
+		Version: historyVersion,
+		User:    historyUser,
+		Failed:  historyFailed,
+		Limit:   historyLimit,
+	}
+	if historySince != "" {
+		if query.Since, err = parseSince(historySince, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	records, err := ledger.Read(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	records = query.Filter(records)
+
+	if cfgJSON {
+		result := HistoryResult{Type: "history", Records: records}
+		if err := messageWriter().Write(result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding result: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(records) == 0 {
+		fmt.Println("No matching runs recorded")
+		return
+	}
+	for _, rec := range records {
+		if cfgVerbose {
+			fmt.Println(rec.Details())
+		} else {
+			fmt.Println(rec.Line())
+		}
+	}
+}
+
+// parseSince parses a date (2006-01-02), an RFC 3339 time, or an age such
+// as "7d" or "12h" relative to now.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: use a date (2006-01-02) or an age (7d, 12h)", s)
+}
+
+// startRecord starts the ledger record of a command run, noting the flags
+// set on the command line.
+func startRecord(cmd *cobra.Command, dir, version string) ledger.Record {
+	var flags []string
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if f.Value.Type() == "bool" && f.Value.String() == "true" {
+			flags = append(flags, "--"+f.Name)
+			return
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			for _, v := range sv.GetSlice() {
+				flags = append(flags, fmt.Sprintf("--%s=%s", f.Name, v))
+			}
+			return
+		}
+		flags = append(flags, fmt.Sprintf("--%s=%s", f.Name, f.Value.String()))
+	})
+
+	command := strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" ")
+	return ledger.NewRecord(dir, command, version, flags)
+}
+
+// recordWorkflow appends a workflow run to the ledger.
+func recordWorkflow(dir string, rec ledger.Record, ctx *workflow.Context, result *workflow.WorkflowResult) {
+	rec.Version = ctx.Version
+	rec.Module = workflow.ReleaseModule(ctx)
+	rec.AddWorkflow(result, ctx.Data)
+	appendRecord(dir, rec)
+}
+
+// appendRecord appends a record to the ledger, warning if it can't.
+// Runs outside a git repository have no ledger.
+func appendRecord(dir string, rec ledger.Record) {
+	path, err := ledger.Path(dir)
+	if err != nil {
+		if cfgVerbose {
+			fmt.Fprintf(os.Stderr, "Warning: run not recorded: %v\n", err)
+		}
+		return
+	}
+	if err := ledger.Append(path, rec); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record run in ledger: %v\n", err)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/agentplexus/agent-team-release/pkg/workflow"
+)
+
+// Hotfix command flags
+var (
+	hotfixFrom  string
+	hotfixPicks []string
+)
+
+// hotfixCmd releases a patch version from an older tag.
+var hotfixCmd = &cobra.Command{
+	Use:   "hotfix <version>",
+	Short: "Release a hotfix from an earlier tag",
+	Long: `Release a patch version from an earlier tag without touching the
+default branch.
+
+The hotfix workflow:
+  1. Validate version format and check it doesn't exist
+  2. Ensure working directory is clean
+  3. Create a temporary hotfix/<version> branch at --from, which defaults
+     to the latest earlier tag of the release line (e.g., v1.4.2 for v1.4.3)
+  4. Cherry-pick the --pick commits
+  5. Bump the version in the manifests and version.go
+  6. Run validation checks and the PM, documentation, security and
+     release validation areas on the bumped tree
+  7. Add a CHANGELOG.json entry listing the picked commits as fixes,
+     placed in version order, and regenerate CHANGELOG.md
+  8. Create the release commit on the hotfix branch
+  9. Create and push the release tag
+  10. Return to the original branch and delete the hotfix branch
+
+Only the tag is pushed; no branch is changed locally or on the remote. On
+failure, the original branch is checked out again and the hotfix branch
+is deleted.
+
+Examples:
+  atrelease hotfix v1.4.3 --from v1.4.2 --pick 1a2b3c4
+  atrelease hotfix v1.4.3 --pick 1a2b3c4 --pick 5d6e7f8
+  atrelease hotfix v1.4.3 --pick 1a2b3c4 --dry-run
+  atrelease release resume v1.4.3        # Resume an interrupted hotfix`,
+	Args: cobra.ExactArgs(1),
+	Run:  runHotfix,
+}
+
+func init() {
+	hotfixCmd.Flags().StringVar(&hotfixFrom, "from", "", "Tag to fix (default: the latest earlier tag of the release line)")
+	hotfixCmd.Flags().StringArrayVar(&hotfixPicks, "pick", nil, "Commit to cherry-pick onto the tag (repeatable, applied in order)")
+	hotfixCmd.Flags().BoolVar(&releaseDryRun, "dry-run", false, "Preview what would be done without making changes")
+	hotfixCmd.Flags().BoolVar(&releaseSkipChecks, "skip-checks", false, "Skip validation checks (dangerous)")
+	hotfixCmd.Flags().BoolVar(&releaseNoRollback, "no-rollback", false, "Don't roll back completed steps when a required step fails")
+	hotfixCmd.Flags().BoolVar(&releaseDiscard, "discard", false, "Discard the checkpoint of an interrupted run and start over")
+	hotfixCmd.Flags().IntVar(&releaseMaxWorkers, "max-workers", workflow.DefaultMaxWorkers, "Maximum number of independent steps run concurrently")
+	_ = hotfixCmd.MarkFlagRequired("pick")
+
+	rootCmd.AddCommand(hotfixCmd)
+}
+
+func runHotfix(cmd *cobra.Command, args []string) {
+	version := args[0]
+	dir := "."
+
+	sigCtx, stop := interruptContext()
+	defer stop()
+	ctx := workflow.NewContext(dir, version)
+	ctx.Ctx = sigCtx
+	ctx.SkipChecks = releaseSkipChecks
+	if err := workflow.SetHotfix(ctx, hotfixFrom, hotfixPicks); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	runner := releaseRunner(dir)
+	runner.DryRun = releaseDryRun
+	if statePath, err := workflow.StatePath(dir, version); err == nil {
+		runner.StateFile = statePath
+	} else if cfgVerbose {
+		fmt.Fprintf(os.Stderr, "Warning: checkpointing disabled: %v\n", err)
+	}
+
+	if runner.StateFile != "" && !releaseDryRun {
+		checkNoCheckpoint(runner.StateFile, version, "atrelease release resume "+version)
+	}
+
+	rec := startRecord(cmd, dir, version)
+	result := runner.Run(workflow.HotfixWorkflow(version), ctx)
+	recordWorkflow(dir, rec, ctx, result)
+
+	if result.Cancelled && runner.StateFile != "" {
+		fmt.Fprintf(os.Stderr, "Hotfix interrupted. Resume with: atrelease release resume %s\n", version)
+	}
+	printWorkflowResult(result)
+}
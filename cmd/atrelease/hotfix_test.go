@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// cliArgsEnv holds the arguments, newline-separated, of a CLI run by runCLI.
+const cliArgsEnv = "ATRELEASE_TEST_ARGS"
+
+// TestMain runs the CLI instead of the tests when re-executed by runCLI.
+func TestMain(m *testing.M) {
+	if args := os.Getenv(cliArgsEnv); args != "" {
+		rootCmd.SetArgs(strings.Split(args, "\n"))
+		Execute()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runCLI runs atrelease with args in dir and returns its output and exit code.
+func runCLI(t *testing.T, dir string, args ...string) (stdout, stderr string, code int) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0])
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), cliArgsEnv+"="+strings.Join(args, "\n"))
+	var out, errOut bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &errOut
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code = exitErr.ExitCode()
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	return out.String(), errOut.String(), code
+}
+
+// gitRun runs a git command in dir and returns its trimmed output.
+func gitRun(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v: %s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestHotfix_RefusesUnfinishedCheckpoint(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	dir := t.TempDir()
+	gitRun(t, dir, "init")
+	gitRun(t, dir, "config", "user.email", "test@example.com")
+	gitRun(t, dir, "config", "user.name", "Test User")
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "initial")
+	gitRun(t, dir, "tag", "-a", "v1.0.0", "-m", "Release v1.0.0")
+	gitRun(t, dir, "commit", "--allow-empty", "-m", "fix: crash")
+	fix := gitRun(t, dir, "rev-parse", "HEAD")
+
+	statePath := filepath.Join(dir, ".git", "atrelease", "v1.0.1.json")
+	if err := os.MkdirAll(filepath.Dir(statePath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	checkpoint := `{"workflow": "Hotfix v1.0.1", "version": "v1.0.1", "completed": false, "steps": []}`
+	if err := os.WriteFile(statePath, []byte(checkpoint), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, stderr, code := runCLI(t, dir, "hotfix", "v1.0.1", "--pick", fix)
+	if code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+	for _, want := range []string{"interrupted v1.0.1 release", "atrelease release resume v1.0.1", "--discard"} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("stderr is missing %q:\n%s", want, stderr)
+		}
+	}
+	if data, _ := os.ReadFile(statePath); string(data) != checkpoint {
+		t.Errorf("checkpoint was overwritten:\n%s", data)
+	}
+
+	// A dry run doesn't checkpoint, so it isn't refused
+	if _, stderr, code := runCLI(t, dir, "hotfix", "v1.0.1", "--pick", fix, "--dry-run"); strings.Contains(stderr, "checkpointed") {
+		t.Errorf("dry run refused (exit code %d):\n%s", code, stderr)
+	}
+
+	// --discard drops the checkpoint and starts over
+	stdout, stderr, _ := runCLI(t, dir, "hotfix", "v1.0.1", "--pick", fix, "--skip-checks", "--discard")
+	if !strings.Contains(stdout, "Discarded the checkpoint") {
+		t.Errorf("--discard did not discard the checkpoint:\n%s\n%s", stdout, stderr)
+	}
+	if data, _ := os.ReadFile(statePath); string(data) == checkpoint {
+		t.Error("checkpoint was not replaced by the new run")
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/agentplexus/agent-team-release/pkg/workflow"
+)
+
+// promoteCmd promotes a release candidate to a release.
+var promoteCmd = &cobra.Command{
+	Use:   "promote <candidate>",
+	Short: "Promote a release candidate to a release",
+	Long: `Promote a release candidate such as v1.2.0-rc.3 to the release v1.2.0.
+
+The promotion workflow:
+  1. Check the candidate tag exists, the release tag doesn't, and HEAD is
+     the candidate's commit on a branch
+  2. Ensure working directory is clean
+  3. Check CI passed on the candidate's commit
+  4. Tag the candidate's commit as the release and push the tag
+  5. Fold the candidates' CHANGELOG.json entries into one release entry
+     and regenerate CHANGELOG.md
+  6. Commit and push the changelog
+
+The promotion is refused if HEAD is not the candidate's commit, or if CI
+on it is failing or still running. --skip-ci skips the CI check, which is
+also needed without the gh CLI.
+
+Examples:
+  atrelease promote v1.2.0-rc.3
+  atrelease promote v1.2.0-rc.3 --dry-run
+  atrelease release resume v1.2.0        # Resume an interrupted promotion`,
+	Args: cobra.ExactArgs(1),
+	Run:  runPromote,
+}
+
+func init() {
+	promoteCmd.Flags().BoolVar(&releaseDryRun, "dry-run", false, "Preview what would be done without making changes")
+	promoteCmd.Flags().BoolVar(&releaseSkipCI, "skip-ci", false, "Don't check CI on the candidate (dangerous)")
+	promoteCmd.Flags().BoolVar(&releaseNoRollback, "no-rollback", false, "Don't roll back completed steps when a required step fails")
+	promoteCmd.Flags().BoolVar(&releaseDiscard, "discard", false, "Discard the checkpoint of an interrupted run and start over")
+
+	rootCmd.AddCommand(promoteCmd)
+}
+
+func runPromote(cmd *cobra.Command, args []string) {
+	dir := "."
+
+	sigCtx, stop := interruptContext()
+	defer stop()
+	ctx := workflow.NewContext(dir, "")
+	ctx.Ctx = sigCtx
+	ctx.SkipCI = releaseSkipCI
+	if err := workflow.SetPromotion(ctx, args[0]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	version := ctx.Version
+
+	runner := releaseRunner(dir)
+	runner.DryRun = releaseDryRun
+	if statePath, err := workflow.StatePath(dir, version); err == nil {
+		runner.StateFile = statePath
+	} else if cfgVerbose {
+		fmt.Fprintf(os.Stderr, "Warning: checkpointing disabled: %v\n", err)
+	}
+
+	if runner.StateFile != "" && !releaseDryRun {
+		checkNoCheckpoint(runner.StateFile, version, "atrelease release resume "+version)
+	}
+
+	rec := startRecord(cmd, dir, version)
+	result := runner.Run(workflow.PromoteWorkflow(version), ctx)
+	recordWorkflow(dir, rec, ctx, result)
+
+	if result.Cancelled && runner.StateFile != "" {
+		fmt.Fprintf(os.Stderr, "Promotion interrupted. Resume with: atrelease release resume %s\n", version)
+	}
+	printWorkflowResult(result)
+}
@@ -1,13 +1,19 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
 	"github.com/spf13/cobra"
-	"github.com/toon-format/toon-go"
 
+	"github.com/agentplexus/agent-team-release/pkg/config"
+	"github.com/agentplexus/agent-team-release/pkg/interactive"
+	"github.com/agentplexus/agent-team-release/pkg/output"
+	"github.com/agentplexus/agent-team-release/pkg/report"
 	"github.com/agentplexus/agent-team-release/pkg/workflow"
 )
 
@@ -16,31 +22,80 @@ var (
 	releaseDryRun     bool
 	releaseSkipChecks bool
 	releaseSkipCI     bool
+	releaseNoRollback bool
+	releaseMaxWorkers int
+	releaseTeam       string
+	releaseRC         bool
+	releaseModule     string
+	releaseResume     bool
+	releaseDiscard    bool
+
+	releaseBranchBase  string
+	releaseBranchPicks []string
 )
 
 // releaseCmd represents the release command
 var releaseCmd = &cobra.Command{
-	Use:   "release <version>",
+	Use:   "release [version]",
 	Short: "Create a release",
 	Long: `Execute the full release workflow for the specified version.
 
 The release workflow includes:
   1. Validate version format and check it doesn't exist
   2. Ensure working directory is clean
-  3. Run validation checks (build, test, lint, format)
-  4. Generate/update changelog
-  5. Update roadmap
-  6. Create release commit
-  7. Push to remote
-  8. Wait for CI to pass
-  9. Create and push release tag
+  3. Bump the version in package.json, Cargo.toml, pyproject.toml and
+     version.go
+  4. Run validation checks (build, test, lint, format) on the bumped tree
+  5. Generate/update changelog
+  6. Update roadmap
+  7. Update README version references and badges
+  8. Create release commit
+  9. Push to remote
+  10. Wait for CI to pass
+  11. Create and push release tag
+
+Steps 5 to 7 run concurrently once the checks pass.
 
 Examples:
   atrelease release v0.3.0
-  atrelease release v0.3.0 --dry-run     # Preview without changes
+  atrelease release v0.3.0 --dry-run     # Print the release plan with diffs
   atrelease release v0.3.0 --skip-ci     # Don't wait for CI
-  atrelease release v0.3.0 --skip-checks # Skip validation`,
-	Args: cobra.ExactArgs(1),
+  atrelease release v0.3.0 --skip-checks # Skip validation
+  atrelease release v0.3.0 --no-rollback # Leave partial changes on failure
+  atrelease release v0.3.0 --rc          # Release the next candidate, e.g. v0.3.0-rc.2
+  atrelease release --rc                 # Continue the latest candidate series
+  atrelease release v1.2.3 --module sdk/go
+                                         # Release the sdk/go module as sdk/go/v1.2.3
+  atrelease release modules              # Show which modules changed since their last tag
+  atrelease release v0.3.0 --resume      # Resume an interrupted release
+  atrelease release v0.3.0 --discard     # Start over, discarding its checkpoint
+  atrelease release branch v1.4.3 --pick 1a2b3c4
+                                         # Release from the release/1.4 branch
+  atrelease release v0.3.0 --team specs/teams/release-team.json
+                                         # Run the steps defined by a team spec
+
+With --interactive, pushing and tagging pause for approval, showing the
+refs, remote and tag message; answer proceed, skip or abort.
+
+If a required step fails, completed steps are rolled back in reverse order:
+the tag is deleted, a pushed release commit is reverted, and an unpushed
+release commit is reset.
+
+With --rc, the version released is the next release candidate of the
+given version, numbered after its existing -rc.N tags. Promote a tested
+candidate with atrelease promote.
+
+With --module, the Go module in that directory of a multi-module
+repository is released with a module-prefixed tag (sdk/go/v1.2.3). Its
+changelog, README and validation are scoped to the module's directory,
+and the version is checked against the module's own tags and major
+version suffix.
+
+Progress is checkpointed to .git/atrelease/<version>.json after every step,
+together with --team and --module, which a resumed release reuses. A new
+release is refused while an unfinished checkpoint exists: continue it with
+--resume or start over with --discard.`,
+	Args: releaseArgs,
 	Run:  runRelease,
 }
 
@@ -48,12 +103,125 @@ func init() {
 	releaseCmd.Flags().BoolVar(&releaseDryRun, "dry-run", false, "Preview what would be done without making changes")
 	releaseCmd.Flags().BoolVar(&releaseSkipChecks, "skip-checks", false, "Skip validation checks (dangerous)")
 	releaseCmd.Flags().BoolVar(&releaseSkipCI, "skip-ci", false, "Don't wait for CI to pass before tagging")
+	releaseCmd.Flags().BoolVar(&releaseNoRollback, "no-rollback", false, "Don't roll back completed steps when a required step fails")
+	releaseCmd.Flags().StringVar(&releaseTeam, "team", "", "Build the workflow from a multi-agent-spec team definition")
+	releaseCmd.Flags().IntVar(&releaseMaxWorkers, "max-workers", workflow.DefaultMaxWorkers, "Maximum number of independent steps run concurrently")
+	releaseCmd.Flags().BoolVar(&releaseRC, "rc", false, "Release the next release candidate (vX.Y.Z-rc.N) of the version")
+	releaseCmd.Flags().StringVar(&releaseModule, "module", "", "Release the Go module in this directory with a module-prefixed tag (e.g., sdk/go)")
+	releaseCmd.Flags().BoolVar(&releaseResume, "resume", false, "Resume the interrupted release from its checkpoint")
+	releaseCmd.Flags().BoolVar(&releaseDiscard, "discard", false, "Discard the checkpoint of an interrupted release and start over")
+	releaseCmd.MarkFlagsMutuallyExclusive("resume", "discard")
+
+	releaseResumeCmd.Flags().BoolVar(&releaseSkipChecks, "skip-checks", false, "Skip validation checks (dangerous)")
+	releaseResumeCmd.Flags().BoolVar(&releaseSkipCI, "skip-ci", false, "Don't wait for CI to pass before tagging")
+	releaseResumeCmd.Flags().BoolVar(&releaseNoRollback, "no-rollback", false, "Don't roll back completed steps when a required step fails")
+	releaseResumeCmd.Flags().StringVar(&releaseTeam, "team", "", "Team definition to use instead of the checkpointed one")
+	releaseResumeCmd.Flags().IntVar(&releaseMaxWorkers, "max-workers", workflow.DefaultMaxWorkers, "Maximum number of independent steps run concurrently")
+
+	releaseBranchCmd.Flags().StringVar(&releaseBranchBase, "base", "", "Ref to create a new release branch from (default: the latest tag of the release line, or the default branch)")
+	releaseBranchCmd.Flags().StringArrayVar(&releaseBranchPicks, "pick", nil, "Commit to cherry-pick onto the release branch (repeatable, applied in order)")
+	releaseBranchCmd.Flags().BoolVar(&releaseDryRun, "dry-run", false, "Preview what would be done without making changes")
+	releaseBranchCmd.Flags().BoolVar(&releaseSkipChecks, "skip-checks", false, "Skip validation checks (dangerous)")
+	releaseBranchCmd.Flags().BoolVar(&releaseSkipCI, "skip-ci", false, "Don't wait for CI to pass before tagging")
+	releaseBranchCmd.Flags().BoolVar(&releaseNoRollback, "no-rollback", false, "Don't roll back completed steps when a required step fails")
+	releaseBranchCmd.Flags().IntVar(&releaseMaxWorkers, "max-workers", workflow.DefaultMaxWorkers, "Maximum number of independent steps run concurrently")
+	releaseBranchCmd.Flags().BoolVar(&releaseDiscard, "discard", false, "Discard the checkpoint of an interrupted release and start over")
 
+	releaseCmd.AddCommand(releaseResumeCmd)
+	releaseCmd.AddCommand(releaseModulesCmd)
+	releaseCmd.AddCommand(releaseBranchCmd)
 	rootCmd.AddCommand(releaseCmd)
 }
 
+// releaseResumeCmd resumes an interrupted release from its checkpoint.
+var releaseResumeCmd = &cobra.Command{
+	Use:   "resume <version>",
+	Short: "Resume an interrupted release",
+	Long: `Resume a release workflow from its last checkpoint.
+
+Steps that completed successfully in the previous run are skipped. The
+team definition and module of the interrupted release are reused. The
+resume is refused if HEAD has moved since the last checkpoint.
+
+Examples:
+  atrelease release resume v0.3.0
+  atrelease release resume v0.3.0 --skip-ci
+  atrelease release resume sdk/go/v1.2.3  # Resume a module release`,
+	Args: cobra.ExactArgs(1),
+	Run:  runReleaseResume,
+}
+
+// releaseBranchCmd releases a version from its maintenance branch.
+var releaseBranchCmd = &cobra.Command{
+	Use:   "branch <version>",
+	Short: "Release from a release/X.Y maintenance branch",
+	Long: `Release a version from its release/X.Y maintenance branch.
+
+The release branch workflow:
+  1. Validate version format and check it doesn't exist
+  2. Ensure working directory is clean
+  3. Check out release/X.Y, creating it if it doesn't exist locally or on
+     the remote from --base, which defaults to the latest tag of the
+     release line (e.g., v1.4.2 for v1.4.3) or else the default branch
+  4. Cherry-pick the --pick commits, skipping any already on the branch
+  5. Bump the version in the manifests and version.go
+  6. Run validation checks on the bumped tree, update the changelog and
+     README
+  7. Create the release commit, push the branch and wait for CI
+  8. Create and push the release tag on the branch
+  9. Add the version's CHANGELOG.json entry to the default branch,
+     regenerate CHANGELOG.md there, commit and push
+
+Afterwards the branch you started on is checked out again. On failure,
+cherry-picks that were not pushed are dropped and a newly created release
+branch is deleted.
+
+Examples:
+  atrelease release branch v1.4.3 --pick 1a2b3c4 --pick 5d6e7f8
+  atrelease release branch v1.5.0 --base v1.5.0-rc.2
+  atrelease release branch v1.4.3 --pick 1a2b3c4 --dry-run`,
+	Args: cobra.ExactArgs(1),
+	Run:  runReleaseBranch,
+}
+
+// releaseModulesCmd shows which Go modules changed since their last tag.
+var releaseModulesCmd = &cobra.Command{
+	Use:   "modules",
+	Short: "Show which Go modules changed since their last tag",
+	Long: `List the Go modules of a multi-module repository with their latest
+release tag and the number of commits touching each since.
+
+A module is tagged with its directory as prefix, e.g. sdk/go/v1.2.3; the
+root module is tagged v1.2.3. Commits in a nested module count only
+towards the nested module. Release a module with
+atrelease release <version> --module <dir>.
+
+Examples:
+  atrelease release modules
+  atrelease release modules --json`,
+	Args: cobra.NoArgs,
+	Run:  runReleaseModules,
+}
+
+// ModulesResult is the structured output of the release modules command.
+type ModulesResult struct {
+	Type    string                  `json:"type" toon:"type"`
+	Modules []workflow.ModuleChange `json:"modules" toon:"modules"`
+}
+
+// releaseArgs requires a version, which --rc can infer from existing tags.
+func releaseArgs(cmd *cobra.Command, args []string) error {
+	if releaseRC {
+		return cobra.MaximumNArgs(1)(cmd, args)
+	}
+	return cobra.ExactArgs(1)(cmd, args)
+}
+
 func runRelease(cmd *cobra.Command, args []string) {
-	version := args[0]
+	var version string
+	if len(args) > 0 {
+		version = args[0]
+	}
 
 	// Get directory
 	dir := "."
@@ -64,46 +232,342 @@ func runRelease(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// Create workflow context
+	if releaseRC {
+		rc, err := workflow.NextCandidate(dir, releaseModule, version)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		version = rc
+		if !cfgJSON {
+			fmt.Printf("Release candidate: %s\n", workflow.ModuleTag(releaseModule, version))
+		}
+	}
+
+	tag := workflow.ModuleTag(releaseModule, version)
+	if releaseResume {
+		resumeRelease(cmd, dir, tag)
+		return
+	}
+
+	// Create workflow context, cancelled on Ctrl-C
+	sigCtx, stop := interruptContext()
+	defer stop()
 	ctx := workflow.NewContext(dir, version)
+	ctx.Ctx = sigCtx
 	ctx.SkipChecks = releaseSkipChecks
 	ctx.SkipCI = releaseSkipCI
+	if err := workflow.SetModule(ctx, releaseModule); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
 	// Create runner
-	runner := workflow.NewRunner()
+	runner := releaseRunner(dir)
+	runner.DryRun = releaseDryRun
+
+	runner.Team = releaseTeam
+
+	// Checkpoint progress so an interrupted release can be resumed
+	if statePath, err := workflow.StatePath(dir, tag); err == nil {
+		runner.StateFile = statePath
+	} else if cfgVerbose {
+		fmt.Fprintf(os.Stderr, "Warning: checkpointing disabled: %v\n", err)
+	}
+	if runner.StateFile != "" && !releaseDryRun {
+		checkNoCheckpoint(runner.StateFile, tag, "atrelease release "+releaseCommandArgs(tag)+" --resume")
+	}
+
+	// Create and run the release workflow
+	wf := buildReleaseWorkflow(version)
+	rec := startRecord(cmd, dir, version)
+	result := runner.Run(wf, ctx)
+	recordWorkflow(dir, rec, ctx, result)
+
+	if result.Cancelled && runner.StateFile != "" {
+		fmt.Fprintf(os.Stderr, "Release interrupted. Resume with: atrelease release %s --resume\n", releaseCommandArgs(workflow.ReleaseTag(ctx)))
+	}
+	printWorkflowResult(result)
+}
+
+// checkNoCheckpoint exits if an unfinished release of tag is checkpointed
+// at statePath, which a fresh run would overwrite, and tells how to resume
+// it with the resume command, if any, or start over with --discard. With
+// --discard the checkpoint is removed instead.
+func checkNoCheckpoint(statePath, tag, resume string) {
+	state, err := workflow.UnfinishedState(statePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if state == nil {
+		return
+	}
+	if releaseDiscard {
+		if err := os.Remove(statePath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !cfgJSON {
+			fmt.Printf("Discarded the checkpoint of the interrupted %s release\n", tag)
+		}
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Error: an interrupted %s release is checkpointed in %s\n", tag, statePath)
+	if resume != "" {
+		fmt.Fprintf(os.Stderr, "Resume it with: %s\n", resume)
+	}
+	fmt.Fprintln(os.Stderr, "Or start over by running the command again with --discard")
+	os.Exit(1)
+}
+
+// releaseCommandArgs returns the release command arguments selecting tag,
+// e.g. "v1.2.3 --module sdk/go" for sdk/go/v1.2.3.
+func releaseCommandArgs(tag string) string {
+	i := strings.LastIndex(tag, "/")
+	if i < 0 {
+		return tag
+	}
+	return tag[i+1:] + " --module " + tag[:i]
+}
+
+func runReleaseResume(cmd *cobra.Command, args []string) {
+	resumeRelease(cmd, ".", args[0])
+}
+
+// resumeRelease resumes the release of version, or module release tag,
+// from its checkpoint with the checkpointed team definition and module.
+func resumeRelease(cmd *cobra.Command, dir, version string) {
+	statePath, err := workflow.StatePath(dir, version)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	state, err := workflow.LoadState(statePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: no checkpoint for %s: %v\n", version, err)
+		os.Exit(1)
+	}
+
+	sigCtx, stop := interruptContext()
+	defer stop()
+	ctx := workflow.NewContext(dir, state.Version)
+	ctx.Ctx = sigCtx
+	ctx.SkipChecks = releaseSkipChecks
+	ctx.SkipCI = releaseSkipCI
+	if err := workflow.SetModule(ctx, state.Module); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if releaseTeam == "" {
+		releaseTeam = state.Team
+	}
+
+	runner := releaseRunner(dir)
+	runner.StateFile = statePath
+	runner.Resume = state
+	runner.Team = releaseTeam
+
+	var wf *workflow.Workflow
+	switch {
+	case workflow.IsPromotion(state):
+		wf = workflow.PromoteWorkflow(state.Version)
+	case workflow.IsHotfix(state):
+		wf = workflow.HotfixWorkflow(state.Version)
+	case workflow.IsBranchRelease(state):
+		wf = workflow.ReleaseBranchWorkflow(state.Version)
+	default:
+		wf = buildReleaseWorkflow(state.Version)
+	}
+	rec := startRecord(cmd, dir, state.Version)
+	result := runner.Run(wf, ctx)
+	recordWorkflow(dir, rec, ctx, result)
+
+	if result.Cancelled {
+		fmt.Fprintf(os.Stderr, "Release interrupted. Resume with: atrelease release resume %s\n", version)
+	}
+	printWorkflowResult(result)
+}
+
+func runReleaseModules(cmd *cobra.Command, args []string) {
+	changes, err := workflow.ChangedModules(".")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if cfgJSON {
+		result := ModulesResult{Type: "modules", Modules: changes}
+		if err := messageWriter().Write(result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding result: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(changes) == 0 {
+		fmt.Println("No Go modules found")
+		return
+	}
+	changed := 0
+	for _, c := range changes {
+		if c.Changed {
+			changed++
+		}
+	}
+	fmt.Printf("%d of %d Go modules changed since their last tag:\n\n", changed, len(changes))
+	for _, c := range changes {
+		icon, lastTag, commits := "○", c.LastTag, "unchanged"
+		if c.Changed {
+			icon = "●"
+		}
+		if lastTag == "" {
+			lastTag = "(untagged)"
+		}
+		if c.Commits > 0 {
+			commits = fmt.Sprintf("%d commit(s)", c.Commits)
+		}
+		fmt.Printf("  %s %-20s %-20s %s\n", icon, c.Dir, lastTag, commits)
+		if cfgVerbose {
+			fmt.Printf("      %s\n", c.Path)
+		}
+	}
+}
+
+func runReleaseBranch(cmd *cobra.Command, args []string) {
+	version := args[0]
+	dir := "."
+
+	sigCtx, stop := interruptContext()
+	defer stop()
+	ctx := workflow.NewContext(dir, version)
+	ctx.Ctx = sigCtx
+	ctx.SkipChecks = releaseSkipChecks
+	ctx.SkipCI = releaseSkipCI
+	if err := workflow.SetBranchRelease(ctx, releaseBranchBase, releaseBranchPicks); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	runner := releaseRunner(dir)
 	runner.DryRun = releaseDryRun
+	if statePath, err := workflow.StatePath(dir, version); err == nil {
+		runner.StateFile = statePath
+	} else if cfgVerbose {
+		fmt.Fprintf(os.Stderr, "Warning: checkpointing disabled: %v\n", err)
+	}
+	if runner.StateFile != "" && !releaseDryRun {
+		checkNoCheckpoint(runner.StateFile, version, "atrelease release resume "+version)
+	}
+
+	rec := startRecord(cmd, dir, version)
+	result := runner.Run(workflow.ReleaseBranchWorkflow(version), ctx)
+	recordWorkflow(dir, rec, ctx, result)
+
+	if result.Cancelled && runner.StateFile != "" {
+		fmt.Fprintf(os.Stderr, "Release interrupted. Resume with: atrelease release resume %s\n", version)
+	}
+	printWorkflowResult(result)
+}
+
+// releaseRunner returns a runner configured from the global and release flags.
+func releaseRunner(dir string) *workflow.Runner {
+	runner := workflow.NewRunner()
 	runner.Verbose = cfgVerbose
 	runner.Interactive = cfgInteractive
 	runner.JSONOutput = cfgJSON
+	runner.Events = progressSink()
+	runner.Prompter = approvalPrompter()
+	runner.NoRollback = releaseNoRollback
+	runner.MaxWorkers = releaseMaxWorkers
+	runner.Hooks = loadHooks(dir)
+	return runner
+}
 
-	// Create and run the release workflow
-	wf := workflow.ReleaseWorkflow(version)
-	result := runner.Run(wf, ctx)
+// interruptContext returns a context that is cancelled on SIGINT or SIGTERM,
+// so running steps and their child processes are stopped cleanly.
+func interruptContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// messageWriter returns a writer for structured messages on stdout: one
+// JSON document per line, or TOON documents separated by blank lines.
+func messageWriter() workflow.MessageWriter {
+	if GetOutputFormat() == OutputFormatJSON {
+		return output.NewJSONLinesWriter(os.Stdout)
+	}
+	return output.DefaultTOONWriter()
+}
 
-	// Print output
+// progressSink returns the event sink for streaming progress with --json,
+// or nil when output is human-readable.
+func progressSink() workflow.EventSink {
+	if !cfgJSON {
+		return nil
+	}
+	return workflow.NewWriterSink(messageWriter())
+}
+
+// approvalPrompter returns the prompter used for approval gates in
+// interactive mode, or nil when not interactive.
+func approvalPrompter() interactive.Prompter {
+	if !cfgInteractive {
+		return nil
+	}
 	if cfgJSON {
-		// Output structured result (TOON or JSON based on format flag)
-		jsonResult := result.ToJSON()
-		if GetOutputFormat() == OutputFormatJSON {
-			// JSON format
-			encoder := json.NewEncoder(os.Stdout)
-			encoder.SetIndent("", "  ")
-			if err := encoder.Encode(jsonResult); err != nil {
-				fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
-				os.Exit(1)
-			}
-		} else {
-			// TOON format (default)
-			data, err := toon.Marshal(jsonResult, toon.WithIndent(2))
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error encoding TOON: %v\n", err)
-				os.Exit(1)
-			}
-			fmt.Print(string(data))
+		return interactive.DefaultJSONPrompter()
+	}
+	return interactive.NewCLIPrompter()
+}
+
+// loadHooks returns the step hooks configured in .releaseagent.yaml.
+func loadHooks(dir string) map[string]config.HookSet {
+	cfg, err := config.Load(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: error loading config: %v\n", err)
+	}
+	return cfg.Hooks
+}
+
+// buildReleaseWorkflow returns the built-in release workflow, or the workflow
+// described by the --team spec if one was given.
+func buildReleaseWorkflow(version string) *workflow.Workflow {
+	if releaseTeam == "" {
+		return workflow.ReleaseWorkflow(version)
+	}
+
+	team, err := report.LoadTeamSpecFile(releaseTeam)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	wf, err := workflow.FromTeam(team, version)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return wf
+}
+
+// printWorkflowResult prints a workflow result in the configured format
+// and exits non-zero if the workflow failed (130 if it was cancelled).
+func printWorkflowResult(result *workflow.WorkflowResult) {
+	if cfgJSON {
+		// Output structured result after the streamed progress events
+		if err := messageWriter().Write(result.ToJSON()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding result: %v\n", err)
+			os.Exit(1)
 		}
 	} else {
 		fmt.Print(result.Output)
 
+		// Dry runs end with the plan of every change the release would make
+		if plan := result.PlanSummary(); plan != "" {
+			fmt.Println()
+			fmt.Print(plan)
+		}
+
 		// Print summary
 		if cfgVerbose {
 			fmt.Println()
@@ -111,6 +575,9 @@ func runRelease(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	if result.Cancelled {
+		os.Exit(130)
+	}
 	if !result.Success {
 		os.Exit(1)
 	}
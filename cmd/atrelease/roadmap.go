@@ -11,7 +11,8 @@ import (
 
 // Roadmap command flags
 var (
-	roadmapDryRun bool
+	roadmapDryRun  bool
+	roadmapVersion string
 )
 
 // roadmapCmd represents the roadmap command
@@ -21,20 +22,23 @@ var roadmapCmd = &cobra.Command{
 	Long: `Generate or update ROADMAP.md using sroadmap.
 
 This command validates ROADMAP.json and regenerates ROADMAP.md
-with deterministic formatting.
+with deterministic formatting. With --version, it also lists the
+roadmap items targeted at that version that are still open.
 
 Requires sroadmap to be installed:
   go install github.com/grokify/sroadmap/cmd/sroadmap@latest
 
 Examples:
   atrelease roadmap              # Regenerate ROADMAP.md
-  atrelease roadmap --dry-run    # Show stats without generating`,
+  atrelease roadmap --dry-run    # Show stats without generating
+  atrelease roadmap --version=v0.6.0 --dry-run  # Show open items for v0.6.0`,
 	Args: cobra.MaximumNArgs(1),
 	Run:  runRoadmap,
 }
 
 func init() {
 	roadmapCmd.Flags().BoolVar(&roadmapDryRun, "dry-run", false, "Show what would be done without making changes")
+	roadmapCmd.Flags().StringVar(&roadmapVersion, "version", "", "List roadmap items targeted at this version that are still open")
 
 	rootCmd.AddCommand(roadmapCmd)
 }
@@ -58,6 +62,7 @@ func runRoadmap(cmd *cobra.Command, args []string) {
 	action := &actions.RoadmapAction{}
 	opts := actions.Options{
 		DryRun:  roadmapDryRun,
+		Version: roadmapVersion,
 		Verbose: cfgVerbose,
 	}
 
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/agentplexus/agent-team-release/pkg/config"
+	"github.com/agentplexus/agent-team-release/pkg/workflow"
+)
+
+// Run command flags
+var (
+	runVersion    string
+	runDryRun     bool
+	runSkipChecks bool
+	runSkipCI     bool
+	runNoRollback bool
+)
+
+// runCmd represents the run command
+var runCmd = &cobra.Command{
+	Use:   "run <workflow>",
+	Short: "Run a custom workflow from .releaseagent.yaml",
+	Long: `Run a named workflow defined in the workflows section of .releaseagent.yaml.
+
+Workflow steps either reference a built-in step with "uses" or run a shell
+command with "run". Built-in steps:
+` + wrapList(workflow.StepRefs(), "  ", 72) + `
+
+Example configuration:
+  workflows:
+    release-with-helm:
+      description: Release and bump the Helm chart
+      steps:
+        - uses: validate-version
+        - uses: validate
+        - name: Regenerate protobufs
+          run: make proto
+          timeout: 5m
+        - name: Bump Helm chart
+          run: ./scripts/bump-chart.sh "$ATRELEASE_VERSION"
+          working_dir: deploy
+          env:
+            CHART: myapp
+        - uses: commit
+        - uses: tag
+
+Examples:
+  atrelease run release-with-helm --version v1.2.0
+  atrelease run release-with-helm --version v1.2.0 --dry-run`,
+	Args: cobra.ExactArgs(1),
+	Run:  runWorkflow,
+}
+
+func init() {
+	runCmd.Flags().StringVar(&runVersion, "version", "", "Target release version (e.g., v1.2.0)")
+	runCmd.Flags().BoolVar(&runDryRun, "dry-run", false, "Preview what would be done without making changes")
+	runCmd.Flags().BoolVar(&runSkipChecks, "skip-checks", false, "Skip validation checks (dangerous)")
+	runCmd.Flags().BoolVar(&runSkipCI, "skip-ci", false, "Don't wait for CI to pass")
+	runCmd.Flags().BoolVar(&runNoRollback, "no-rollback", false, "Don't roll back completed steps when a required step fails")
+
+	rootCmd.AddCommand(runCmd)
+}
+
+// wrapList joins items with commas into indented lines of at most width
+// characters.
+func wrapList(items []string, indent string, width int) string {
+	var lines []string
+	line := indent
+	for i, item := range items {
+		if i < len(items)-1 {
+			item += ","
+		}
+		if line != indent && len(line)+1+len(item) > width {
+			lines = append(lines, line)
+			line = indent
+		}
+		if line != indent {
+			line += " "
+		}
+		line += item
+	}
+	return strings.Join(append(lines, line), "\n")
+}
+
+func runWorkflow(cmd *cobra.Command, args []string) {
+	name := args[0]
+	dir := "."
+
+	cfg, err := config.Load(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	wc, ok := cfg.Workflows[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: workflow %q not defined in .releaseagent.yaml\n", name)
+		if len(cfg.Workflows) > 0 {
+			names := make([]string, 0, len(cfg.Workflows))
+			for n := range cfg.Workflows {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			fmt.Fprintf(os.Stderr, "Available workflows: %v\n", names)
+		}
+		os.Exit(1)
+	}
+
+	wf, err := workflow.FromConfig(name, wc)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	sigCtx, stop := interruptContext()
+	defer stop()
+	ctx := workflow.NewContext(dir, runVersion)
+	ctx.Ctx = sigCtx
+	ctx.SkipChecks = runSkipChecks
+	ctx.SkipCI = runSkipCI
+
+	runner := workflow.NewRunner()
+	runner.DryRun = runDryRun
+	runner.Verbose = cfgVerbose || cfg.Verbose
+	runner.Interactive = cfgInteractive
+	runner.JSONOutput = cfgJSON
+	runner.Events = progressSink()
+	runner.Prompter = approvalPrompter()
+	runner.NoRollback = runNoRollback
+	runner.Hooks = cfg.Hooks
+
+	rec := startRecord(cmd, dir, runVersion)
+	rec.Command += " " + name
+	result := runner.Run(wf, ctx)
+	recordWorkflow(dir, rec, ctx, result)
+	printWorkflowResult(result)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/agentplexus/agent-team-release/pkg/train"
+	"github.com/agentplexus/agent-team-release/pkg/workflow"
+)
+
+// trainCmd releases several dependent Go repositories in dependency order.
+var trainCmd = &cobra.Command{
+	Use:   "train [manifest]",
+	Short: "Release dependent Go repositories together",
+	Long: `Release several interdependent Go repositories together, in the order
+their go.mod files require.
+
+The manifest (default: release-train.yaml) lists the repositories and the
+version to release for each; paths are relative to the manifest:
+
+  tidy: true            # Run "go mod tidy" after bumping requirements
+  repos:
+    - path: ../core
+      version: v1.4.0
+    - path: ../sdk
+      version: v0.9.0
+
+For each repository, in dependency order, the train:
+  1. Bumps its go.mod requirements on the repositories released before it
+     and commits them as "chore(deps): ..."
+  2. Runs the release workflow, with the PM, documentation, security and
+     release validation areas gating the release commit
+
+The train stops at the first repository that fails or is NO-GO and reports
+the status of every repository. Repositories whose version is already
+tagged are skipped, so a stopped train can be run again after the problem
+is fixed; pass --discard to drop the checkpoint of the repository that
+stopped it.
+
+Examples:
+  atrelease train
+  atrelease train trains/q3.yaml
+  atrelease train --dry-run
+  atrelease train --skip-ci
+  atrelease train --discard  # Run a stopped train again`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runTrain,
+}
+
+// TrainResult is the structured output of the train command.
+type TrainResult struct {
+	Type    string             `json:"type" toon:"type"`
+	Success bool               `json:"success" toon:"success"`
+	Order   []string           `json:"order" toon:"order"`
+	Repos   []train.RepoResult `json:"repos" toon:"repos"`
+	Error   string             `json:"error,omitempty" toon:"error,omitempty"`
+}
+
+func init() {
+	trainCmd.Flags().BoolVar(&releaseDryRun, "dry-run", false, "Preview what would be done without making changes")
+	trainCmd.Flags().BoolVar(&releaseSkipChecks, "skip-checks", false, "Skip validation checks (dangerous)")
+	trainCmd.Flags().BoolVar(&releaseSkipCI, "skip-ci", false, "Don't wait for CI to pass before tagging")
+	trainCmd.Flags().BoolVar(&releaseNoRollback, "no-rollback", false, "Don't roll back completed steps when a required step fails")
+	trainCmd.Flags().BoolVar(&releaseDiscard, "discard", false, "Discard the checkpoints of interrupted releases and start over")
+	trainCmd.Flags().IntVar(&releaseMaxWorkers, "max-workers", workflow.DefaultMaxWorkers, "Maximum number of independent steps run concurrently")
+
+	rootCmd.AddCommand(trainCmd)
+}
+
+func runTrain(cmd *cobra.Command, args []string) {
+	path := train.DefaultManifest
+	if len(args) > 0 {
+		path = args[0]
+	}
+	manifest, err := train.LoadManifest(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Refuse before any repository is released, not midway
+	if !releaseDryRun {
+		for _, repo := range manifest.Repos {
+			if statePath, err := workflow.StatePath(repo.Dir(), repo.Version); err == nil {
+				checkNoCheckpoint(statePath, repo.Version, "")
+			}
+		}
+	}
+
+	sigCtx, stop := interruptContext()
+	defer stop()
+
+	t := &train.Train{
+		Manifest:   manifest,
+		DryRun:     releaseDryRun,
+		SkipChecks: releaseSkipChecks,
+		SkipCI:     releaseSkipCI,
+		Ctx:        sigCtx,
+	}
+	t.Runner = func(repo train.Repo) *workflow.Runner {
+		runner := releaseRunner(repo.Dir())
+		if statePath, err := workflow.StatePath(repo.Dir(), repo.Version); err == nil {
+			runner.StateFile = statePath
+		} else if cfgVerbose {
+			fmt.Fprintf(os.Stderr, "Warning: checkpointing disabled: %v\n", err)
+		}
+		return runner
+	}
+	t.Released = func(repo train.Repo, ctx *workflow.Context, result *workflow.WorkflowResult) {
+		recordWorkflow(repo.Dir(), startRecord(cmd, repo.Dir(), repo.Version), ctx, result)
+		if !cfgJSON {
+			fmt.Printf("\n[%s]\n", repo.Name)
+			fmt.Print(result.Output)
+		}
+	}
+
+	report := t.Run()
+
+	if cfgJSON {
+		if err := messageWriter().Write(TrainResult{
+			Type:    "train",
+			Success: report.Success,
+			Order:   report.Order,
+			Repos:   report.Repos,
+			Error:   report.Error,
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding result: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		fmt.Println()
+		fmt.Print(report.Summary())
+	}
+
+	for _, r := range report.Repos {
+		if r.Status == train.StatusCancelled {
+			os.Exit(130)
+		}
+	}
+	if !report.Success {
+		os.Exit(1)
+	}
+}
@@ -90,6 +90,8 @@ func runValidate(cmd *cobra.Command, args []string) {
 		cfg.Verbose = true
 	}
 
+	rec := startRecord(cmd, dir, validateVersion)
+
 	// Create validation report
 	validationReport := &checks.ValidationReport{
 		Version: validateVersion,
@@ -186,6 +188,9 @@ func runValidate(cmd *cobra.Command, args []string) {
 		checks.PrintValidationReport(validationReport)
 	}
 
+	rec.AddValidation(validationReport)
+	appendRecord(dir, rec)
+
 	// Exit with error if validation failed
 	if !validationReport.IsGo() {
 		os.Exit(1)
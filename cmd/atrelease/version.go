@@ -2,8 +2,12 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"sort"
 
 	"github.com/spf13/cobra"
+
+	"github.com/agentplexus/agent-team-release/pkg/workflow"
 )
 
 // versionCmd represents the version command
@@ -21,3 +25,112 @@ var versionCmd = &cobra.Command{
 		}
 	},
 }
+
+// versionNextCmd recommends the next version from the commit history.
+var versionNextCmd = &cobra.Command{
+	Use:   "next [directory]",
+	Short: "Recommend the next version from commits since the latest tag",
+	Long: `Recommend the next semantic version from the conventional commits since
+the latest release tag.
+
+  - Breaking changes ("feat!:" or a "BREAKING CHANGE:" footer) bump MAJOR;
+    while the major version is 0, they bump MINOR instead
+  - New features (feat:) bump MINOR
+  - Fixes and performance improvements (fix:, perf:) bump PATCH
+  - Any other commits call for a PATCH release
+
+The output lists the reasoning and the commits driving the bump.
+
+Examples:
+  atrelease version next
+  atrelease version next --json
+  atrelease version next --json --format json`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runVersionNext,
+}
+
+// VersionNextResult is the structured output of the version next command.
+type VersionNextResult struct {
+	Type    string                `json:"type" toon:"type"`
+	Current string                `json:"current,omitempty" toon:"current,omitempty"`
+	Next    string                `json:"next,omitempty" toon:"next,omitempty"`
+	Bump    string                `json:"bump" toon:"bump"`
+	Reason  string                `json:"reason" toon:"reason"`
+	Total   int                   `json:"total" toon:"total"`
+	Commits []workflow.CommitBump `json:"commits" toon:"commits"`
+	Types   map[string]int        `json:"types,omitempty" toon:"types,omitempty"`
+}
+
+func init() {
+	versionCmd.AddCommand(versionNextCmd)
+}
+
+func runVersionNext(cmd *cobra.Command, args []string) {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	rec, err := workflow.NextVersion(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if cfgJSON {
+		if err := messageWriter().Write(VersionNextResult{
+			Type:    "version_next",
+			Current: rec.Current,
+			Next:    rec.Next,
+			Bump:    rec.Bump,
+			Reason:  rec.Reason,
+			Total:   rec.Total,
+			Commits: rec.Commits,
+			Types:   rec.Types,
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding result: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	current := rec.Current
+	if current == "" {
+		current = "(no release tags)"
+	}
+	fmt.Printf("Current:   %s\n", current)
+	if rec.Next == "" {
+		fmt.Printf("Suggested: none (%s)\n", rec.Reason)
+		return
+	}
+	fmt.Printf("Suggested: %s (%s bump)\n", rec.Next, rec.Bump)
+	fmt.Printf("Reason:    %s\n", rec.Reason)
+
+	if len(rec.Commits) > 0 {
+		fmt.Println()
+		fmt.Println("Commits:")
+		for _, c := range rec.Commits {
+			fmt.Printf("  %s %s\n", shortHash(c.Hash), c.Subject)
+		}
+	}
+	if cfgVerbose && len(rec.Types) > 0 {
+		fmt.Println()
+		fmt.Printf("%d commit(s) since %s:\n", rec.Total, current)
+		types := make([]string, 0, len(rec.Types))
+		for typ := range rec.Types {
+			types = append(types, typ)
+		}
+		sort.Strings(types)
+		for _, typ := range types {
+			fmt.Printf("  %-10s %d\n", typ, rec.Types[typ])
+		}
+	}
+}
+
+// shortHash abbreviates a commit hash for display.
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
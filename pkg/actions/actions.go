@@ -2,6 +2,8 @@
 package actions
 
 import (
+	"context"
+
 	"github.com/agentplexus/agent-team-release/pkg/config"
 )
 
@@ -41,12 +43,22 @@ type Action interface {
 
 // Options configures action behavior.
 type Options struct {
-	DryRun      bool           // Don't actually make changes
-	Interactive bool           // Enable interactive mode
-	Version     string         // Target version (for release)
-	Since       string         // Since tag (for changelog)
-	Verbose     bool           // Show detailed output
-	Config      *config.Config // Configuration
+	DryRun      bool            // Don't actually make changes
+	Interactive bool            // Enable interactive mode
+	Version     string          // Target version (for release)
+	Since       string          // Since tag (for changelog)
+	Paths       []string        // Pathspecs the listed commits must touch, from dir (for changelog; all if none)
+	Verbose     bool            // Show detailed output
+	Config      *config.Config  // Configuration
+	Context     context.Context // Cancels external commands (nil = never)
+}
+
+// context returns the context external commands run under.
+func (o Options) context() context.Context {
+	if o.Context == nil {
+		return context.Background()
+	}
+	return o.Context
 }
 
 // DefaultOptions returns the default action options.
@@ -0,0 +1,155 @@
+package actions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/agentplexus/agent-team-release/pkg/detect"
+)
+
+// BumpAction sets the version fields of the project's manifests
+// (package.json, Cargo.toml, pyproject.toml) and Go version.go files to
+// the release version.
+type BumpAction struct{}
+
+// Name returns the action name.
+func (a *BumpAction) Name() string {
+	return "bump"
+}
+
+// Run executes the bump action directly.
+func (a *BumpAction) Run(dir string, opts Options) Result {
+	proposals, err := a.Propose(dir, opts)
+	if err != nil {
+		return Result{
+			Name:    "bump",
+			Success: false,
+			Error:   err,
+			Output:  "Failed to find version fields",
+		}
+	}
+
+	if len(proposals) == 0 {
+		return Result{
+			Name:    "bump",
+			Success: true,
+			Output:  "No version fields need updating",
+		}
+	}
+
+	if opts.DryRun {
+		var output strings.Builder
+		output.WriteString("[Dry run] Would bump versions:\n")
+		for _, p := range proposals {
+			output.WriteString(fmt.Sprintf("  - %s\n", p.Description))
+		}
+		return Result{
+			Name:    "bump",
+			Success: true,
+			Output:  output.String(),
+		}
+	}
+
+	return a.Apply(dir, proposals)
+}
+
+// Propose generates one proposal per file whose version fields differ
+// from the target version.
+func (a *BumpAction) Propose(dir string, opts Options) ([]Proposal, error) {
+	if opts.Version == "" {
+		return nil, fmt.Errorf("version is required")
+	}
+
+	fields, err := detect.VersionFields(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var proposals []Proposal
+	for i := 0; i < len(fields); {
+		// Fields are grouped by file
+		j := i
+		for j < len(fields) && fields[j].File == fields[i].File {
+			j++
+		}
+		p, err := bumpFile(dir, fields[i:j], opts.Version)
+		if err != nil {
+			return nil, err
+		}
+		if p != nil {
+			proposals = append(proposals, *p)
+		}
+		i = j
+	}
+	return proposals, nil
+}
+
+// bumpFile proposes setting the version fields of one file, or returns nil
+// if they are all up to date.
+func bumpFile(dir string, fields []detect.VersionField, version string) (*Proposal, error) {
+	file := fields[0].File
+	content, err := os.ReadFile(filepath.Join(dir, file))
+	if err != nil {
+		return nil, err
+	}
+
+	var sb strings.Builder
+	var changes []string
+	last := 0
+	for _, f := range fields {
+		want := f.Format(version)
+		if f.Value == want {
+			continue
+		}
+		sb.Write(content[last:f.Start])
+		sb.WriteString(want)
+		last = f.End
+		changes = append(changes, fmt.Sprintf("%s %s → %s", f.Key, f.Value, want))
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	sb.Write(content[last:])
+
+	return &Proposal{
+		Description: fmt.Sprintf("Bump %s: %s", file, strings.Join(changes, ", ")),
+		FilePath:    file,
+		OldContent:  string(content),
+		NewContent:  sb.String(),
+		Metadata: map[string]string{
+			"version": version,
+		},
+	}, nil
+}
+
+// Apply applies approved proposals.
+func (a *BumpAction) Apply(dir string, proposals []Proposal) Result {
+	if len(proposals) == 0 {
+		return Result{
+			Name:    "bump",
+			Success: true,
+			Output:  "No proposals to apply",
+		}
+	}
+
+	var output strings.Builder
+	for _, p := range proposals {
+		if err := os.WriteFile(filepath.Join(dir, p.FilePath), []byte(p.NewContent), 0644); err != nil {
+			return Result{
+				Name:    "bump",
+				Success: false,
+				Error:   err,
+				Output:  "Failed to write " + p.FilePath,
+			}
+		}
+		output.WriteString(p.Description + "\n")
+	}
+
+	return Result{
+		Name:    "bump",
+		Success: true,
+		Output:  output.String(),
+	}
+}
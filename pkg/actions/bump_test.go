@@ -0,0 +1,68 @@
+package actions
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBumpAction(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"package.json": "{\n  \"name\": \"app\",\n  \"version\": \"2.2.0\"\n}\n",
+		"Cargo.toml":   "[package]\nname = \"app\"\nversion = \"2.3.0\"\n",
+		"go.mod":       "module example.com/app\n",
+		"version.go":   "package app\n\nconst (\n\tVersion      = \"v2.2.0\"\n\tAPIVersion   = \"2.0.0\"\n\tMinGoVersion = \"1.21.0\"\n)\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	action := &BumpAction{}
+	proposals, err := action.Propose(dir, Options{Version: "v2.3.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Cargo.toml is already at 2.3.0
+	if len(proposals) != 2 {
+		t.Fatalf("Propose() = %+v", proposals)
+	}
+	if proposals[0].FilePath != "package.json" || proposals[1].FilePath != "version.go" {
+		t.Errorf("proposal files = %s, %s", proposals[0].FilePath, proposals[1].FilePath)
+	}
+	if want := "Bump version.go: Version v2.2.0 → v2.3.0"; proposals[1].Description != want {
+		t.Errorf("Description = %q, want %q", proposals[1].Description, want)
+	}
+
+	if result := action.Run(dir, Options{Version: "v2.3.0", DryRun: true}); !result.Success || !strings.Contains(result.Output, "Would bump") {
+		t.Errorf("dry run = %+v", result)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "package.json")); string(data) != files["package.json"] {
+		t.Error("dry run changed package.json")
+	}
+
+	if result := action.Run(dir, Options{Version: "v2.3.0"}); !result.Success {
+		t.Fatalf("Run() = %+v", result)
+	}
+	// Other *Version constants are not the release version
+	want := map[string]string{
+		"package.json": "{\n  \"name\": \"app\",\n  \"version\": \"2.3.0\"\n}\n",
+		"version.go":   "package app\n\nconst (\n\tVersion      = \"v2.3.0\"\n\tAPIVersion   = \"2.0.0\"\n\tMinGoVersion = \"1.21.0\"\n)\n",
+	}
+	for name, content := range want {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != content {
+			t.Errorf("%s =\n%s\nwant\n%s", name, data, content)
+		}
+	}
+
+	if proposals, err := action.Propose(dir, Options{Version: "v2.3.0"}); err != nil || len(proposals) != 0 {
+		t.Errorf("Propose() after bump = %+v, %v", proposals, err)
+	}
+}
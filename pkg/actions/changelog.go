@@ -2,15 +2,33 @@ package actions
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/agentplexus/agent-team-release/pkg/changelog"
+	"github.com/agentplexus/agent-team-release/pkg/git"
+	"github.com/agentplexus/agent-team-release/pkg/proc"
 )
 
-// ChangelogAction generates and updates changelogs using schangelog.
-type ChangelogAction struct{}
+// ChangelogAction validates CHANGELOG.json and renders CHANGELOG.md from
+// it, listing the commits since the latest tag. Given a version that
+// CHANGELOG.json has no entry for, it first drafts one from those commits,
+// linking the issues and pull requests they refer to.
+type ChangelogAction struct {
+	// Classify settles the uncertain classifications of a drafted release
+	// entry in interactive mode, e.g. interactive.ClassifyChanges. Nil keeps
+	// the guesses.
+	Classify func(d *changelog.Draft) error
+
+	// ReleaseNotes also writes the release notes for the version to
+	// changelog.ReleaseNotesPath.
+	ReleaseNotes bool
+}
 
 // Name returns the action name.
 func (a *ChangelogAction) Name() string {
@@ -19,52 +37,62 @@ func (a *ChangelogAction) Name() string {
 
 // Run executes the changelog action directly.
 func (a *ChangelogAction) Run(dir string, opts Options) Result {
-	// Check if schangelog is available
-	if !commandExists("schangelog") {
+	since := a.since(dir, opts)
+
+	var output strings.Builder
+
+	// Step 1: List commits
+	commits, err := a.Commits(dir, since, opts)
+	if err != nil {
 		return Result{
 			Name:    "changelog",
 			Success: false,
-			Error:   fmt.Errorf("schangelog not found in PATH"),
-			Output:  "Install schangelog: go install github.com/grokify/schangelog/cmd/schangelog@latest",
+			Error:   err,
+			Output:  "Could not read commits. Use --since to specify a tag.",
 		}
 	}
+	output.WriteString(describeCommits(since, commits))
 
-	// Determine the since tag
-	since := opts.Since
-	if since == "" {
-		// Try to get the latest tag
-		latestTag, err := getLatestTag(dir)
-		if err != nil {
-			return Result{
-				Name:    "changelog",
-				Success: false,
-				Error:   err,
-				Output:  "Could not determine latest tag. Use --since to specify.",
-			}
+	// Step 2: Check if CHANGELOG.json exists
+	if !fileExists(filepath.Join(dir, changelog.FileName)) {
+		output.WriteString("\nCHANGELOG.json not found. Create it first.\n")
+		return Result{
+			Name:    "changelog",
+			Success: false,
+			Output:  output.String(),
 		}
-		since = latestTag
 	}
 
-	var output strings.Builder
-
-	// Step 1: Parse commits
-	output.WriteString(fmt.Sprintf("Parsing commits since %s...\n", since))
-	parseArgs := []string{"parse-commits", "--since=" + since, "--format=toon"}
-	parseResult := runCommand("parse-commits", dir, "schangelog", parseArgs...)
-	if !parseResult.Success {
+	// Step 3: Draft the release entry if there is none
+	cl, err := loadChangelog(dir)
+	if err != nil {
+		return Result{
+			Name:    "changelog",
+			Success: false,
+			Error:   err,
+			Output:  output.String(),
+		}
+	}
+	draft, err := a.draft(dir, since, cl, commits, opts)
+	if err != nil {
 		return Result{
 			Name:    "changelog",
 			Success: false,
-			Error:   parseResult.Error,
-			Output:  parseResult.Output,
+			Error:   err,
+			Output:  output.String(),
 		}
 	}
-	output.WriteString(parseResult.Output)
-	output.WriteString("\n")
 
 	// If dry run, stop here
 	if opts.DryRun {
-		output.WriteString("\n[Dry run] Would generate changelog from commits above\n")
+		if draft != nil {
+			output.WriteString(fmt.Sprintf("\n[Dry run] Would add %s to CHANGELOG.json: %s\n", draft.Version, draft.Summary()))
+			output.WriteString(describeDraft(draft))
+		}
+		output.WriteString("\n[Dry run] Would regenerate CHANGELOG.md from CHANGELOG.json\n")
+		if a.ReleaseNotes && opts.Version != "" {
+			output.WriteString(fmt.Sprintf("[Dry run] Would write release notes to %s\n", changelog.ReleaseNotesPath(dir, opts.Version)))
+		}
 		return Result{
 			Name:    "changelog",
 			Success: true,
@@ -72,43 +100,65 @@ func (a *ChangelogAction) Run(dir string, opts Options) Result {
 		}
 	}
 
-	// Step 2: Check if CHANGELOG.json exists
-	changelogJSON := filepath.Join(dir, "CHANGELOG.json")
-	if !fileExists(changelogJSON) {
-		output.WriteString("\nCHANGELOG.json not found. Create it first or use schangelog init.\n")
-		return Result{
-			Name:    "changelog",
-			Success: false,
-			Output:  output.String(),
+	if draft != nil {
+		if _, err := draft.AddTo(cl); err != nil {
+			return Result{
+				Name:    "changelog",
+				Success: false,
+				Error:   err,
+				Output:  output.String(),
+			}
+		}
+		if err := cl.Save(filepath.Join(dir, changelog.FileName)); err != nil {
+			return Result{
+				Name:    "changelog",
+				Success: false,
+				Error:   err,
+				Output:  output.String(),
+			}
 		}
+		output.WriteString(fmt.Sprintf("\nAdded %s to CHANGELOG.json: %s\n", draft.Version, draft.Summary()))
+		output.WriteString(describeDraft(draft))
 	}
 
-	// Step 3: Validate existing CHANGELOG.json
+	// Step 4: Validate CHANGELOG.json
 	output.WriteString("\nValidating CHANGELOG.json...\n")
-	validateResult := runCommand("validate", dir, "schangelog", "validate", "CHANGELOG.json")
-	if !validateResult.Success {
+	if err := a.Validate(dir); err != nil {
 		return Result{
 			Name:    "changelog",
 			Success: false,
-			Error:   validateResult.Error,
-			Output:  output.String() + validateResult.Output,
+			Error:   err,
+			Output:  output.String(),
 		}
 	}
 	output.WriteString("CHANGELOG.json is valid\n")
 
-	// Step 4: Generate CHANGELOG.md
+	// Step 5: Generate CHANGELOG.md
 	output.WriteString("\nGenerating CHANGELOG.md...\n")
-	generateResult := runCommand("generate", dir, "schangelog", "generate", "CHANGELOG.json", "-o", "CHANGELOG.md")
-	if !generateResult.Success {
+	if err := a.Generate(dir); err != nil {
 		return Result{
 			Name:    "changelog",
 			Success: false,
-			Error:   generateResult.Error,
-			Output:  output.String() + generateResult.Output,
+			Error:   err,
+			Output:  output.String(),
 		}
 	}
 	output.WriteString("Generated CHANGELOG.md\n")
 
+	// Step 6: Write the release notes
+	if a.ReleaseNotes && opts.Version != "" {
+		path, err := a.WriteReleaseNotes(dir, opts.Version)
+		if err != nil {
+			return Result{
+				Name:    "changelog",
+				Success: false,
+				Error:   err,
+				Output:  output.String(),
+			}
+		}
+		output.WriteString(fmt.Sprintf("Wrote release notes to %s\n", path))
+	}
+
 	return Result{
 		Name:    "changelog",
 		Success: true,
@@ -118,103 +168,283 @@ func (a *ChangelogAction) Run(dir string, opts Options) Result {
 
 // Propose generates proposals for interactive mode.
 func (a *ChangelogAction) Propose(dir string, opts Options) ([]Proposal, error) {
-	// Check if schangelog is available
-	if !commandExists("schangelog") {
-		return nil, fmt.Errorf("schangelog not found in PATH")
+	since := a.since(dir, opts)
+	commits, err := a.Commits(dir, since, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commits: %w", err)
 	}
 
-	// Determine the since tag
-	since := opts.Since
-	if since == "" {
-		latestTag, err := getLatestTag(dir)
+	// Read current CHANGELOG.md if it exists
+	oldContent := ""
+	if content, err := os.ReadFile(filepath.Join(dir, changelog.MarkdownFileName)); err == nil {
+		oldContent = string(content)
+	}
+
+	// Render the new CHANGELOG.md without touching the working tree
+	var proposals []Proposal
+	var cl *changelog.Changelog
+	newContent := oldContent
+	if fileExists(filepath.Join(dir, changelog.FileName)) {
+		cl, err = loadChangelog(dir)
+		if err != nil {
+			return nil, err
+		}
+		draft, err := a.draft(dir, since, cl, commits, opts)
 		if err != nil {
-			return nil, fmt.Errorf("could not determine latest tag: %w", err)
+			return nil, err
+		}
+		if draft != nil {
+			oldJSON, err := cl.Bytes()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := draft.AddTo(cl); err != nil {
+				return nil, err
+			}
+			newJSON, err := cl.Bytes()
+			if err != nil {
+				return nil, err
+			}
+			proposals = append(proposals, Proposal{
+				Description: fmt.Sprintf("Add %s to CHANGELOG.json: %s", draft.Version, draft.Summary()),
+				FilePath:    changelog.FileName,
+				OldContent:  string(oldJSON),
+				NewContent:  string(newJSON),
+				Metadata: map[string]string{
+					"version": draft.Version,
+					"changes": describeDraft(draft),
+				},
+			})
 		}
-		since = latestTag
+		newContent = cl.Markdown()
 	}
 
-	// Parse commits to show what would be included
-	parseArgs := []string{"parse-commits", "--since=" + since, "--format=toon"}
-	parseResult := runCommand("parse-commits", dir, "schangelog", parseArgs...)
-	if !parseResult.Success {
-		return nil, fmt.Errorf("failed to parse commits: %w", parseResult.Error)
-	}
+	proposals = append(proposals, Proposal{
+		Description: fmt.Sprintf("Update changelog with commits since %s", sinceName(since)),
+		FilePath:    changelog.MarkdownFileName,
+		OldContent:  oldContent,
+		NewContent:  newContent,
+		Metadata: map[string]string{
+			"since":   since,
+			"commits": describeCommits(since, commits),
+		},
+	})
 
-	// Read current CHANGELOG.md if it exists
-	changelogMD := filepath.Join(dir, "CHANGELOG.md")
-	oldContent := ""
-	if fileExists(changelogMD) {
-		content, err := os.ReadFile(changelogMD)
-		if err == nil {
-			oldContent = string(content)
+	if a.ReleaseNotes && opts.Version != "" && cl != nil {
+		notes, err := cl.ReleaseNotes(opts.Version)
+		if err != nil {
+			return nil, err
 		}
+		path := changelog.ReleaseNotesPath(dir, opts.Version)
+		old, _ := os.ReadFile(filepath.Join(dir, path))
+		proposals = append(proposals, Proposal{
+			Description: fmt.Sprintf("Write release notes for %s", opts.Version),
+			FilePath:    path,
+			OldContent:  string(old),
+			NewContent:  notes,
+			Metadata:    map[string]string{"version": opts.Version},
+		})
 	}
-
-	return []Proposal{
-		{
-			Description: fmt.Sprintf("Update changelog with commits since %s", since),
-			FilePath:    "CHANGELOG.md",
-			OldContent:  oldContent,
-			NewContent:  "[Will be generated by schangelog]",
-			Metadata: map[string]string{
-				"since":   since,
-				"commits": parseResult.Output,
-			},
-		},
-	}, nil
+	return proposals, nil
 }
 
 // Apply applies approved proposals.
 func (a *ChangelogAction) Apply(dir string, proposals []Proposal) Result {
-	// Run the action to apply changes
-	return a.Run(dir, Options{DryRun: false})
+	var output strings.Builder
+	for _, p := range proposals {
+		path := filepath.Join(dir, p.FilePath)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return Result{
+				Name:    "changelog",
+				Success: false,
+				Error:   err,
+				Output:  "Failed to create the directory of " + p.FilePath,
+			}
+		}
+		if err := os.WriteFile(path, []byte(p.NewContent), 0644); err != nil {
+			return Result{
+				Name:    "changelog",
+				Success: false,
+				Error:   err,
+				Output:  "Failed to write " + p.FilePath,
+			}
+		}
+		output.WriteString(p.Description + "\n")
+	}
+	return Result{
+		Name:    "changelog",
+		Success: true,
+		Output:  output.String(),
+	}
 }
 
-// ParseCommits runs schangelog parse-commits and returns the output.
-func (a *ChangelogAction) ParseCommits(dir string, since string, format string) (string, error) {
-	if !commandExists("schangelog") {
-		return "", fmt.Errorf("schangelog not found in PATH")
-	}
+// Commits returns the commits since a tag touching opts.Paths, newest
+// first, with the files they change, leaving out merge commits. An empty
+// since returns the whole history.
+func (a *ChangelogAction) Commits(dir, since string, opts Options) ([]git.Commit, error) {
+	return git.New(dir).WithContext(opts.context()).Commits(since, "HEAD", git.LogOptions{NoMerges: true, Files: true, Paths: opts.Paths})
+}
 
-	if format == "" {
-		format = "toon"
+// Draft classifies commits into a release entry for version, dated today,
+// leaving out commits the changelog already credits. References to issues
+// and pull requests are linked to repo, if known.
+func (a *ChangelogAction) Draft(cl *changelog.Changelog, version string, commits []git.Commit, repo *git.Repository) *changelog.Draft {
+	var fresh []git.Commit
+	for _, c := range commits {
+		if !cl.HasCommit(c.Hash) {
+			fresh = append(fresh, c)
+		}
 	}
+	return changelog.NewDraft(version, time.Now().Format(changelog.DateFormat), fresh, repo)
+}
 
-	args := []string{"parse-commits", "--since=" + since, "--format=" + format}
-	result := runCommand("parse-commits", dir, "schangelog", args...)
-	if !result.Success {
-		return "", result.Error
+// Repository returns the repository issue and pull request references link
+// to: the remote's, else the one CHANGELOG.json names, else nil.
+func (a *ChangelogAction) Repository(dir string, cl *changelog.Changelog, opts Options) *git.Repository {
+	if repo, err := git.New(dir).WithContext(opts.context()).Repository(); err == nil {
+		return repo
+	}
+	if repo, err := git.ParseRepositoryURL(cl.Repository); err == nil {
+		return repo
 	}
+	return nil
+}
 
-	return result.Output, nil
+// draft drafts the release entry for opts.Version from the commits since a
+// tag, crediting their contributors, and settles uncertain classifications
+// with Classify in interactive mode outside dry runs. It returns nil if no
+// version is given or the changelog already has an entry for it.
+func (a *ChangelogAction) draft(dir, since string, cl *changelog.Changelog, commits []git.Commit, opts Options) (*changelog.Draft, error) {
+	if opts.Version == "" || cl.Release(opts.Version) != nil {
+		return nil, nil
+	}
+	d := a.Draft(cl, opts.Version, commits, a.Repository(dir, cl, opts))
+	contributors, err := git.New(dir).WithContext(opts.context()).Contributors(since, "HEAD", opts.Paths...)
+	if err != nil {
+		return nil, fmt.Errorf("listing contributors: %w", err)
+	}
+	d.Contributors = contributors
+	if opts.Interactive && !opts.DryRun && a.Classify != nil {
+		if err := a.Classify(d); err != nil {
+			return nil, fmt.Errorf("classifying changes: %w", err)
+		}
+	}
+	return d, nil
 }
 
-// Generate runs schangelog generate to create CHANGELOG.md.
+// Generate renders CHANGELOG.md from CHANGELOG.json.
 func (a *ChangelogAction) Generate(dir string) error {
-	if !commandExists("schangelog") {
-		return fmt.Errorf("schangelog not found in PATH")
+	cl, err := loadChangelog(dir)
+	if err != nil {
+		return err
 	}
+	return os.WriteFile(filepath.Join(dir, changelog.MarkdownFileName), []byte(cl.Markdown()), 0644)
+}
 
-	result := runCommand("generate", dir, "schangelog", "generate", "CHANGELOG.json", "-o", "CHANGELOG.md")
-	if !result.Success {
-		return result.Error
+// WriteReleaseNotes renders the release notes for version from
+// CHANGELOG.json and writes them to changelog.ReleaseNotesPath, which it
+// returns.
+func (a *ChangelogAction) WriteReleaseNotes(dir, version string) (string, error) {
+	cl, err := loadChangelog(dir)
+	if err != nil {
+		return "", err
+	}
+	notes, err := cl.ReleaseNotes(version)
+	if err != nil {
+		return "", err
+	}
+	path := changelog.ReleaseNotesPath(dir, version)
+	if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), 0755); err != nil {
+		return "", err
 	}
+	return path, os.WriteFile(filepath.Join(dir, path), []byte(notes), 0644)
+}
 
+// Validate checks CHANGELOG.json against the changelog schema.
+func (a *ChangelogAction) Validate(dir string) error {
+	cl, err := loadChangelog(dir)
+	if err != nil {
+		return err
+	}
+	if err := cl.Validate(); err != nil {
+		return fmt.Errorf("validation failed:\n%w", err)
+	}
 	return nil
 }
 
-// Validate runs schangelog validate on CHANGELOG.json.
-func (a *ChangelogAction) Validate(dir string) error {
-	if !commandExists("schangelog") {
-		return fmt.Errorf("schangelog not found in PATH")
+// since returns the tag to list commits from: opts.Since, else the latest
+// tag, else "" for the whole history.
+func (a *ChangelogAction) since(dir string, opts Options) string {
+	if opts.Since != "" {
+		return opts.Since
+	}
+	latestTag, err := getLatestTag(opts.context(), dir)
+	if err != nil {
+		return ""
 	}
+	return latestTag
+}
 
-	result := runCommand("validate", dir, "schangelog", "validate", "CHANGELOG.json")
-	if !result.Success {
-		return fmt.Errorf("validation failed: %s", result.Output)
+// loadChangelog loads the CHANGELOG.json in dir.
+func loadChangelog(dir string) (*changelog.Changelog, error) {
+	cl, err := changelog.Load(filepath.Join(dir, changelog.FileName))
+	if err != nil {
+		return nil, fmt.Errorf("loading %s: %w", changelog.FileName, err)
 	}
+	return cl, nil
+}
 
-	return nil
+// describeCommits lists commits by short hash and subject.
+func describeCommits(since string, commits []git.Commit) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d commit(s) since %s\n", len(commits), sinceName(since)))
+	for _, c := range commits {
+		sb.WriteString(fmt.Sprintf("  %s %s\n", c.ShortHash(), c.Subject))
+	}
+	return sb.String()
+}
+
+// describeDraft lists the drafted changes by category, then the commits
+// left out and the contributors.
+func describeDraft(d *changelog.Draft) string {
+	var sb strings.Builder
+	cats := changelog.DraftCategories[:len(changelog.DraftCategories):len(changelog.DraftCategories)]
+	for _, c := range append(cats, "") {
+		for _, item := range d.Items {
+			if item.Category != c {
+				continue
+			}
+			name := string(c)
+			if name == "" {
+				name = "omitted"
+			}
+			sb.WriteString(fmt.Sprintf("  %-10s %s %s", name, item.Commit.ShortHash(), item.Entry.Description))
+			if !item.Certain {
+				sb.WriteString(fmt.Sprintf(" (uncertain: %s)", item.Reason))
+			}
+			sb.WriteString("\n")
+		}
+	}
+	var names []string
+	for _, p := range d.Contributors {
+		if p.FirstTime {
+			names = append(names, p.Name+" (first contribution)")
+		} else {
+			names = append(names, p.Name)
+		}
+	}
+	if len(names) > 0 {
+		sb.WriteString("  contributors: " + strings.Join(names, ", ") + "\n")
+	}
+	return sb.String()
+}
+
+// sinceName names the start of a commit range in messages.
+func sinceName(since string) string {
+	if since == "" {
+		return "the first commit"
+	}
+	return since
 }
 
 // Helper functions
@@ -229,8 +459,8 @@ func fileExists(path string) bool {
 	return err == nil
 }
 
-func runCommand(name string, dir string, command string, args ...string) Result {
-	cmd := exec.Command(command, args...)
+func runCommand(ctx context.Context, name string, dir string, command string, args ...string) Result {
+	cmd := proc.Command(ctx, command, args...)
 	cmd.Dir = dir
 
 	var stdout, stderr bytes.Buffer
@@ -255,8 +485,41 @@ func runCommand(name string, dir string, command string, args ...string) Result
 	}
 }
 
-func getLatestTag(dir string) (string, error) {
-	cmd := exec.Command("git", "describe", "--tags", "--abbrev=0")
+// outputPlaceholder marks the output path argument for renderToTemp.
+const outputPlaceholder = "{output}"
+
+// renderToTemp runs a generator with its output redirected to a temporary
+// file, replacing outputPlaceholder in args, and returns what it wrote.
+func renderToTemp(ctx context.Context, dir string, command string, args ...string) (string, error) {
+	tmpDir, err := os.MkdirTemp("", "atrelease-render-*")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	out := filepath.Join(tmpDir, "output")
+	cmdArgs := make([]string, len(args))
+	for i, arg := range args {
+		if arg == outputPlaceholder {
+			arg = out
+		}
+		cmdArgs[i] = arg
+	}
+
+	result := runCommand(ctx, "render", dir, command, cmdArgs...)
+	if !result.Success {
+		return "", fmt.Errorf("%w: %s", result.Error, result.Output)
+	}
+
+	content, err := os.ReadFile(out)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+func getLatestTag(ctx context.Context, dir string) (string, error) {
+	cmd := proc.Command(ctx, "git", "describe", "--tags", "--abbrev=0")
 	cmd.Dir = dir
 
 	output, err := cmd.Output()
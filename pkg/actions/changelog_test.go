@@ -0,0 +1,161 @@
+package actions
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/agent-team-release/pkg/changelog"
+)
+
+func TestChangelogAction(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test User")
+	valid := `{
+  "project": "demo",
+  "repository": "https://github.com/acme/demo",
+  "releases": [
+    {
+      "version": "v1.0.0",
+      "date": "2026-01-01",
+      "added": [
+        { "description": "First release", "commit": "abc1234" }
+      ]
+    }
+  ]
+}
+`
+	write("CHANGELOG.json", valid)
+	git("add", ".")
+	git("commit", "-q", "-m", "chore: init")
+	git("tag", "v1.0.0")
+	write("app.txt", "v2\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "feat: streaming")
+
+	action := &ChangelogAction{}
+	result := action.Run(dir, Options{DryRun: true})
+	if !result.Success || !strings.Contains(result.Output, "1 commit(s) since v1.0.0") || !strings.Contains(result.Output, "feat: streaming") {
+		t.Errorf("Run(dry run) = %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "CHANGELOG.md")); !os.IsNotExist(err) {
+		t.Error("dry run wrote CHANGELOG.md")
+	}
+
+	proposals, err := action.Propose(dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proposals) != 1 || !strings.Contains(proposals[0].NewContent, "- First release ([`abc1234`](https://github.com/acme/demo/commit/abc1234))") {
+		t.Fatalf("Propose() = %+v", proposals)
+	}
+
+	if result := action.Run(dir, Options{}); !result.Success {
+		t.Fatalf("Run() = %+v", result)
+	}
+	md, err := os.ReadFile(filepath.Join(dir, "CHANGELOG.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(md) != proposals[0].NewContent {
+		t.Errorf("CHANGELOG.md =\n%s\nwant\n%s", md, proposals[0].NewContent)
+	}
+
+	// An invalid changelog fails validation
+	write("CHANGELOG.json", `{"releases": [{"version": "v1.0.0", "date": "January"}]}`)
+	if result := action.Run(dir, Options{}); result.Success || result.Error == nil || !strings.Contains(result.Error.Error(), "invalid date") {
+		t.Errorf("Run(invalid) = %+v", result)
+	}
+
+	// A version without an entry is drafted from the commits
+	write("CHANGELOG.json", valid)
+	write("lib.go", "package lib\n")
+	git("add", "lib.go")
+	git("commit", "-q", "-m", "refactor: tidy lib", "-m", "Closes #5")
+	git("remote", "add", "origin", "git@gitlab.com:acme/demo.git")
+	action.ReleaseNotes = true
+
+	var asked int
+	action.Classify = func(d *changelog.Draft) error {
+		for _, item := range d.Uncertain() {
+			asked++
+			item.Category = changelog.Changed
+			item.Certain = true
+		}
+		return nil
+	}
+	opts := Options{Version: "v1.1.0", Interactive: true}
+
+	dryRun := opts
+	dryRun.DryRun = true
+	if result := action.Run(dir, dryRun); !result.Success || !strings.Contains(result.Output, "Would add v1.1.0 to CHANGELOG.json: 1 added (1 commit(s) left out)") || asked != 0 {
+		t.Errorf("Run(dry run, version) = %+v, asked %d", result, asked)
+	}
+
+	proposals, err = action.Propose(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proposals) != 3 || proposals[0].FilePath != "CHANGELOG.json" || !strings.Contains(proposals[1].NewContent, "## [v1.1.0]") ||
+		proposals[2].FilePath != "RELEASE_NOTES_v1.1.0.md" {
+		t.Fatalf("Propose(version) = %+v", proposals)
+	}
+
+	asked = 0
+	if result := action.Run(dir, opts); !result.Success || asked != 1 {
+		t.Fatalf("Run(version) = %+v, asked %d", result, asked)
+	}
+	cl, err := changelog.Load(filepath.Join(dir, "CHANGELOG.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := cl.Release("v1.1.0")
+	if r == nil || len(r.Entries(changelog.Added)) != 1 || r.Entries(changelog.Added)[0].Description != "Streaming" || len(r.Entries(changelog.Changed)) != 1 {
+		t.Fatalf("drafted release = %+v", r)
+	}
+	wantRefs := []changelog.Reference{{ID: "#5", URL: "https://gitlab.com/acme/demo/-/issues/5"}}
+	if refs := r.Entries(changelog.Changed)[0].References; !reflect.DeepEqual(refs, wantRefs) {
+		t.Errorf("references = %+v, want %+v", refs, wantRefs)
+	}
+	notes, err := os.ReadFile(filepath.Join(dir, "RELEASE_NOTES_v1.1.0.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []changelog.Contributor{{Name: "Test User"}}; !reflect.DeepEqual(r.Contributors, want) {
+		t.Errorf("contributors = %+v, want %+v", r.Contributors, want)
+	}
+	if !strings.Contains(string(notes), "- Tidy lib ([#5](https://gitlab.com/acme/demo/-/issues/5))") ||
+		!strings.Contains(string(notes), "### Acknowledgements\n\nThanks to everyone who contributed to this release:\n\n- Test User\n") {
+		t.Errorf("release notes =\n%s", notes)
+	}
+
+	// Once the entry exists, nothing more is drafted
+	asked = 0
+	if proposals, err := action.Propose(dir, opts); err != nil || len(proposals) != 2 || asked != 0 {
+		t.Errorf("Propose(existing version) = %+v, %v", proposals, err)
+	}
+}
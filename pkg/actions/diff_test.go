@@ -0,0 +1,70 @@
+package actions
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	old := "# Changelog\n\n## v1.0.0\n\n- Initial release\n"
+	new := "# Changelog\n\n## v1.1.0\n\n- Add feature\n\n## v1.0.0\n\n- Initial release\n"
+
+	got := UnifiedDiff("CHANGELOG.md", old, new)
+	want := `--- a/CHANGELOG.md
++++ b/CHANGELOG.md
+@@ -1,5 +1,9 @@
+ # Changelog
+ 
++## v1.1.0
++
++- Add feature
++
+ ## v1.0.0
+ 
+ - Initial release
+`
+	if got != want {
+		t.Errorf("UnifiedDiff() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnifiedDiff_SeparateHunks(t *testing.T) {
+	var oldLines, newLines []string
+	for i := 0; i < 30; i++ {
+		line := string(rune('a' + i%26))
+		oldLines = append(oldLines, line)
+		switch i {
+		case 2:
+			newLines = append(newLines, "changed-2")
+		case 25:
+			newLines = append(newLines, "changed-25")
+		default:
+			newLines = append(newLines, line)
+		}
+	}
+
+	got := UnifiedDiff("f.txt", strings.Join(oldLines, "\n")+"\n", strings.Join(newLines, "\n")+"\n")
+	if n := strings.Count(got, "@@ -"); n != 2 {
+		t.Fatalf("expected 2 hunks, got %d:\n%s", n, got)
+	}
+	if !strings.Contains(got, "@@ -1,6 +1,6 @@") || !strings.Contains(got, "@@ -23,7 +23,7 @@") {
+		t.Errorf("unexpected hunk headers:\n%s", got)
+	}
+}
+
+func TestUnifiedDiff_NewFile(t *testing.T) {
+	got := UnifiedDiff("NOTES.md", "", "hello\n")
+	want := "--- /dev/null\n+++ b/NOTES.md\n@@ -0,0 +1 @@\n+hello\n"
+	if got != want {
+		t.Errorf("UnifiedDiff() = %q, want %q", got, want)
+	}
+}
+
+func TestUnifiedDiff_Unchanged(t *testing.T) {
+	if got := UnifiedDiff("f", "same\n", "same\n"); got != "" {
+		t.Errorf("UnifiedDiff() = %q, want empty", got)
+	}
+	if got := (Proposal{OldContent: "a", NewContent: "b"}).Diff(); got != "" {
+		t.Errorf("Diff() without file path = %q, want empty", got)
+	}
+}
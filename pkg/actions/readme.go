@@ -80,7 +80,7 @@ func (a *ReadmeAction) Run(dir string, opts Options) Result {
 			args = append(args, "-exclude", excludeArg)
 		}
 
-		result := runCommand("gocoverbadge", dir, "gocoverbadge", args...)
+		result := runCommand(opts.context(), "gocoverbadge", dir, "gocoverbadge", args...)
 		if result.Success {
 			changes = append(changes, "Updated coverage badge")
 			output.WriteString(result.Output + "\n")
@@ -173,8 +173,9 @@ func (a *ReadmeAction) Propose(dir string, opts Options) ([]Proposal, error) {
 		description.WriteString("\n  - Update coverage badge")
 	}
 
+	// Nothing to propose
 	if newContent == oldContent && !commandExists("gocoverbadge") {
-		return nil, fmt.Errorf("no changes to propose")
+		return nil, nil
 	}
 
 	return []Proposal{
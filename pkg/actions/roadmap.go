@@ -1,10 +1,14 @@
 package actions
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/agentplexus/agent-team-release/pkg/roadmap"
 )
 
 // RoadmapAction generates and updates roadmaps using sroadmap.
@@ -29,20 +33,27 @@ func (a *RoadmapAction) Run(dir string, opts Options) Result {
 
 	var output strings.Builder
 
-	// Check if ROADMAP.json exists
-	roadmapJSON := filepath.Join(dir, "ROADMAP.json")
-	if !fileExists(roadmapJSON) {
+	rm, err := loadRoadmap(dir)
+	if err != nil {
 		return Result{
 			Name:    "roadmap",
 			Success: false,
-			Error:   fmt.Errorf("ROADMAP.json not found"),
-			Output:  "ROADMAP.json not found. Create it first.",
+			Error:   err,
+			Output:  roadmapHint(err),
 		}
 	}
 
 	// Step 1: Validate ROADMAP.json
 	output.WriteString("Validating ROADMAP.json...\n")
-	validateResult := runCommand("validate", dir, "sroadmap", "validate", "ROADMAP.json")
+	if err := rm.Validate(); err != nil {
+		return Result{
+			Name:    "roadmap",
+			Success: false,
+			Error:   fmt.Errorf("invalid ROADMAP.json"),
+			Output:  output.String() + err.Error(),
+		}
+	}
+	validateResult := runCommand(opts.context(), "validate", dir, "sroadmap", "validate", "ROADMAP.json")
 	if !validateResult.Success {
 		return Result{
 			Name:    "roadmap",
@@ -52,13 +63,15 @@ func (a *RoadmapAction) Run(dir string, opts Options) Result {
 		}
 	}
 	output.WriteString("ROADMAP.json is valid\n")
+	if opts.Version != "" {
+		output.WriteString("\n" + describeOpen(rm, opts.Version))
+	}
 
 	// If dry run, show stats and stop
 	if opts.DryRun {
 		output.WriteString("\nRoadmap statistics:\n")
-		statsResult := runCommand("stats", dir, "sroadmap", "stats", "ROADMAP.json")
-		output.WriteString(statsResult.Output)
-		output.WriteString("\n\n[Dry run] Would generate ROADMAP.md\n")
+		output.WriteString(rm.Summary())
+		output.WriteString("\n[Dry run] Would generate ROADMAP.md\n")
 		return Result{
 			Name:    "roadmap",
 			Success: true,
@@ -68,7 +81,7 @@ func (a *RoadmapAction) Run(dir string, opts Options) Result {
 
 	// Step 2: Generate ROADMAP.md
 	output.WriteString("\nGenerating ROADMAP.md...\n")
-	generateResult := runCommand("generate", dir, "sroadmap", "generate", "-i", "ROADMAP.json", "-o", "ROADMAP.md")
+	generateResult := runCommand(opts.context(), "generate", dir, "sroadmap", "generate", "-i", "ROADMAP.json", "-o", "ROADMAP.md")
 	if !generateResult.Success {
 		return Result{
 			Name:    "roadmap",
@@ -93,15 +106,11 @@ func (a *RoadmapAction) Propose(dir string, opts Options) ([]Proposal, error) {
 		return nil, fmt.Errorf("sroadmap not found in PATH")
 	}
 
-	// Check if ROADMAP.json exists
-	roadmapJSON := filepath.Join(dir, "ROADMAP.json")
-	if !fileExists(roadmapJSON) {
-		return nil, fmt.Errorf("ROADMAP.json not found")
+	rm, err := loadRoadmap(dir)
+	if err != nil {
+		return nil, err
 	}
 
-	// Get stats to show what will be included
-	statsResult := runCommand("stats", dir, "sroadmap", "stats", "ROADMAP.json")
-
 	// Read current ROADMAP.md if it exists
 	roadmapMD := filepath.Join(dir, "ROADMAP.md")
 	oldContent := ""
@@ -112,15 +121,19 @@ func (a *RoadmapAction) Propose(dir string, opts Options) ([]Proposal, error) {
 		}
 	}
 
+	// Render the new ROADMAP.md without touching the working tree
+	newContent, err := renderToTemp(opts.context(), dir, "sroadmap", "generate", "-i", "ROADMAP.json", "-o", outputPlaceholder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render ROADMAP.md: %w", err)
+	}
+
 	return []Proposal{
 		{
 			Description: "Regenerate ROADMAP.md from ROADMAP.json",
 			FilePath:    "ROADMAP.md",
 			OldContent:  oldContent,
-			NewContent:  "[Will be generated by sroadmap]",
-			Metadata: map[string]string{
-				"stats": statsResult.Output,
-			},
+			NewContent:  newContent,
+			Metadata:    proposalMetadata(rm, opts.Version),
 		},
 	}, nil
 }
@@ -131,13 +144,22 @@ func (a *RoadmapAction) Apply(dir string, proposals []Proposal) Result {
 	return a.Run(dir, Options{DryRun: false})
 }
 
-// Validate runs sroadmap validate on ROADMAP.json.
+// Validate checks ROADMAP.json against the roadmap model (see
+// roadmap.Roadmap.Validate), then runs sroadmap validate on it.
 func (a *RoadmapAction) Validate(dir string) error {
 	if !commandExists("sroadmap") {
 		return fmt.Errorf("sroadmap not found in PATH")
 	}
 
-	result := runCommand("validate", dir, "sroadmap", "validate", "ROADMAP.json")
+	rm, err := loadRoadmap(dir)
+	if err != nil {
+		return err
+	}
+	if err := rm.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+
+	result := runCommand(context.Background(), "validate", dir, "sroadmap", "validate", "ROADMAP.json")
 	if !result.Success {
 		return fmt.Errorf("validation failed: %s", result.Output)
 	}
@@ -151,7 +173,7 @@ func (a *RoadmapAction) Generate(dir string) error {
 		return fmt.Errorf("sroadmap not found in PATH")
 	}
 
-	result := runCommand("generate", dir, "sroadmap", "generate", "-i", "ROADMAP.json", "-o", "ROADMAP.md")
+	result := runCommand(context.Background(), "generate", dir, "sroadmap", "generate", "-i", "ROADMAP.json", "-o", "ROADMAP.md")
 	if !result.Success {
 		return result.Error
 	}
@@ -159,16 +181,69 @@ func (a *RoadmapAction) Generate(dir string) error {
 	return nil
 }
 
-// Stats returns roadmap statistics.
+// Stats returns roadmap statistics: items by status and the progress of
+// each targeted version.
 func (a *RoadmapAction) Stats(dir string) (string, error) {
-	if !commandExists("sroadmap") {
-		return "", fmt.Errorf("sroadmap not found in PATH")
+	rm, err := loadRoadmap(dir)
+	if err != nil {
+		return "", err
+	}
+	return rm.Summary(), nil
+}
+
+// Open returns the items ROADMAP.json targets at version that are not yet
+// completed.
+func (a *RoadmapAction) Open(dir, version string) ([]roadmap.Item, error) {
+	rm, err := loadRoadmap(dir)
+	if err != nil {
+		return nil, err
 	}
+	return rm.Open(version), nil
+}
 
-	result := runCommand("stats", dir, "sroadmap", "stats", "ROADMAP.json")
-	if !result.Success {
-		return "", result.Error
+// errNoRoadmap is returned when the directory has no ROADMAP.json.
+var errNoRoadmap = errors.New("ROADMAP.json not found")
+
+// loadRoadmap reads ROADMAP.json from dir.
+func loadRoadmap(dir string) (*roadmap.Roadmap, error) {
+	rm, err := roadmap.Load(filepath.Join(dir, roadmap.FileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errNoRoadmap
 	}
+	return rm, err
+}
 
-	return result.Output, nil
+// roadmapHint explains how to fix a ROADMAP.json that couldn't be loaded.
+func roadmapHint(err error) string {
+	if errors.Is(err, errNoRoadmap) {
+		return "ROADMAP.json not found. Create it first."
+	}
+	return "Fix ROADMAP.json and try again."
+}
+
+// describeOpen reports the progress of the items targeted at version,
+// listing those still open.
+func describeOpen(rm *roadmap.Roadmap, version string) string {
+	targeted, open := rm.Targeting(version), rm.Open(version)
+	if len(targeted) == 0 {
+		return fmt.Sprintf("No roadmap items tagged for %s\n", version)
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Roadmap items for %s: %d/%d completed\n", version, len(targeted)-len(open), len(targeted)))
+	for _, item := range open {
+		sb.WriteString(fmt.Sprintf("  - %s: %s (%s)\n", item.ID, item.Title, item.Status))
+	}
+	return sb.String()
+}
+
+// proposalMetadata summarizes the roadmap for the regenerate proposal, with
+// the open items of version if given.
+func proposalMetadata(rm *roadmap.Roadmap, version string) map[string]string {
+	metadata := map[string]string{
+		"stats": rm.Summary(),
+	}
+	if version != "" {
+		metadata["open"] = describeOpen(rm, version)
+	}
+	return metadata
 }
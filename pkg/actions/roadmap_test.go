@@ -0,0 +1,50 @@
+package actions
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRoadmapAction(t *testing.T) {
+	dir := t.TempDir()
+	action := &RoadmapAction{}
+	if _, err := action.Stats(dir); err == nil || err.Error() != "ROADMAP.json not found" {
+		t.Errorf("Stats() without ROADMAP.json = %v", err)
+	}
+
+	roadmap := `{
+  "items": [
+    {"id": "python", "title": "Python checks", "status": "planned", "version": "0.2.0"},
+    {"id": "config", "title": "Config file", "status": "completed", "version": "0.2.0"}
+  ]
+}`
+	if err := os.WriteFile(filepath.Join(dir, "ROADMAP.json"), []byte(roadmap), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := action.Stats(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "2 items, 1 completed, 1 planned\n  v0.2.0: 1/2 completed (1 open)\n"; stats != want {
+		t.Errorf("Stats() = %q, want %q", stats, want)
+	}
+
+	open, err := action.Open(dir, "v0.2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(open) != 1 || open[0].ID != "python" {
+		t.Errorf("Open() = %+v", open)
+	}
+	rm, err := loadRoadmap(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := describeOpen(rm, "v0.2.0")
+	if !strings.Contains(report, "Roadmap items for v0.2.0: 1/2 completed") || !strings.Contains(report, "- python: Python checks (planned)") {
+		t.Errorf("describeOpen() = %q", report)
+	}
+}
@@ -0,0 +1,224 @@
+// Package changelog reads and edits release entries in CHANGELOG.json,
+// leaving the rest of the file's formatting untouched.
+package changelog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FileName is the name of the structured changelog.
+const FileName = "CHANGELOG.json"
+
+// ErrReleaseExists is returned when inserting a release that is already present.
+var ErrReleaseExists = errors.New("release already exists")
+
+// span locates a release entry within the document.
+type span struct {
+	start, end int // byte offsets of the entry's JSON value
+	version    string
+}
+
+// releases locates the "releases" array and its entries. open is the offset
+// just past the array's opening bracket.
+func releases(data []byte) (open int, spans []span, err error) {
+	open, _, spans, err = releasesArray(data)
+	return open, spans, err
+}
+
+// releasesArray locates the "releases" array and its entries. open is the
+// offset just past the array's opening bracket and close the offset of its
+// closing bracket.
+func releasesArray(data []byte) (open, close int, spans []span, err error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return 0, 0, nil, fmt.Errorf("%s must contain a JSON object", FileName)
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return 0, 0, nil, fmt.Errorf("parsing %s: %w", FileName, err)
+		}
+		if key, _ := tok.(string); key != "releases" {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return 0, 0, nil, fmt.Errorf("parsing %s: %w", FileName, err)
+			}
+			continue
+		}
+
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+			return 0, 0, nil, fmt.Errorf("%s: releases must be an array", FileName)
+		}
+		open = int(dec.InputOffset())
+		for dec.More() {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return 0, 0, nil, fmt.Errorf("parsing %s: %w", FileName, err)
+			}
+			var entry struct {
+				Version string `json:"version"`
+			}
+			if err := json.Unmarshal(raw, &entry); err != nil {
+				return 0, 0, nil, fmt.Errorf("%s: release entries must be objects: %w", FileName, err)
+			}
+			end := int(dec.InputOffset())
+			spans = append(spans, span{start: end - len(raw), end: end, version: entry.Version})
+		}
+		if _, err := dec.Token(); err != nil {
+			return 0, 0, nil, fmt.Errorf("parsing %s: %w", FileName, err)
+		}
+		return open, int(dec.InputOffset()) - 1, spans, nil
+	}
+
+	return 0, 0, nil, fmt.Errorf("%s has no releases array", FileName)
+}
+
+// sameVersion reports whether two version strings name the same release,
+// ignoring a leading "v".
+func sameVersion(a, b string) bool {
+	return strings.TrimPrefix(a, "v") == strings.TrimPrefix(b, "v")
+}
+
+// separator returns the text between array entries: a comma and newline
+// with the entries' indentation if they start on their own lines, or ", "
+// if they are inline.
+func separator(data []byte, start int) string {
+	lineStart := bytes.LastIndexByte(data[:start], '\n') + 1
+	indent := data[lineStart:start]
+	if lineStart == 0 || len(bytes.TrimLeft(indent, " \t")) > 0 {
+		return ", "
+	}
+	return ",\n" + string(indent)
+}
+
+// layout is how a document lays out its release entries.
+type layout struct {
+	inline        bool   // Release entries are written on one line
+	prefix        string // Indentation of the release entries
+	unit          string // Indentation step within an entry
+	inlineEntries bool   // Change entries are written on one line, e.g. { "description": "..." }
+}
+
+// inlineEntryRegex matches a change entry written on one line.
+var inlineEntryRegex = regexp.MustCompile(`(?m)^[ \t]*\{[^\n]*\},?[ \t]*$`)
+
+// layoutOf returns the layout of the release entries in data, defaulting
+// to two-space indentation if there are none.
+func layoutOf(data []byte, spans []span) layout {
+	l := layout{prefix: "    ", unit: "  "}
+	if len(spans) == 0 {
+		return l
+	}
+
+	first := spans[0]
+	lineStart := bytes.LastIndexByte(data[:first.start], '\n') + 1
+	indent := data[lineStart:first.start]
+	if lineStart == 0 || len(bytes.TrimLeft(indent, " \t")) > 0 {
+		l.inline = true
+		return l
+	}
+	l.prefix = string(indent)
+
+	// The entry's first field sets the indentation step
+	body := data[first.start+1 : first.end]
+	if nl := bytes.IndexByte(body, '\n'); nl >= 0 {
+		line := body[nl+1:]
+		fieldIndent := line[:len(line)-len(bytes.TrimLeft(line, " \t"))]
+		if len(fieldIndent) > len(l.prefix) {
+			l.unit = string(fieldIndent[len(l.prefix):])
+		}
+	}
+
+	for _, s := range spans {
+		if inlineEntryRegex.Match(data[s.start+1 : s.end-1]) {
+			l.inlineEntries = true
+			break
+		}
+	}
+	return l
+}
+
+// object is a JSON object that keeps its keys in order.
+type object struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+// parseObject decodes a JSON object, keeping its key order.
+func parseObject(data []byte) (*object, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, errors.New("release entries must be objects")
+	}
+	o := &object{values: make(map[string]json.RawMessage)}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		if _, ok := o.values[key]; !ok {
+			o.keys = append(o.keys, key)
+		}
+		o.values[key] = value
+	}
+	return o, nil
+}
+
+// set sets a key to a string value, appending the key if it is new.
+func (o *object) set(key, value string) {
+	raw, _ := json.Marshal(value)
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = raw
+}
+
+// merge adds other's fields to o: arrays are concatenated and other
+// values are replaced.
+func (o *object) merge(other *object) {
+	for _, key := range other.keys {
+		value := other.values[key]
+		current, ok := o.values[key]
+		if !ok {
+			o.keys = append(o.keys, key)
+			o.values[key] = value
+			continue
+		}
+		var a, b []json.RawMessage
+		if json.Unmarshal(current, &a) == nil && json.Unmarshal(value, &b) == nil {
+			value, _ = json.Marshal(append(a, b...))
+		}
+		o.values[key] = value
+	}
+}
+
+// MarshalJSON encodes the object with its keys in order.
+func (o *object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(o.values[key])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,222 @@
+package changelog
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const sample = `{
+  "ir_version": "1.0",
+  "project": "demo",
+  "releases": [
+    {
+      "version": "v1.6.0",
+      "date": "2026-03-01",
+      "added": [
+        { "description": "New thing", "commit": "aaa1111" }
+      ]
+    },
+    {
+      "version": "v1.4.2",
+      "date": "2026-01-10",
+      "fixed": [
+        { "description": "Old fix", "commit": "bbb2222" }
+      ]
+    }
+  ]
+}
+`
+
+// versions returns the versions of a changelog's release entries, in order.
+func versions(c *Changelog) []string {
+	var vs []string
+	for _, r := range c.Releases {
+		vs = append(vs, r.Version)
+	}
+	return vs
+}
+
+func TestChangelog_InsertRelease(t *testing.T) {
+	c, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	branch, err := Parse([]byte(`{"releases": [{"version": "v1.4.3", "date": "2026-03-05", "fixed": [{"description": "Backported fix", "commit": "ccc3333"}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.InsertRelease(branch.Release("v1.4.3")); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := c.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The copy is laid out like the other entries, which are unchanged
+	entry := `{
+      "version": "v1.4.3",
+      "date": "2026-03-05",
+      "fixed": [
+        { "description": "Backported fix", "commit": "ccc3333" }
+      ]
+    }`
+	want := sample[:len(sample)-len(tail)] + entry + ",\n    " + tail
+	if string(out) != want {
+		t.Errorf("Bytes() =\n%s\nwant\n%s", out, want)
+	}
+
+	if err := c.InsertRelease(branch.Release("v1.4.3")); !errors.Is(err, ErrReleaseExists) {
+		t.Errorf("duplicate release error = %v, want ErrReleaseExists", err)
+	}
+	if err := c.InsertRelease(&Release{Date: "2026-01-01"}); err == nil {
+		t.Error("expected error for entry without version")
+	}
+}
+
+// tail is the sample from the v1.4.2 entry on.
+const tail = `{
+      "version": "v1.4.2",
+      "date": "2026-01-10",
+      "fixed": [
+        { "description": "Old fix", "commit": "bbb2222" }
+      ]
+    }
+  ]
+}
+`
+
+func TestChangelog_InsertReleasePositions(t *testing.T) {
+	doc := `{"releases": [{"version": "Unreleased"}, {"version": "v2.0.0"}, {"version": "v1.0.0"}]}`
+
+	tests := []struct {
+		version string
+		want    []string
+	}{
+		{"v3.0.0", []string{"Unreleased", "v3.0.0", "v2.0.0", "v1.0.0"}},
+		{"v1.5.0", []string{"Unreleased", "v2.0.0", "v1.5.0", "v1.0.0"}},
+		{"v0.9.0", []string{"Unreleased", "v2.0.0", "v1.0.0", "v0.9.0"}},
+		{"v2.0.0-rc.1", []string{"Unreleased", "v2.0.0", "v2.0.0-rc.1", "v1.0.0"}},
+	}
+
+	for _, tt := range tests {
+		c, err := Parse([]byte(doc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.InsertRelease(&Release{Version: tt.version}); err != nil {
+			t.Fatalf("InsertRelease(%s) error = %v", tt.version, err)
+		}
+		if got := versions(c); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("InsertRelease(%s) order = %v, want %v", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestChangelog_RemoveRelease(t *testing.T) {
+	doc := `{"releases": [{"version": "v3.0.0"}, {"version": "v2.0.0"}, {"version": "v1.0.0"}]}`
+
+	tests := map[string]string{
+		"v3.0.0": `{"releases": [{"version": "v2.0.0"}, {"version": "v1.0.0"}]}`,
+		"v2.0.0": `{"releases": [{"version": "v3.0.0"}, {"version": "v1.0.0"}]}`,
+		"v1.0.0": `{"releases": [{"version": "v3.0.0"}, {"version": "v2.0.0"}]}`,
+		"v9.0.0": doc,
+	}
+	for version, want := range tests {
+		c, err := Parse([]byte(doc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		removed := c.RemoveRelease(version)
+		out, err := c.Bytes()
+		if err != nil || string(out) != want || removed != (version != "v9.0.0") {
+			t.Errorf("RemoveRelease(%s) = %v: %s, %v, want %s", version, removed, out, err, want)
+		}
+	}
+}
+
+const candidates = `{
+  "project": "demo",
+  "releases": [
+    {
+      "version": "v1.2.0-rc.2",
+      "date": "2026-03-10",
+      "summary": "Second candidate",
+      "fixed": [
+        { "description": "Fix from rc.2", "commit": "ccc3333" }
+      ]
+    },
+    {
+      "version": "v1.2.0-rc.1",
+      "date": "2026-03-01",
+      "summary": "First candidate",
+      "added": [
+        { "description": "Feature from rc.1", "commit": "aaa1111" }
+      ],
+      "fixed": [
+        { "description": "Fix from rc.1", "commit": "bbb2222" }
+      ]
+    },
+    {
+      "version": "v1.1.0",
+      "date": "2026-02-01"
+    }
+  ]
+}
+`
+
+func TestChangelog_FoldPrereleases(t *testing.T) {
+	c, err := Parse([]byte(candidates))
+	if err != nil {
+		t.Fatal(err)
+	}
+	folded, err := c.FoldPrereleases("v1.2.0", "2026-03-15")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"v1.2.0-rc.1", "v1.2.0-rc.2"}; !reflect.DeepEqual(folded, want) {
+		t.Errorf("folded = %v, want %v", folded, want)
+	}
+
+	out, err := c.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "project": "demo",
+  "releases": [
+    {
+      "version": "v1.2.0",
+      "date": "2026-03-15",
+      "summary": "Second candidate",
+      "added": [
+        { "description": "Feature from rc.1", "commit": "aaa1111" }
+      ],
+      "fixed": [
+        { "description": "Fix from rc.1", "commit": "bbb2222" },
+        { "description": "Fix from rc.2", "commit": "ccc3333" }
+      ]
+    },
+    {
+      "version": "v1.1.0",
+      "date": "2026-02-01"
+    }
+  ]
+}
+`
+	if string(out) != want {
+		t.Errorf("FoldPrereleases() =\n%s\nwant\n%s", out, want)
+	}
+
+	// Nothing to fold
+	c, _ = Parse([]byte(candidates))
+	folded, err = c.FoldPrereleases("v1.3.0", "2026-03-15")
+	if out, _ := c.Bytes(); err != nil || folded != nil || string(out) != candidates {
+		t.Errorf("FoldPrereleases(v1.3.0) = %v, %v", folded, err)
+	}
+
+	if _, err := c.FoldPrereleases("v1.1.0", "2026-03-15"); !errors.Is(err, ErrReleaseExists) {
+		t.Errorf("FoldPrereleases(existing) error = %v, want ErrReleaseExists", err)
+	}
+}
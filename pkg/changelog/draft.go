@@ -0,0 +1,331 @@
+package changelog
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/agentplexus/agent-team-release/pkg/git"
+)
+
+// DraftCategories are the categories commits are classified into when
+// drafting a release entry.
+var DraftCategories = []Category{Added, Changed, Deprecated, Removed, Fixed, Security}
+
+// Classification is where a commit's change belongs in the changelog.
+type Classification struct {
+	Category Category // Empty leaves the commit out of the changelog
+	Certain  bool     // False when the commit's type, subject and files leave room for doubt
+	Reason   string   // Why the commit was classified this way
+}
+
+// fileKind groups files that don't change what users get.
+type fileKind string
+
+const (
+	kindDocs         fileKind = "docs"
+	kindTests        fileKind = "tests"
+	kindTooling      fileKind = "CI and build"
+	kindDependencies fileKind = "dependency"
+)
+
+// lockFiles are dependency manifests and lock files.
+var lockFiles = map[string]bool{
+	"go.mod": true, "go.sum": true, "go.work": true, "go.work.sum": true,
+	"package-lock.json": true, "yarn.lock": true, "pnpm-lock.yaml": true,
+	"Cargo.lock": true, "poetry.lock": true, "uv.lock": true, "Gemfile.lock": true,
+}
+
+// kindOf returns the kind of a file that doesn't change what users get,
+// or "" for other files.
+func kindOf(file string) fileKind {
+	base := path.Base(file)
+	ext := strings.ToLower(path.Ext(base))
+	switch {
+	case lockFiles[base] || strings.HasPrefix(base, "requirements") && ext == ".txt":
+		return kindDependencies
+	case strings.HasSuffix(base, "_test.go") || strings.HasPrefix(base, "test_") && ext == ".py" ||
+		strings.Contains(base, ".test.") || strings.Contains(base, ".spec.") ||
+		hasDir(file, "test", "tests", "testdata", "__tests__"):
+		return kindTests
+	case ext == ".md" || ext == ".rst" || ext == ".adoc" || hasDir(file, "docs", "doc") ||
+		base == "LICENSE" || base == "mkdocs.yml":
+		return kindDocs
+	case hasDir(file, ".github", ".circleci", ".gitlab") || base == ".gitlab-ci.yml" ||
+		base == "Makefile" || base == "Dockerfile" || strings.HasPrefix(base, ".goreleaser") ||
+		base == ".golangci.yml" || base == ".golangci.yaml":
+		return kindTooling
+	}
+	return ""
+}
+
+// hasDir reports whether a slash-separated path is inside a directory
+// with one of the given names.
+func hasDir(file string, names ...string) bool {
+	dirs := strings.Split(path.Dir(file), "/")
+	for _, d := range dirs {
+		for _, n := range names {
+			if d == n {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// internalFiles returns the kind of files a commit touches if none of them
+// changes what users get: the kind they share, or "internal" if mixed.
+// It returns "" if any file is user-facing or the files are unknown.
+func internalFiles(files []string) fileKind {
+	var kind fileKind
+	for _, f := range files {
+		k := kindOf(f)
+		switch {
+		case k == "":
+			return ""
+		case kind == "":
+			kind = k
+		case kind != k:
+			kind = "internal"
+		}
+	}
+	return kind
+}
+
+// keywordCategory guesses a category from the wording of a commit subject,
+// skipping leading tags such as "[ABC-12]".
+func keywordCategory(subject string) (Category, bool) {
+	s := strings.ToLower(strings.TrimSpace(subject))
+	for strings.HasPrefix(s, "[") {
+		_, rest, ok := strings.Cut(s, "]")
+		if !ok {
+			break
+		}
+		s = strings.TrimSpace(rest)
+	}
+	first, _, _ := strings.Cut(s, " ")
+	switch {
+	case strings.Contains(s, "security") || strings.Contains(s, "cve-") || strings.Contains(s, "vulnerab"):
+		return Security, true
+	case strings.HasPrefix(first, "deprecat"):
+		return Deprecated, true
+	case oneOf(first, "remove", "removed", "removes", "drop", "dropped", "drops", "delete", "deleted", "deletes"):
+		return Removed, true
+	case oneOf(first, "fix", "fixed", "fixes", "resolve", "resolved", "resolves", "correct", "corrected") || strings.Contains(s, "bug"):
+		return Fixed, true
+	case oneOf(first, "add", "added", "adds", "introduce", "introduced", "introduces", "support", "supports", "implement", "implemented", "implements", "new"):
+		return Added, true
+	case oneOf(first, "update", "updated", "updates", "change", "changed", "changes", "improve", "improved", "improves",
+		"rename", "renamed", "renames", "replace", "replaced", "replaces", "refactor", "move", "moved", "moves"):
+		return Changed, true
+	}
+	return "", false
+}
+
+func oneOf(s string, values ...string) bool {
+	for _, v := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Classify decides where a commit's change belongs, from its conventional
+// type, its subject and the files it changes (see git.LogOptions.Files).
+// Features are added, fixes fixed, and performance improvements and
+// refactorings changed, unless the subject says the change deprecates,
+// removes or secures something. Docs, tests, CI, build and chore commits
+// are left out. Classifications that rest on wording alone, or where the
+// type and the files disagree, are uncertain. Breaking changes are never
+// left out.
+func Classify(c git.Commit) Classification {
+	internal := internalFiles(c.Files)
+	keyword, hasKeyword := keywordCategory(c.Description)
+
+	var cl Classification
+	switch c.Type {
+	case "feat":
+		cl = Classification{Category: Added, Certain: true, Reason: "feat commit"}
+		if hasKeyword && (keyword == Deprecated || keyword == Removed || keyword == Security) {
+			cl = Classification{Category: keyword, Certain: true, Reason: fmt.Sprintf("feat commit that reads as %s", keyword)}
+		}
+	case "fix":
+		cl = Classification{Category: Fixed, Certain: true, Reason: "fix commit"}
+		if keyword == Security {
+			cl = Classification{Category: Security, Certain: true, Reason: "fix commit that reads as security"}
+		}
+	case "perf":
+		cl = Classification{Category: Changed, Certain: true, Reason: "perf commit"}
+	case "refactor":
+		cl = Classification{Reason: "refactor commit, which may not change behavior"}
+	case "revert":
+		cl = Classification{Category: Removed, Reason: "revert commit"}
+	case "docs", "test", "tests", "ci", "build", "chore", "style":
+		cl = Classification{Certain: true, Reason: c.Type + " commit"}
+		if keyword == Security {
+			cl = Classification{Category: Security, Reason: c.Type + " commit that reads as security"}
+		}
+	case "":
+		switch {
+		case internal != "":
+			cl = Classification{Certain: true, Reason: fmt.Sprintf("only changes %s files", internal)}
+		case hasKeyword:
+			cl = Classification{Category: keyword, Reason: "not a conventional commit; guessed from its wording"}
+		default:
+			cl = Classification{Category: Changed, Reason: "not a conventional commit"}
+		}
+	default:
+		cl = Classification{Category: Changed, Reason: fmt.Sprintf("unknown commit type %q", c.Type)}
+		if hasKeyword {
+			cl.Category = keyword
+		}
+	}
+
+	// The type promises a user-facing change the files don't show
+	if cl.Category != "" && cl.Certain && internal != "" && c.Type != "" {
+		cl.Certain = false
+		cl.Reason += fmt.Sprintf(" that only changes %s files", internal)
+	}
+
+	if c.Breaking && cl.Category == "" {
+		cl = Classification{Category: Changed, Certain: true, Reason: "breaking " + cl.Reason}
+	}
+	return cl
+}
+
+// DraftItem is a commit and the change drafted from it.
+type DraftItem struct {
+	Commit git.Commit
+	Entry  Entry
+	Classification
+}
+
+// Draft is a release entry drafted from commits.
+type Draft struct {
+	Version      string
+	Date         string
+	Items        []*DraftItem      // Oldest commit first
+	Contributors []git.Contributor // See git.Git.Contributors
+}
+
+// NewDraft classifies commits, given newest first as git.Git.Commits
+// returns them, into a draft entry for version, with the issues and pull
+// requests each commit refers to linked to repo. Merge commits are
+// skipped, since the commits they merge are drafted themselves.
+func NewDraft(version, date string, commits []git.Commit, repo *git.Repository) *Draft {
+	d := &Draft{Version: version, Date: date}
+	for i := len(commits) - 1; i >= 0; i-- {
+		c := commits[i]
+		if c.IsMerge() {
+			continue
+		}
+		d.Items = append(d.Items, &DraftItem{
+			Commit: c,
+			Entry: Entry{
+				Description: sentence(c.Description),
+				Commit:      c.ShortHash(),
+				Breaking:    c.Breaking,
+				References:  References(c, repo),
+			},
+			Classification: Classify(c),
+		})
+	}
+	return d
+}
+
+// References returns the issues and pull requests a commit refers to, each
+// once, linked to repo. A nil repo leaves references written as numbers
+// unlinked.
+func References(c git.Commit, repo *git.Repository) []Reference {
+	var refs []Reference
+	seen := make(map[string]bool)
+	for _, ref := range c.References() {
+		url := ref.URL
+		if repo != nil {
+			ref = repo.Local(ref)
+			url = repo.ReferenceURL(ref)
+		}
+		id := ref.String()
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		refs = append(refs, Reference{ID: id, URL: url})
+	}
+	return refs
+}
+
+// sentence capitalizes the first letter of a commit description.
+func sentence(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// Uncertain returns the items whose classification is in doubt.
+func (d *Draft) Uncertain() []*DraftItem {
+	var items []*DraftItem
+	for _, item := range d.Items {
+		if !item.Certain {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Count returns the number of changes drafted under a category; the empty
+// category counts the commits left out.
+func (d *Draft) Count(c Category) int {
+	n := 0
+	for _, item := range d.Items {
+		if item.Category == c {
+			n++
+		}
+	}
+	return n
+}
+
+// Summary describes the draft, e.g. "2 added, 1 fixed (3 commits left out)".
+func (d *Draft) Summary() string {
+	var parts []string
+	for _, c := range DraftCategories {
+		if n := d.Count(c); n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, c))
+		}
+	}
+	summary := "no changes"
+	if len(parts) > 0 {
+		summary = strings.Join(parts, ", ")
+	}
+	if n := d.Count(""); n > 0 {
+		summary += fmt.Sprintf(" (%d commit(s) left out)", n)
+	}
+	return summary
+}
+
+// AddTo adds the drafted release entry to a changelog, listing each change
+// under its category in commit order, and acknowledging the contributors
+// other than bots.
+func (d *Draft) AddTo(c *Changelog) (*Release, error) {
+	r, err := c.AddRelease(d.Version, d.Date)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range d.Contributors {
+		if !p.IsBot() && p.Name != "" {
+			r.Contributors = append(r.Contributors, Contributor{Name: p.Name, FirstTime: p.FirstTime})
+		}
+	}
+	for _, item := range d.Items {
+		if item.Category == "" {
+			continue
+		}
+		if err := r.Add(item.Category, item.Entry); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,121 @@
+package changelog
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/agentplexus/agent-team-release/pkg/git"
+)
+
+func commit(hash, subject string, files ...string) git.Commit {
+	return git.Commit{
+		Hash:         hash,
+		Subject:      subject,
+		Files:        files,
+		Conventional: git.ParseConventional(subject),
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		subject  string
+		files    []string
+		category Category
+		certain  bool
+	}{
+		{"feat: streaming output", []string{"cmd/main.go"}, Added, true},
+		{"feat: deprecate the --old flag", []string{"cmd/main.go"}, Deprecated, true},
+		{"feat!: remove the v1 API", []string{"api/v1.go"}, Removed, true},
+		{"fix: crash on empty input", []string{"parse.go"}, Fixed, true},
+		{"fix(auth): patch CVE-2026-1234 token leak", []string{"auth.go"}, Security, true},
+		{"perf: cache lookups", []string{"cache.go"}, Changed, true},
+		{"refactor: split parser", []string{"parse.go"}, "", false},
+		{"refactor!: rename Config.Dir", []string{"config.go"}, Changed, true},
+		{"revert: streaming output", []string{"cmd/main.go"}, Removed, false},
+		{"docs: explain flags", []string{"README.md"}, "", true},
+		{"test: cover parser", []string{"parse_test.go"}, "", true},
+		{"ci: cache modules", []string{".github/workflows/ci.yaml"}, "", true},
+		{"chore(deps): bump x to fix security issue", []string{"go.mod", "go.sum"}, Security, false},
+		{"feat: add guide", []string{"docs/guide.md"}, Added, false},
+		{"fix: flaky test", []string{"pkg/a/a_test.go", "pkg/a/testdata/in.txt"}, Fixed, false},
+		{"Add retries to uploads", []string{"upload.go"}, Added, false},
+		{"[ABC-12] Remove legacy flags", []string{"flags.go"}, Removed, false},
+		{"Fix typo in README", []string{"README.md"}, "", true},
+		{"Tweak upload buffer", []string{"upload.go"}, Changed, false},
+		{"improvement: faster startup", []string{"main.go"}, Changed, false},
+	}
+	for _, tt := range tests {
+		got := Classify(commit("abc1234", tt.subject, tt.files...))
+		if got.Category != tt.category || got.Certain != tt.certain || got.Reason == "" {
+			t.Errorf("Classify(%q, %v) = %+v, want %q certain=%v", tt.subject, tt.files, got, tt.category, tt.certain)
+		}
+	}
+}
+
+func TestDraft(t *testing.T) {
+	merge := commit("ddd4444", "Merge branch 'topic'")
+	merge.Parents = []string{"a", "b"}
+	fix := commit("bbb2222aaaa", "fix: crash on start (#12)", "main.go")
+	fix.Body = "Fixes https://github.com/acme/demo/issues/9, see #12."
+	// Newest first, as git.Git.Commits returns them
+	commits := []git.Commit{
+		commit("eee5555aaaa", "refactor: split parser", "parse.go"),
+		merge,
+		commit("ccc3333aaaa", "docs: usage", "README.md"),
+		fix,
+		commit("aaa1111aaaa", "feat!: streaming output", "main.go"),
+	}
+
+	repo := &git.Repository{Forge: git.GitHub, Host: "github.com", Owner: "acme", Name: "demo"}
+	d := NewDraft("v2.0.0", "2026-05-01", commits, repo)
+	if len(d.Items) != 4 {
+		t.Fatalf("NewDraft() = %d items, want 4 (merge skipped)", len(d.Items))
+	}
+	first := d.Items[0]
+	if e := first.Entry; e.Description != "Streaming output" || e.Commit != "aaa1111" || !e.Breaking || first.Category != Added {
+		t.Errorf("first item = %+v, want the oldest commit", first)
+	}
+	wantRefs := []Reference{
+		{ID: "#12", URL: "https://github.com/acme/demo/pull/12"},
+		{ID: "#9", URL: "https://github.com/acme/demo/issues/9"},
+	}
+	if got := d.Items[1].Entry.References; !reflect.DeepEqual(got, wantRefs) {
+		t.Errorf("References = %+v, want %+v", got, wantRefs)
+	}
+	if uncertain := d.Uncertain(); len(uncertain) != 1 || uncertain[0].Commit.Hash != "eee5555aaaa" {
+		t.Errorf("Uncertain() = %+v, want the refactor", uncertain)
+	}
+	if got, want := d.Summary(), "1 added, 1 fixed (2 commit(s) left out)"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+
+	c, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.HasCommit("ccc3333") || !c.HasCommit("aaa1111ffff") {
+		t.Error("HasCommit() should match abbreviated hashes of credited commits only")
+	}
+	d.Uncertain()[0].Category = Changed
+	d.Contributors = []git.Contributor{
+		{Person: git.Person{Name: "Ada Lovelace"}, Commits: 2},
+		{Person: git.Person{Name: "renovate[bot]"}, Commits: 1, FirstTime: true},
+		{Person: git.Person{Name: "Grace Hopper"}, Commits: 1, FirstTime: true},
+	}
+	r, err := d.AddTo(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r != c.Releases[0] || r.Date != "2026-05-01" {
+		t.Errorf("AddTo() = %+v, want the newest release", r)
+	}
+	if got, want := r.Categories(), []Category{Added, Changed, Fixed}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Categories() = %v, want %v", got, want)
+	}
+	if want := []Contributor{{Name: "Ada Lovelace"}, {Name: "Grace Hopper", FirstTime: true}}; !reflect.DeepEqual(r.Contributors, want) {
+		t.Errorf("Contributors = %+v, want %+v", r.Contributors, want)
+	}
+	if _, err := d.AddTo(c); err == nil {
+		t.Error("AddTo() twice should fail")
+	}
+}
@@ -0,0 +1,169 @@
+package changelog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MarkdownFileName is the name of the changelog rendered from CHANGELOG.json.
+const MarkdownFileName = "CHANGELOG.md"
+
+// Markdown renders the changelog as CHANGELOG.md in the Keep a Changelog
+// format, the same layout schangelog generates: the unreleased changes,
+// then each release's changes by category and acknowledgements, then links
+// comparing each release with the one before it.
+func (c *Changelog) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString("# Changelog\n\n")
+	sb.WriteString("All notable changes to this project will be documented in this file.\n\n")
+
+	clauses := []string{"The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/)"}
+	if strings.EqualFold(c.Versioning, "semver") {
+		clauses = append(clauses, "this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html)")
+	}
+	if strings.EqualFold(c.CommitConvention, "conventional") {
+		clauses = append(clauses, "commits follow [Conventional Commits](https://www.conventionalcommits.org/en/v1.0.0/)")
+	}
+	clauses = append(clauses, "and this changelog is generated by [Structured Changelog](https://github.com/grokify/structured-changelog).")
+	sb.WriteString(strings.Join(clauses, ",\n") + "\n\n")
+
+	sb.WriteString("## [Unreleased]\n\n")
+	var released []*Release
+	for _, r := range c.Releases {
+		if IsUnreleased(r.Version) {
+			c.writeRelease(&sb, r)
+			continue
+		}
+		released = append(released, r)
+	}
+
+	for _, r := range released {
+		if r.Date != "" {
+			sb.WriteString(fmt.Sprintf("## [%s] - %s\n\n", r.Version, r.Date))
+		} else {
+			sb.WriteString(fmt.Sprintf("## [%s]\n\n", r.Version))
+		}
+		c.writeRelease(&sb, r)
+	}
+
+	repo := strings.TrimSuffix(c.Repository, "/")
+	if repo == "" || len(released) == 0 {
+		return sb.String()
+	}
+	sb.WriteString(fmt.Sprintf("[unreleased]: %s/compare/%s...HEAD\n", repo, released[0].Version))
+	for i, r := range released {
+		if i == len(released)-1 {
+			sb.WriteString(fmt.Sprintf("[%s]: %s/releases/tag/%s\n", r.Version, repo, r.Version))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("[%s]: %s/compare/%s...%s\n", r.Version, repo, released[i+1].Version, r.Version))
+	}
+	return sb.String()
+}
+
+// writeRelease writes a release's changes under a heading per category,
+// then its acknowledgements.
+func (c *Changelog) writeRelease(sb *strings.Builder, r *Release) {
+	c.writeCategories(sb, r)
+	if len(r.Contributors) == 0 {
+		return
+	}
+	sb.WriteString("### Acknowledgements\n\n")
+	sb.WriteString("Thanks to everyone who contributed to this release:\n\n")
+	for _, p := range r.Contributors {
+		if p.FirstTime {
+			sb.WriteString("- " + p.Name + " (first contribution)\n")
+		} else {
+			sb.WriteString("- " + p.Name + "\n")
+		}
+	}
+	sb.WriteString("\n")
+}
+
+// writeCategories writes a release's changes under a heading per category.
+func (c *Changelog) writeCategories(sb *strings.Builder, r *Release) {
+	for _, cat := range r.Categories() {
+		sb.WriteString("### " + cat.Title() + "\n\n")
+		for _, e := range r.Entries(cat) {
+			sb.WriteString("- " + c.entryLine(e) + "\n")
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// entryLine renders a change, linking its issues and pull requests, and its
+// commit if the repository is known.
+func (c *Changelog) entryLine(e Entry) string {
+	line := e.Description
+	if e.Breaking {
+		line = "**BREAKING:** " + line
+	}
+	if len(e.References) > 0 {
+		links := make([]string, len(e.References))
+		for i, ref := range e.References {
+			links[i] = ref.ID
+			if ref.URL != "" {
+				links[i] = fmt.Sprintf("[%s](%s)", ref.ID, ref.URL)
+			}
+		}
+		line += " (" + strings.Join(links, ", ") + ")"
+	}
+	if e.Commit == "" {
+		return line
+	}
+	if repo := strings.TrimSuffix(c.Repository, "/"); repo != "" {
+		return fmt.Sprintf("%s ([`%s`](%s/commit/%s))", line, e.Commit, repo, e.Commit)
+	}
+	return fmt.Sprintf("%s (`%s`)", line, e.Commit)
+}
+
+// ReleaseNotes renders the notes for one release: its changes by category
+// and acknowledgements, as in CHANGELOG.md, and a link comparing it with
+// the release before it.
+func (c *Changelog) ReleaseNotes(version string) (string, error) {
+	r := c.Release(version)
+	if r == nil {
+		return "", fmt.Errorf("no release entry for %s in %s", version, FileName)
+	}
+
+	var sb strings.Builder
+	title := r.Version
+	if c.Project != "" {
+		title = c.Project + " " + title
+	}
+	sb.WriteString("# " + title + "\n\n")
+	if r.Date != "" {
+		sb.WriteString("Released " + r.Date + ".\n\n")
+	}
+	c.writeRelease(&sb, r)
+
+	repo := strings.TrimSuffix(c.Repository, "/")
+	if repo == "" || IsUnreleased(r.Version) {
+		return sb.String(), nil
+	}
+	var previous string
+	for i, existing := range c.Releases {
+		if existing == r && i+1 < len(c.Releases) {
+			previous = c.Releases[i+1].Version
+		}
+	}
+	if previous != "" {
+		sb.WriteString(fmt.Sprintf("**Full Changelog**: %s/compare/%s...%s\n", repo, previous, r.Version))
+	} else {
+		sb.WriteString(fmt.Sprintf("**Full Changelog**: %s/releases/tag/%s\n", repo, r.Version))
+	}
+	return sb.String(), nil
+}
+
+// ReleaseNotesPath returns where the notes for version go, relative to dir:
+// docs/releases/vX.Y.Z.md if dir has a docs directory, else
+// RELEASE_NOTES_vX.Y.Z.md.
+func ReleaseNotesPath(dir, version string) string {
+	version = "v" + strings.TrimPrefix(version, "v")
+	if info, err := os.Stat(filepath.Join(dir, "docs")); err == nil && info.IsDir() {
+		return filepath.Join("docs", "releases", version+".md")
+	}
+	return "RELEASE_NOTES_" + version + ".md"
+}
@@ -0,0 +1,794 @@
+package changelog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agentplexus/agent-team-release/pkg/semver"
+)
+
+// Category is a kind of change listed in a release entry, e.g. "added".
+type Category string
+
+// Change categories, in the order CHANGELOG.md lists them.
+const (
+	Highlights     Category = "highlights"
+	Breaking       Category = "breaking"
+	UpgradeGuide   Category = "upgrade_guide"
+	Security       Category = "security"
+	Added          Category = "added"
+	Changed        Category = "changed"
+	Deprecated     Category = "deprecated"
+	Removed        Category = "removed"
+	Fixed          Category = "fixed"
+	Performance    Category = "performance"
+	Dependencies   Category = "dependencies"
+	Documentation  Category = "documentation"
+	Build          Category = "build"
+	Tests          Category = "tests"
+	Infrastructure Category = "infrastructure"
+	Observability  Category = "observability"
+	Compliance     Category = "compliance"
+	Internal       Category = "internal"
+	KnownIssues    Category = "known_issues"
+)
+
+// Categories lists the change categories in the order CHANGELOG.md lists them.
+var Categories = []Category{
+	Highlights, Breaking, UpgradeGuide, Security, Added, Changed, Deprecated,
+	Removed, Fixed, Performance, Dependencies, Documentation, Build, Tests,
+	Infrastructure, Observability, Compliance, Internal, KnownIssues,
+}
+
+// categoryTitles holds the headings that aren't the capitalized category.
+var categoryTitles = map[Category]string{
+	UpgradeGuide: "Upgrade Guide",
+	KnownIssues:  "Known Issues",
+}
+
+// Title returns the category's heading in CHANGELOG.md, e.g. "Added".
+func (c Category) Title() string {
+	if t, ok := categoryTitles[c]; ok {
+		return t
+	}
+	s := string(c)
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// Valid reports whether c is a known category.
+func (c Category) Valid() bool {
+	for _, k := range Categories {
+		if k == c {
+			return true
+		}
+	}
+	return false
+}
+
+// Unreleased is the version of the entry collecting unreleased changes.
+const Unreleased = "Unreleased"
+
+// IsUnreleased reports whether version names the unreleased entry.
+func IsUnreleased(version string) bool {
+	return strings.EqualFold(version, Unreleased)
+}
+
+// DateFormat is the layout of release dates.
+const DateFormat = "2006-01-02"
+
+// Entry is one change listed in a release.
+type Entry struct {
+	Description string
+	Commit      string // Short hash of the commit making the change
+	Breaking    bool
+	References  []Reference // Issues and pull requests the change refers to
+
+	keys  []string                   // Field order in the file
+	extra map[string]json.RawMessage // Fields the model doesn't know, kept as is
+}
+
+// entryFields lists the fields Entry models, in the order new entries
+// write them.
+var entryFields = []string{"description", "breaking", "commit", "references"}
+
+// Reference is an issue or pull request a change refers to.
+type Reference struct {
+	ID  string `json:"id"`            // As written in commit messages, e.g. "#12" or "owner/repo#12"
+	URL string `json:"url,omitempty"` // Web URL, if the repository is known
+}
+
+// UnmarshalJSON decodes an entry, keeping its field order and unknown fields.
+func (e *Entry) UnmarshalJSON(data []byte) error {
+	o, err := parseObject(data)
+	if err != nil {
+		return errors.New("changes must be objects")
+	}
+	*e = Entry{keys: o.keys}
+	for _, key := range o.keys {
+		value := o.values[key]
+		var err error
+		switch key {
+		case "description":
+			err = json.Unmarshal(value, &e.Description)
+		case "commit":
+			err = json.Unmarshal(value, &e.Commit)
+		case "breaking":
+			err = json.Unmarshal(value, &e.Breaking)
+		case "references":
+			err = json.Unmarshal(value, &e.References)
+		default:
+			if e.extra == nil {
+				e.extra = make(map[string]json.RawMessage)
+			}
+			e.extra[key] = value
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// MarshalJSON encodes the entry with its fields in their original order.
+func (e Entry) MarshalJSON() ([]byte, error) {
+	return e.object().MarshalJSON()
+}
+
+func (e Entry) object() *object {
+	values := make(map[string]json.RawMessage, len(e.extra)+4)
+	for k, v := range e.extra {
+		values[k] = v
+	}
+	if e.Description != "" {
+		values["description"] = mustMarshal(e.Description)
+	}
+	if e.Commit != "" {
+		values["commit"] = mustMarshal(e.Commit)
+	}
+	if e.Breaking {
+		values["breaking"] = json.RawMessage("true")
+	}
+	if len(e.References) > 0 {
+		values["references"] = mustMarshal(e.References)
+	}
+	return &object{keys: orderKeys(e.keys, entryFields, values), values: values}
+}
+
+// Release is a release entry of the changelog.
+type Release struct {
+	Version      string
+	Date         string        // YYYY-MM-DD; empty for the unreleased entry
+	Contributors []Contributor // Acknowledged in CHANGELOG.md and release notes
+
+	entries map[Category][]Entry
+	keys    []string                   // Field order in the file
+	extra   map[string]json.RawMessage // Fields the model doesn't know, kept as is
+	raw     []byte                     // The entry as it appears in the file
+	loaded  []byte                     // The entry's encoding when loaded
+}
+
+// releaseFields lists the fields Release models, in the order new entries
+// write them.
+var releaseFields = func() []string {
+	fields := []string{"version", "date"}
+	for _, c := range Categories {
+		fields = append(fields, string(c))
+	}
+	return append(fields, "contributors")
+}()
+
+// Contributor is a person credited with a release's commits.
+type Contributor struct {
+	Name      string `json:"name"`
+	FirstTime bool   `json:"first_time,omitempty"` // First contribution to the project
+}
+
+// Entries returns the changes listed under a category.
+func (r *Release) Entries(c Category) []Entry {
+	return r.entries[c]
+}
+
+// Add lists changes under a category.
+func (r *Release) Add(c Category, entries ...Entry) error {
+	if !c.Valid() {
+		return fmt.Errorf("unknown changelog category %q", c)
+	}
+	if r.entries == nil {
+		r.entries = make(map[Category][]Entry)
+	}
+	r.entries[c] = append(r.entries[c], entries...)
+	return nil
+}
+
+// Set replaces the changes listed under a category. No entries removes it.
+func (r *Release) Set(c Category, entries []Entry) error {
+	if !c.Valid() {
+		return fmt.Errorf("unknown changelog category %q", c)
+	}
+	if len(entries) == 0 {
+		delete(r.entries, c)
+		return nil
+	}
+	if r.entries == nil {
+		r.entries = make(map[Category][]Entry)
+	}
+	r.entries[c] = entries
+	return nil
+}
+
+// Categories returns the categories listing changes, in CHANGELOG.md order.
+func (r *Release) Categories() []Category {
+	var cats []Category
+	for _, c := range Categories {
+		if len(r.entries[c]) > 0 {
+			cats = append(cats, c)
+		}
+	}
+	return cats
+}
+
+// Count returns the number of changes listed under the given categories.
+func (r *Release) Count(cats ...Category) int {
+	n := 0
+	for _, c := range cats {
+		n += len(r.entries[c])
+	}
+	return n
+}
+
+// Changes returns the number of changes listed, not counting highlights,
+// which summarize other changes.
+func (r *Release) Changes() int {
+	n := 0
+	for c, entries := range r.entries {
+		if c != Highlights {
+			n += len(entries)
+		}
+	}
+	return n
+}
+
+// BreakingChanges returns the changes marked breaking and those listed
+// under the breaking category.
+func (r *Release) BreakingChanges() []Entry {
+	var breaking []Entry
+	for _, c := range Categories {
+		for _, e := range r.entries[c] {
+			if c == Breaking || (e.Breaking && c != Highlights) {
+				breaking = append(breaking, e)
+			}
+		}
+	}
+	return breaking
+}
+
+// UnmarshalJSON decodes a release entry, keeping its field order and
+// unknown fields.
+func (r *Release) UnmarshalJSON(data []byte) error {
+	o, err := parseObject(data)
+	if err != nil {
+		return err
+	}
+	*r = Release{keys: o.keys}
+	for _, key := range o.keys {
+		value := o.values[key]
+		var err error
+		switch {
+		case key == "version":
+			err = json.Unmarshal(value, &r.Version)
+		case key == "date":
+			err = json.Unmarshal(value, &r.Date)
+		case key == "contributors":
+			err = json.Unmarshal(value, &r.Contributors)
+		case Category(key).Valid():
+			var entries []Entry
+			if err = json.Unmarshal(value, &entries); err == nil && len(entries) > 0 {
+				err = r.Add(Category(key), entries...)
+			}
+		default:
+			if r.extra == nil {
+				r.extra = make(map[string]json.RawMessage)
+			}
+			r.extra[key] = value
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// MarshalJSON encodes the release with its fields in their original
+// order; new categories take their place in CHANGELOG.md order.
+func (r *Release) MarshalJSON() ([]byte, error) {
+	return r.object().MarshalJSON()
+}
+
+func (r *Release) object() *object {
+	values := make(map[string]json.RawMessage, len(r.extra)+len(r.entries)+3)
+	for k, v := range r.extra {
+		values[k] = v
+	}
+	values["version"] = mustMarshal(r.Version)
+	if r.Date != "" {
+		values["date"] = mustMarshal(r.Date)
+	}
+	for c, entries := range r.entries {
+		if len(entries) > 0 {
+			values[string(c)] = mustMarshal(entries)
+		}
+	}
+	if len(r.Contributors) > 0 {
+		values["contributors"] = mustMarshal(r.Contributors)
+	}
+	return &object{keys: orderKeys(r.keys, releaseFields, values), values: values}
+}
+
+// changed reports whether the release differs from when it was loaded.
+func (r *Release) changed() bool {
+	if r.raw == nil {
+		return true
+	}
+	current, err := json.Marshal(r)
+	return err != nil || !bytes.Equal(current, r.loaded)
+}
+
+// Changelog is a parsed CHANGELOG.json. Saving it rewrites only the
+// release entries that were added or changed, so the rest of the file
+// keeps its formatting.
+type Changelog struct {
+	IRVersion        string `json:"ir_version,omitempty"`
+	Project          string `json:"project,omitempty"`
+	Repository       string `json:"repository,omitempty"` // e.g., https://github.com/owner/repo
+	Versioning       string `json:"versioning,omitempty"` // e.g., "semver"
+	CommitConvention string `json:"commit_convention,omitempty"`
+
+	Releases []*Release `json:"-"` // Newest first
+
+	data []byte
+}
+
+// Parse parses a CHANGELOG.json document.
+func Parse(data []byte) (*Changelog, error) {
+	_, spans, err := releases(data)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Changelog{data: data}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", FileName, err)
+	}
+	for i, s := range spans {
+		raw := data[s.start:s.end]
+		r := &Release{}
+		if err := json.Unmarshal(raw, r); err != nil {
+			return nil, fmt.Errorf("%s: release %s: %w", FileName, releaseName(s.version, i), err)
+		}
+		r.raw = raw
+		if r.loaded, err = json.Marshal(r); err != nil {
+			return nil, err
+		}
+		c.Releases = append(c.Releases, r)
+	}
+	return c, nil
+}
+
+// Load reads and parses a CHANGELOG.json file.
+func Load(path string) (*Changelog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Release returns the release entry for version, ignoring a leading "v",
+// or nil if there is none.
+func (c *Changelog) Release(version string) *Release {
+	for _, r := range c.Releases {
+		if sameVersion(r.Version, version) || (IsUnreleased(version) && IsUnreleased(r.Version)) {
+			return r
+		}
+	}
+	return nil
+}
+
+// AddRelease adds an empty release entry for version, dated date, in
+// version order: after the unreleased entry and before older releases.
+func (c *Changelog) AddRelease(version, date string) (*Release, error) {
+	r := &Release{Version: version, Date: date}
+	if err := c.insert(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// InsertRelease adds a copy of a release entry, such as one from another
+// branch's changelog, in version order. The copy is laid out like the
+// changelog's other entries.
+func (c *Changelog) InsertRelease(r *Release) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	cp := &Release{}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return err
+	}
+	return c.insert(cp)
+}
+
+// insert adds a release entry in version order.
+func (c *Changelog) insert(r *Release) error {
+	if r.Version == "" {
+		return errors.New("release entry has no version")
+	}
+	if c.Release(r.Version) != nil {
+		return fmt.Errorf("%w: %s", ErrReleaseExists, r.Version)
+	}
+
+	at := len(c.Releases)
+	for i, existing := range c.Releases {
+		if semver.IsValid(existing.Version) && semver.IsValid(r.Version) && semver.Compare(r.Version, existing.Version) > 0 {
+			at = i
+			break
+		}
+	}
+	if IsUnreleased(r.Version) {
+		at = 0
+	}
+	c.Releases = append(c.Releases[:at], append([]*Release{r}, c.Releases[at:]...)...)
+	return nil
+}
+
+// RemoveRelease removes the release entry for version, reporting whether
+// there was one.
+func (c *Changelog) RemoveRelease(version string) bool {
+	for i, r := range c.Releases {
+		if sameVersion(r.Version, version) {
+			c.Releases = append(c.Releases[:i], c.Releases[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// FoldPrereleases replaces the prerelease entries of version (e.g.,
+// v1.2.0-rc.1 and v1.2.0-rc.2 for v1.2.0) with a single entry for version
+// dated date. Their change lists are concatenated oldest first and other
+// fields are taken from the newest. It returns the folded versions, oldest
+// first, or none if there were no prerelease entries.
+func (c *Changelog) FoldPrereleases(version, date string) ([]string, error) {
+	target, err := semver.Parse(version)
+	if err != nil {
+		return nil, err
+	}
+	if c.Release(version) != nil {
+		return nil, fmt.Errorf("%w: %s", ErrReleaseExists, version)
+	}
+
+	var folded []*Release
+	for _, r := range c.Releases {
+		v, err := semver.Parse(r.Version)
+		if err == nil && v.Prerelease != "" && v.Release() == target.Release() {
+			folded = append(folded, r)
+		}
+	}
+	if len(folded) == 0 {
+		return nil, nil
+	}
+	sort.Slice(folded, func(i, j int) bool {
+		return semver.Compare(folded[i].Version, folded[j].Version) < 0
+	})
+
+	merged := &object{values: make(map[string]json.RawMessage)}
+	versions := make([]string, len(folded))
+	for i, r := range folded {
+		merged.merge(r.object())
+		versions[i] = r.Version
+	}
+	merged.set("version", version)
+	merged.set("date", date)
+
+	data, err := merged.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	release := &Release{}
+	if err := json.Unmarshal(data, release); err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		c.RemoveRelease(v)
+	}
+	return versions, c.insert(release)
+}
+
+// HasCommit reports whether any change in the changelog credits the commit
+// with the given full or abbreviated hash.
+func (c *Changelog) HasCommit(hash string) bool {
+	if hash == "" {
+		return false
+	}
+	for _, r := range c.Releases {
+		for _, cat := range r.Categories() {
+			for _, e := range r.Entries(cat) {
+				if e.Commit != "" && (strings.HasPrefix(hash, e.Commit) || strings.HasPrefix(e.Commit, hash)) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Sort orders the release entries newest first, with the unreleased entry
+// and other entries that aren't versions first.
+func (c *Changelog) Sort() {
+	sort.SliceStable(c.Releases, func(i, j int) bool {
+		a, b := c.Releases[i].Version, c.Releases[j].Version
+		if va, vb := semver.IsValid(a), semver.IsValid(b); !va || !vb {
+			return !va && vb
+		}
+		return semver.Compare(a, b) > 0
+	})
+}
+
+// Validate checks the release entries: each has a valid version and date,
+// no version appears twice, releases are newest first, and every change
+// has a description and references with ids, and every contributor a
+// name. It returns all the problems found, joined.
+func (c *Changelog) Validate() error {
+	var errs []error
+	seen := make(map[string]bool)
+	prev := ""
+	for i, r := range c.Releases {
+		name := releaseName(r.Version, i)
+		switch {
+		case r.Version == "":
+			errs = append(errs, fmt.Errorf("release %s: missing version", name))
+		case IsUnreleased(r.Version):
+			if i != 0 {
+				errs = append(errs, fmt.Errorf("release %s: must be the first entry", name))
+			}
+		case !semver.IsValid(r.Version):
+			errs = append(errs, fmt.Errorf("release %s: invalid version", name))
+		default:
+			if _, err := time.Parse(DateFormat, r.Date); err != nil {
+				errs = append(errs, fmt.Errorf("release %s: invalid date %q, want YYYY-MM-DD", name, r.Date))
+			}
+			if prev != "" && semver.Compare(prev, r.Version) < 0 {
+				errs = append(errs, fmt.Errorf("release %s: listed after older release %s", name, prev))
+			}
+			prev = r.Version
+		}
+
+		key := strings.ToLower(strings.TrimPrefix(r.Version, "v"))
+		if r.Version != "" && seen[key] {
+			errs = append(errs, fmt.Errorf("release %s: duplicate entry", name))
+		}
+		seen[key] = true
+
+		for _, cat := range r.Categories() {
+			for j, e := range r.entries[cat] {
+				if strings.TrimSpace(e.Description) == "" {
+					errs = append(errs, fmt.Errorf("release %s: %s[%d]: missing description", name, cat, j))
+				}
+				for k, ref := range e.References {
+					if strings.TrimSpace(ref.ID) == "" {
+						errs = append(errs, fmt.Errorf("release %s: %s[%d]: references[%d]: missing id", name, cat, j, k))
+					}
+				}
+			}
+		}
+		for j, p := range r.Contributors {
+			if strings.TrimSpace(p.Name) == "" {
+				errs = append(errs, fmt.Errorf("release %s: contributors[%d]: missing name", name, j))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// releaseName names a release in messages by its version, or by its
+// position if it has none.
+func releaseName(version string, i int) string {
+	if version != "" {
+		return version
+	}
+	return fmt.Sprintf("#%d", i+1)
+}
+
+// Bytes returns the document with the release entries as they now are.
+// Unchanged entries keep their text, and added or changed entries are
+// laid out like the existing ones.
+func (c *Changelog) Bytes() ([]byte, error) {
+	open, close, spans, err := releasesArray(c.data)
+	if err != nil {
+		return nil, err
+	}
+	l := layoutOf(c.data, spans)
+
+	parts := make([][]byte, len(c.Releases))
+	for i, r := range c.Releases {
+		if !r.changed() {
+			parts[i] = r.raw
+			continue
+		}
+		if parts[i], err = l.format(r); err != nil {
+			return nil, err
+		}
+	}
+
+	var out bytes.Buffer
+	switch {
+	case len(spans) > 0:
+		sep := []byte(separator(c.data, spans[0].start))
+		last := spans[len(spans)-1]
+		if len(parts) == 0 {
+			// Drop the whitespace between the brackets too
+			out.Write(c.data[:open])
+			out.Write(c.data[close:])
+			break
+		}
+		out.Write(c.data[:spans[0].start])
+		out.Write(bytes.Join(parts, sep))
+		out.Write(c.data[last.end:])
+	case len(parts) > 0:
+		outer := strings.TrimSuffix(l.prefix, l.unit)
+		out.Write(c.data[:open])
+		out.WriteString("\n" + l.prefix)
+		out.Write(bytes.Join(parts, []byte(",\n"+l.prefix)))
+		out.WriteString("\n" + outer)
+		out.Write(c.data[close:])
+	default:
+		return c.data, nil
+	}
+	return out.Bytes(), nil
+}
+
+// Save writes the document to path. Afterwards the saved entries count as
+// unchanged.
+func (c *Changelog) Save(path string) error {
+	data, err := c.Bytes()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	saved, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	*c = *saved
+	return nil
+}
+
+// format encodes a release entry in the layout.
+func (l layout) format(r *Release) ([]byte, error) {
+	if l.inline {
+		return json.Marshal(r)
+	}
+
+	o := r.object()
+	inner := l.prefix + l.unit
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString("\n" + inner)
+		buf.Write(mustMarshal(key))
+		buf.WriteString(": ")
+
+		cat := Category(key)
+		if !cat.Valid() {
+			var value bytes.Buffer
+			if err := json.Indent(&value, o.values[key], inner, l.unit); err != nil {
+				return nil, err
+			}
+			buf.Write(value.Bytes())
+			continue
+		}
+		buf.WriteString("[")
+		for j, e := range r.entries[cat] {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString("\n" + inner + l.unit)
+			entry, err := l.formatEntry(e, inner+l.unit)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(entry)
+		}
+		buf.WriteString("\n" + inner + "]")
+	}
+	buf.WriteString("\n" + l.prefix + "}")
+	return buf.Bytes(), nil
+}
+
+// formatEntry encodes a change, on one line if the layout writes changes
+// that way.
+func (l layout) formatEntry(e Entry, prefix string) ([]byte, error) {
+	if !l.inlineEntries {
+		return json.MarshalIndent(e, prefix, l.unit)
+	}
+	o := e.object()
+	fields := make([]string, len(o.keys))
+	for i, key := range o.keys {
+		var value bytes.Buffer
+		if err := json.Compact(&value, o.values[key]); err != nil {
+			return nil, err
+		}
+		fields[i] = string(mustMarshal(key)) + ": " + value.String()
+	}
+	return []byte("{ " + strings.Join(fields, ", ") + " }"), nil
+}
+
+// orderKeys returns the keys of values in their original order, with new
+// keys placed before the first key that follows them in canonical. Keys
+// no longer in values are dropped.
+func orderKeys(original, canonical []string, values map[string]json.RawMessage) []string {
+	rank := make(map[string]int, len(canonical))
+	for i, k := range canonical {
+		rank[k] = i
+	}
+
+	var keys []string
+	placed := make(map[string]bool)
+	for _, k := range original {
+		if _, ok := values[k]; ok && !placed[k] {
+			keys = append(keys, k)
+			placed[k] = true
+		}
+	}
+
+	// New modeled keys, in canonical order
+	for _, k := range canonical {
+		if _, ok := values[k]; !ok || placed[k] {
+			continue
+		}
+		at := len(keys)
+		for i, existing := range keys {
+			if r, ok := rank[existing]; ok && r > rank[k] {
+				at = i
+				break
+			}
+		}
+		keys = append(keys[:at], append([]string{k}, keys[at:]...)...)
+		placed[k] = true
+	}
+
+	// New unmodeled keys, sorted
+	var rest []string
+	for k := range values {
+		if !placed[k] {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
+// mustMarshal encodes values that always encode: strings, entries,
+// references and contributors.
+func mustMarshal(v any) json.RawMessage {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
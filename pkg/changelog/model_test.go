@@ -0,0 +1,392 @@
+package changelog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse_RoundTrip(t *testing.T) {
+	c, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Project != "demo" || len(c.Releases) != 2 {
+		t.Fatalf("Parse() = project %q, %d releases", c.Project, len(c.Releases))
+	}
+
+	r := c.Release("1.6.0")
+	if r == nil || r.Date != "2026-03-01" {
+		t.Fatalf("Release(1.6.0) = %+v", r)
+	}
+	if added := r.Entries(Added); len(added) != 1 || added[0].Description != "New thing" || added[0].Commit != "aaa1111" {
+		t.Errorf("Entries(added) = %+v", added)
+	}
+
+	out, err := c.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != sample {
+		t.Errorf("Bytes() of an unchanged changelog =\n%s\nwant\n%s", out, sample)
+	}
+}
+
+func TestChangelog_Edit(t *testing.T) {
+	c, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := c.AddRelease("v1.7.0", "2026-04-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(Fixed, Entry{Description: "Crash on start", Commit: "ccc3333"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(Added, Entry{Description: "Streaming", Breaking: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add("misc", Entry{Description: "x"}); err == nil {
+		t.Error("Add() should reject unknown categories")
+	}
+	if _, err := c.AddRelease("1.7.0", "2026-04-02"); !errors.Is(err, ErrReleaseExists) {
+		t.Errorf("AddRelease(duplicate) error = %v, want ErrReleaseExists", err)
+	}
+
+	// A new category of an existing release goes in CHANGELOG.md order
+	if err := c.Release("v1.4.2").Add(Added, Entry{Description: "Late addition"}); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := c.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "ir_version": "1.0",
+  "project": "demo",
+  "releases": [
+    {
+      "version": "v1.7.0",
+      "date": "2026-04-01",
+      "added": [
+        { "description": "Streaming", "breaking": true }
+      ],
+      "fixed": [
+        { "description": "Crash on start", "commit": "ccc3333" }
+      ]
+    },
+    {
+      "version": "v1.6.0",
+      "date": "2026-03-01",
+      "added": [
+        { "description": "New thing", "commit": "aaa1111" }
+      ]
+    },
+    {
+      "version": "v1.4.2",
+      "date": "2026-01-10",
+      "added": [
+        { "description": "Late addition" }
+      ],
+      "fixed": [
+        { "description": "Old fix", "commit": "bbb2222" }
+      ]
+    }
+  ]
+}
+`
+	if string(out) != want {
+		t.Errorf("Bytes() =\n%s\nwant\n%s", out, want)
+	}
+
+	if !c.RemoveRelease("v1.6.0") || c.RemoveRelease("v1.6.0") {
+		t.Error("RemoveRelease() should remove the entry once")
+	}
+}
+
+func TestChangelog_SaveKeepsUnknownFields(t *testing.T) {
+	doc := `{"releases": [{"version": "v1.0.0", "date": "2026-01-01", "yanked": true, "fixed": [{"description": "a", "pr": 12}]}]}`
+	c, err := Parse([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := c.Release("v1.0.0")
+	if err := r.Add(Fixed, Entry{Description: "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := c.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := saved.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"releases": [{"version":"v1.0.0","date":"2026-01-01","yanked":true,"fixed":[{"description":"a","pr":12},{"description":"b"}]}]}`
+	if string(raw) != want {
+		t.Errorf("saved =\n%s\nwant\n%s", raw, want)
+	}
+}
+
+func TestChangelog_EmptyReleases(t *testing.T) {
+	c, err := Parse([]byte("{\n  \"project\": \"demo\",\n  \"releases\": []\n}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := c.AddRelease("v0.1.0", "2026-01-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(Added, Entry{Description: "First"}); err != nil {
+		t.Fatal(err)
+	}
+	out, err := c.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "project": "demo",
+  "releases": [
+    {
+      "version": "v0.1.0",
+      "date": "2026-01-01",
+      "added": [
+        {
+          "description": "First"
+        }
+      ]
+    }
+  ]
+}
+`
+	if string(out) != want {
+		t.Errorf("Bytes() =\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestChangelog_Sort(t *testing.T) {
+	c, err := Parse([]byte(`{"releases": [{"version": "v1.0.0"}, {"version": "v2.0.0"}, {"version": "Unreleased"}, {"version": "v1.5.0"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Sort()
+	var got []string
+	for _, r := range c.Releases {
+		got = append(got, r.Version)
+	}
+	if want := []string{"Unreleased", "v2.0.0", "v1.5.0", "v1.0.0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Sort() order = %v, want %v", got, want)
+	}
+}
+
+func TestChangelog_Validate(t *testing.T) {
+	c, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+
+	c, err = Parse([]byte(`{"releases": [
+		{"version": "v1.0.0", "date": "2026-01-01"},
+		{"version": "v1.1.0", "date": "Jan 2"},
+		{"version": "1.0.0", "date": "2026-01-01", "fixed": [{"commit": "abc"}]},
+		{"version": "latest"},
+		{"version": "Unreleased"}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.Validate()
+	if err == nil {
+		t.Fatal("Validate() = nil, want problems")
+	}
+	for _, want := range []string{
+		`release v1.1.0: invalid date "Jan 2"`,
+		"release v1.1.0: listed after older release v1.0.0",
+		"release 1.0.0: duplicate entry",
+		"release 1.0.0: fixed[0]: missing description",
+		"release latest: invalid version",
+		"release Unreleased: must be the first entry",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() = %v\nmissing %q", err, want)
+		}
+	}
+}
+
+func TestRelease_Counts(t *testing.T) {
+	r := &Release{Version: "v2.0.0"}
+	_ = r.Add(Highlights, Entry{Description: "Big release", Breaking: true})
+	_ = r.Add(Changed, Entry{Description: "Renamed flag", Breaking: true}, Entry{Description: "Faster"})
+	_ = r.Add(Breaking, Entry{Description: "Dropped v1 API"})
+	_ = r.Add(Fixed, Entry{Description: "Fix"})
+
+	if got := r.Changes(); got != 4 {
+		t.Errorf("Changes() = %d, want 4", got)
+	}
+	if got := r.Count(Changed, Fixed); got != 3 {
+		t.Errorf("Count(changed, fixed) = %d, want 3", got)
+	}
+	if got := len(r.BreakingChanges()); got != 2 {
+		t.Errorf("BreakingChanges() = %d, want 2", got)
+	}
+	if got, want := r.Categories(), []Category{Highlights, Breaking, Changed, Fixed}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Categories() = %v, want %v", got, want)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	c, err := Parse([]byte(`{
+  "project": "demo",
+  "repository": "https://github.com/acme/demo",
+  "versioning": "semver",
+  "releases": [
+    {"version": "Unreleased", "added": [{"description": "Work in progress"}]},
+    {"version": "v1.1.0", "date": "2026-02-01", "upgrade_guide": [{"description": "Rename the config"}], "changed": [{"description": "New config", "breaking": true, "commit": "bbb2222"}]},
+    {"version": "v1.0.0", "date": "2026-01-01", "added": [{"description": "First", "commit": "aaa1111"}]}
+  ]
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "# Changelog\n\n" +
+		"All notable changes to this project will be documented in this file.\n\n" +
+		"The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),\n" +
+		"this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html),\n" +
+		"and this changelog is generated by [Structured Changelog](https://github.com/grokify/structured-changelog).\n\n" +
+		"## [Unreleased]\n\n" +
+		"### Added\n\n- Work in progress\n\n" +
+		"## [v1.1.0] - 2026-02-01\n\n" +
+		"### Upgrade Guide\n\n- Rename the config\n\n" +
+		"### Changed\n\n- **BREAKING:** New config ([`bbb2222`](https://github.com/acme/demo/commit/bbb2222))\n\n" +
+		"## [v1.0.0] - 2026-01-01\n\n" +
+		"### Added\n\n- First ([`aaa1111`](https://github.com/acme/demo/commit/aaa1111))\n\n" +
+		"[unreleased]: https://github.com/acme/demo/compare/v1.1.0...HEAD\n" +
+		"[v1.1.0]: https://github.com/acme/demo/compare/v1.0.0...v1.1.0\n" +
+		"[v1.0.0]: https://github.com/acme/demo/releases/tag/v1.0.0\n"
+	if got := c.Markdown(); got != want {
+		t.Errorf("Markdown() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestReleaseNotes(t *testing.T) {
+	c, err := Parse([]byte(`{
+  "project": "demo",
+  "repository": "https://github.com/acme/demo",
+  "releases": [
+    {"version": "v1.1.0", "date": "2026-02-01", "fixed": [{"description": "Crash", "commit": "bbb2222", "references": [{"id": "#12", "url": "https://github.com/acme/demo/pull/12"}, {"id": "acme/lib#3"}]}]},
+    {"version": "v1.0.0", "date": "2026-01-01", "added": [{"description": "First"}]}
+  ]
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.ReleaseNotes("1.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# demo v1.1.0\n\n" +
+		"Released 2026-02-01.\n\n" +
+		"### Fixed\n\n- Crash ([#12](https://github.com/acme/demo/pull/12), acme/lib#3) ([`bbb2222`](https://github.com/acme/demo/commit/bbb2222))\n\n" +
+		"**Full Changelog**: https://github.com/acme/demo/compare/v1.0.0...v1.1.0\n"
+	if got != want {
+		t.Errorf("ReleaseNotes() =\n%s\nwant\n%s", got, want)
+	}
+	if got, _ := c.ReleaseNotes("v1.0.0"); !strings.HasSuffix(got, "https://github.com/acme/demo/releases/tag/v1.0.0\n") {
+		t.Errorf("ReleaseNotes(first) =\n%s", got)
+	}
+	if _, err := c.ReleaseNotes("v9.0.0"); err == nil {
+		t.Error("ReleaseNotes(missing) should fail")
+	}
+
+	dir := t.TempDir()
+	if got := ReleaseNotesPath(dir, "1.1.0"); got != "RELEASE_NOTES_v1.1.0.md" {
+		t.Errorf("ReleaseNotesPath() = %s", got)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "docs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := ReleaseNotesPath(dir, "v1.1.0"); got != filepath.Join("docs", "releases", "v1.1.0.md") {
+		t.Errorf("ReleaseNotesPath(docs) = %s", got)
+	}
+
+	c.Releases[0].Entries(Fixed)[0].References = append(c.Releases[0].Entries(Fixed)[0].References, Reference{})
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "references[2]: missing id") {
+		t.Errorf("Validate() = %v, want missing reference id", err)
+	}
+}
+
+func TestRelease_Contributors(t *testing.T) {
+	doc := `{
+  "repository": "https://github.com/acme/demo",
+  "releases": [
+    {
+      "version": "v1.1.0",
+      "date": "2026-02-01",
+      "fixed": [
+        { "description": "Crash" }
+      ],
+      "contributors": [
+        { "name": "Ada Lovelace" },
+        { "name": "Grace Hopper", "first_time": true }
+      ]
+    }
+  ]
+}
+`
+	c, err := Parse([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, err := c.Bytes(); err != nil || string(out) != doc {
+		t.Errorf("Bytes() =\n%s\nwant it unchanged (%v)", out, err)
+	}
+
+	want := "### Fixed\n\n- Crash\n\n" +
+		"### Acknowledgements\n\n" +
+		"Thanks to everyone who contributed to this release:\n\n" +
+		"- Ada Lovelace\n" +
+		"- Grace Hopper (first contribution)\n\n"
+	if md := c.Markdown(); !strings.Contains(md, "## [v1.1.0] - 2026-02-01\n\n"+want+"[unreleased]:") {
+		t.Errorf("Markdown() =\n%s\nmissing\n%s", md, want)
+	}
+	if notes, _ := c.ReleaseNotes("v1.1.0"); !strings.Contains(notes, want) {
+		t.Errorf("ReleaseNotes() =\n%s\nmissing\n%s", notes, want)
+	}
+
+	// A changed release keeps its contributors
+	r := c.Release("v1.1.0")
+	r.Contributors = append(r.Contributors, Contributor{})
+	if err := r.Add(Fixed, Entry{Description: "Leak"}); err != nil {
+		t.Fatal(err)
+	}
+	out, err := c.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := Parse(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := saved.Release("v1.1.0").Contributors; len(got) != 3 || !got[1].FirstTime {
+		t.Errorf("saved contributors = %+v", got)
+	}
+	if err := saved.Validate(); err == nil || !strings.Contains(err.Error(), "contributors[2]: missing name") {
+		t.Errorf("Validate() = %v, want missing contributor name", err)
+	}
+}
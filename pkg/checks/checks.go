@@ -2,10 +2,13 @@
 package checks
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
+
+	"github.com/agentplexus/agent-team-release/pkg/proc"
 )
 
 // Result represents the result of a check.
@@ -51,7 +54,12 @@ func DefaultOptions() Options {
 
 // RunCommand executes a command and returns the result.
 func RunCommand(name string, dir string, command string, args ...string) Result {
-	cmd := exec.Command(command, args...)
+	return RunCommandContext(context.Background(), name, dir, command, args...)
+}
+
+// RunCommandContext is like RunCommand but kills the command when ctx is cancelled.
+func RunCommandContext(ctx context.Context, name string, dir string, command string, args ...string) Result {
+	cmd := proc.Command(ctx, command, args...)
 	cmd.Dir = dir
 
 	output, err := cmd.CombinedOutput()
@@ -64,6 +72,14 @@ func RunCommand(name string, dir string, command string, args ...string) Result
 	}
 }
 
+// orBackground returns ctx, or context.Background() if it is nil.
+func orBackground(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
+}
+
 // CommandExists checks if a command is available in PATH.
 func CommandExists(command string) bool {
 	_, err := exec.LookPath(command)
@@ -3,6 +3,7 @@ package checks
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -139,3 +140,115 @@ func TestPrintResults_Warnings(t *testing.T) {
 		t.Errorf("expected 1 warning, got %d", warnings)
 	}
 }
+
+func TestCheckManifestVersions(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"version": "2.2.0"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c := &ReleaseChecker{}
+
+	result := c.checkManifestVersions(dir, "v2.3.0", false)
+	if result.Passed || !strings.Contains(result.Output, "package.json:1 version is 2.2.0, want 2.3.0") {
+		t.Errorf("mismatch = %+v", result)
+	}
+	if result := c.checkManifestVersions(dir, "v2.2.0", false); !result.Passed {
+		t.Errorf("match = %+v", result)
+	}
+	if result := c.checkManifestVersions(dir, "v2.3.0", true); !result.Skipped {
+		t.Errorf("pending bump = %+v", result)
+	}
+	if result := c.checkManifestVersions(t.TempDir(), "v2.3.0", false); !result.Skipped {
+		t.Errorf("no manifests = %+v", result)
+	}
+}
+
+func TestPMChecker_Changelog(t *testing.T) {
+	dir := t.TempDir()
+	changelog := `{
+  "releases": [
+    {
+      "version": "v2.0.0",
+      "date": "2026-02-01",
+      "highlights": [{ "description": "New API" }],
+      "breaking": [{ "description": "Dropped v1 endpoints" }],
+      "changed": [{ "description": "Renamed --out", "breaking": true }, { "description": "Faster startup" }],
+      "deprecated": [{ "description": "Old config keys" }],
+      "fixed": [{ "description": "Crash on start" }]
+    }
+  ]
+}`
+	if err := os.WriteFile(filepath.Join(dir, "CHANGELOG.json"), []byte(changelog), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results := make(map[string]Result)
+	for _, r := range (&PMChecker{}).Check(dir, PMOptions{Version: "2.0.0"}) {
+		results[r.Name] = r
+	}
+	want := map[string]string{
+		"PM: release-scope":       "5 changes documented",
+		"PM: changelog-quality":   "1 highlights present",
+		"PM: breaking-changes":    "2 breaking changes documented",
+		"PM: deprecation-notices": "1 deprecation notices",
+	}
+	for name, output := range want {
+		if r := results[name]; !r.Passed || r.Output != output {
+			t.Errorf("%s = %+v, want passed with %q", name, r, output)
+		}
+	}
+
+	results = make(map[string]Result)
+	for _, r := range (&PMChecker{}).Check(dir, PMOptions{Version: "v3.0.0"}) {
+		results[r.Name] = r
+	}
+	if r := results["PM: release-scope"]; !r.Warning || r.Reason != "Version v3.0.0 not found in CHANGELOG.json" {
+		t.Errorf("release-scope for a missing version = %+v", r)
+	}
+
+	if r := (&ReleaseChecker{}).checkChangelogJSON(dir); !r.Passed {
+		t.Errorf("checkChangelogJSON() = %+v", r)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "CHANGELOG.json"), []byte(`{"releases": [{"version": "next"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if r := (&ReleaseChecker{}).checkChangelogJSON(dir); r.Passed || !strings.Contains(r.Output, "release next: invalid version") {
+		t.Errorf("checkChangelogJSON(invalid) = %+v", r)
+	}
+}
+
+func TestPMChecker_RoadmapAlignment(t *testing.T) {
+	dir := t.TempDir()
+	c := &PMChecker{}
+	if r := c.checkRoadmapAlignment(dir, "v0.2.0"); r.Passed || r.Reason != "ROADMAP.json not found" {
+		t.Errorf("missing roadmap = %+v", r)
+	}
+
+	roadmap := `{
+  "items": [
+    {"id": "python", "title": "Python checks", "status": "in_progress", "version": "0.2.0"},
+    {"id": "rust", "title": "Rust checks", "status": "planned", "version": "0.2.0"},
+    {"id": "config", "title": "Config file", "status": "completed", "version": "0.2.0"},
+    {"id": "detect", "title": "Detection", "status": "completed", "version": "0.1.0"}
+  ]
+}`
+	if err := os.WriteFile(filepath.Join(dir, "ROADMAP.json"), []byte(roadmap), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := c.checkRoadmapAlignment(dir, "v0.2.0")
+	if r.Passed || !r.Warning || r.Reason != "1/3 roadmap items completed (2 open)" {
+		t.Errorf("open items = %+v", r)
+	}
+	want := "1/3 roadmap items completed (2 open)\n- python: Python checks (in_progress)\n- rust: Rust checks (planned)"
+	if r.Output != want {
+		t.Errorf("Output = %q, want %q", r.Output, want)
+	}
+
+	if r := c.checkRoadmapAlignment(dir, "0.1.0"); !r.Passed || r.Output != "1/1 items completed" {
+		t.Errorf("completed items = %+v", r)
+	}
+	if r := c.checkRoadmapAlignment(dir, "v0.3.0"); !r.Passed || !r.Warning || r.Output != "No roadmap items tagged for v0.3.0" {
+		t.Errorf("untargeted version = %+v", r)
+	}
+}
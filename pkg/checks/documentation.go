@@ -9,6 +9,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/agentplexus/agent-team-release/pkg/changelog"
 )
 
 // DocChecker implements documentation checks.
@@ -191,19 +193,11 @@ func (c *DocChecker) checkReleaseNotes(dir string, version string) Result {
 		}
 	}
 
-	// Determine expected location
-	docsDir := filepath.Join(dir, "docs")
-	var expectedPath string
-	if FileExists(docsDir) {
-		expectedPath = fmt.Sprintf("docs/releases/%s.md", versionWithV)
-	} else {
-		expectedPath = fmt.Sprintf("RELEASE_NOTES_%s.md", versionWithV)
-	}
-
 	return Result{
 		Name:   name,
 		Passed: false,
-		Output: fmt.Sprintf("Release notes not found. Expected: %s", expectedPath),
+		Output: fmt.Sprintf("Release notes not found. Expected: %s (run: atrelease changelog --version=%s --notes)",
+			filepath.ToSlash(changelog.ReleaseNotesPath(dir, versionWithV)), versionWithV),
 	}
 }
 
@@ -219,7 +213,7 @@ func (c *DocChecker) checkChangelog(dir string) Result {
 				Name:    name,
 				Warning: true,
 				Passed:  false,
-				Output:  "CHANGELOG.json exists but CHANGELOG.md not generated. Run: schangelog generate",
+				Output:  "CHANGELOG.json exists but CHANGELOG.md not generated. Run: atrelease changelog",
 			}
 		}
 		return Result{
@@ -5,12 +5,15 @@
 package checks
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/agentplexus/agent-team-release/pkg/changelog"
+	"github.com/agentplexus/agent-team-release/pkg/roadmap"
 )
 
 // PMChecker validates product management concerns for a release.
@@ -92,56 +95,23 @@ func (c *PMChecker) checkVersionRecommendation(dir, version string) Result {
 func (c *PMChecker) checkReleaseScope(dir, version string) Result {
 	name := "PM: release-scope"
 
-	// Check CHANGELOG.json for the version entry
-	changelogPath := filepath.Join(dir, "CHANGELOG.json")
-	data, err := os.ReadFile(changelogPath)
+	release, err := changelogRelease(dir, version)
 	if err != nil {
-		return Result{
-			Name:    name,
-			Passed:  false,
-			Warning: true,
-			Reason:  "CHANGELOG.json not found",
-		}
-	}
-
-	var changelog struct {
-		Releases []struct {
-			Version    string `json:"version"`
-			Highlights []struct {
-				Description string `json:"description"`
-			} `json:"highlights"`
-			Added   []interface{} `json:"added"`
-			Changed []interface{} `json:"changed"`
-			Fixed   []interface{} `json:"fixed"`
-		} `json:"releases"`
+		return changelogWarning(name, err)
 	}
-
-	if err := json.Unmarshal(data, &changelog); err != nil {
+	if release == nil {
 		return Result{
 			Name:    name,
 			Passed:  false,
 			Warning: true,
-			Reason:  "Failed to parse CHANGELOG.json",
-		}
-	}
-
-	// Find the version entry
-	for _, release := range changelog.Releases {
-		if release.Version == version {
-			totalChanges := len(release.Added) + len(release.Changed) + len(release.Fixed)
-			return Result{
-				Name:   name,
-				Passed: true,
-				Output: fmt.Sprintf("%d changes documented", totalChanges),
-			}
+			Reason:  fmt.Sprintf("Version %s not found in CHANGELOG.json", version),
 		}
 	}
 
 	return Result{
-		Name:    name,
-		Passed:  false,
-		Warning: true,
-		Reason:  fmt.Sprintf("Version %s not found in CHANGELOG.json", version),
+		Name:   name,
+		Passed: true,
+		Output: fmt.Sprintf("%d changes documented", release.Changes()),
 	}
 }
 
@@ -149,59 +119,32 @@ func (c *PMChecker) checkReleaseScope(dir, version string) Result {
 func (c *PMChecker) checkChangelogQuality(dir, version string) Result {
 	name := "PM: changelog-quality"
 
-	changelogPath := filepath.Join(dir, "CHANGELOG.json")
-	data, err := os.ReadFile(changelogPath)
+	release, err := changelogRelease(dir, version)
 	if err != nil {
+		return changelogWarning(name, err)
+	}
+	if release == nil {
 		return Result{
 			Name:    name,
 			Passed:  false,
 			Warning: true,
-			Reason:  "CHANGELOG.json not found",
+			Reason:  fmt.Sprintf("Version %s not found in CHANGELOG.json", version),
 		}
 	}
 
-	var changelog struct {
-		Releases []struct {
-			Version    string `json:"version"`
-			Highlights []struct {
-				Description string `json:"description"`
-			} `json:"highlights"`
-		} `json:"releases"`
-	}
-
-	if err := json.Unmarshal(data, &changelog); err != nil {
+	highlights := release.Entries(changelog.Highlights)
+	if len(highlights) == 0 {
 		return Result{
 			Name:    name,
 			Passed:  false,
 			Warning: true,
-			Reason:  "Failed to parse CHANGELOG.json",
+			Reason:  "No highlights for this release",
 		}
 	}
-
-	// Find the version entry
-	for _, release := range changelog.Releases {
-		if release.Version == version {
-			if len(release.Highlights) == 0 {
-				return Result{
-					Name:    name,
-					Passed:  false,
-					Warning: true,
-					Reason:  "No highlights for this release",
-				}
-			}
-			return Result{
-				Name:   name,
-				Passed: true,
-				Output: fmt.Sprintf("%d highlights present", len(release.Highlights)),
-			}
-		}
-	}
-
 	return Result{
-		Name:    name,
-		Passed:  false,
-		Warning: true,
-		Reason:  fmt.Sprintf("Version %s not found in CHANGELOG.json", version),
+		Name:   name,
+		Passed: true,
+		Output: fmt.Sprintf("%d highlights present", len(highlights)),
 	}
 }
 
@@ -209,98 +152,54 @@ func (c *PMChecker) checkChangelogQuality(dir, version string) Result {
 func (c *PMChecker) checkBreakingChanges(dir, version string) Result {
 	name := "PM: breaking-changes"
 
-	changelogPath := filepath.Join(dir, "CHANGELOG.json")
-	data, err := os.ReadFile(changelogPath)
+	release, err := changelogRelease(dir, version)
 	if err != nil {
-		return Result{
-			Name:    name,
-			Passed:  false,
-			Warning: true,
-			Reason:  "CHANGELOG.json not found",
-		}
-	}
-
-	var changelog struct {
-		Releases []struct {
-			Version string `json:"version"`
-			Changed []struct {
-				Description string `json:"description"`
-				Breaking    bool   `json:"breaking"`
-			} `json:"changed"`
-		} `json:"releases"`
+		return changelogWarning(name, err)
 	}
-
-	if err := json.Unmarshal(data, &changelog); err != nil {
+	if release == nil {
 		return Result{
-			Name:    name,
-			Passed:  false,
-			Warning: true,
-			Reason:  "Failed to parse CHANGELOG.json",
+			Name:   name,
+			Passed: true,
+			Output: "No breaking changes (version not in changelog)",
 		}
 	}
 
-	// Find the version entry and count breaking changes
-	for _, release := range changelog.Releases {
-		if release.Version == version {
-			breakingCount := 0
-			for _, change := range release.Changed {
-				if change.Breaking {
-					breakingCount++
-				}
-			}
-
-			if breakingCount == 0 {
-				return Result{
-					Name:   name,
-					Passed: true,
-					Output: "No breaking changes",
-				}
-			}
-
-			return Result{
-				Name:   name,
-				Passed: true,
-				Output: fmt.Sprintf("%d breaking changes documented", breakingCount),
-			}
+	breaking := len(release.BreakingChanges())
+	if breaking == 0 {
+		return Result{
+			Name:   name,
+			Passed: true,
+			Output: "No breaking changes",
 		}
 	}
-
 	return Result{
 		Name:   name,
 		Passed: true,
-		Output: "No breaking changes (version not in changelog)",
+		Output: fmt.Sprintf("%d breaking changes documented", breaking),
 	}
 }
 
-// checkRoadmapAlignment validates the release aligns with roadmap items.
+// checkRoadmapAlignment validates the items ROADMAP.json targets at the
+// release are completed, naming those still open.
 func (c *PMChecker) checkRoadmapAlignment(dir, version string) Result {
 	name := "PM: roadmap-alignment"
 
-	roadmapPath := filepath.Join(dir, "ROADMAP.md")
-	data, err := os.ReadFile(roadmapPath)
+	rm, err := roadmap.Load(filepath.Join(dir, roadmap.FileName))
 	if err != nil {
+		reason := "Failed to parse ROADMAP.json"
+		if errors.Is(err, os.ErrNotExist) {
+			reason = "ROADMAP.json not found"
+		}
 		return Result{
 			Name:    name,
 			Passed:  false,
 			Warning: true,
-			Reason:  "ROADMAP.md not found",
+			Reason:  reason,
 		}
 	}
 
-	content := string(data)
-
-	// Count completed vs total items for this version
-	// Look for patterns like "**Version:** 0.5.0" after "### [x]" or "### [ ]"
-	versionNum := strings.TrimPrefix(version, "v")
-
-	completedPattern := regexp.MustCompile(`### \[x\][^\n]+\n[^\n]*\n\*\*Version:\*\* ` + regexp.QuoteMeta(versionNum))
-	pendingPattern := regexp.MustCompile(`### \[ \][^\n]+\n[^\n]*\n\*\*Version:\*\* ` + regexp.QuoteMeta(versionNum))
-
-	completed := len(completedPattern.FindAllString(content, -1))
-	pending := len(pendingPattern.FindAllString(content, -1))
-	total := completed + pending
-
-	if total == 0 {
+	targeted := rm.Targeting(version)
+	if version == "" || len(targeted) == 0 {
 		return Result{
 			Name:    name,
 			Passed:  true,
@@ -309,19 +208,27 @@ func (c *PMChecker) checkRoadmapAlignment(dir, version string) Result {
 		}
 	}
 
-	if pending > 0 {
+	open := rm.Open(version)
+	completed := len(targeted) - len(open)
+	if len(open) > 0 {
+		reason := fmt.Sprintf("%d/%d roadmap items completed (%d open)", completed, len(targeted), len(open))
+		lines := []string{reason}
+		for _, item := range open {
+			lines = append(lines, fmt.Sprintf("- %s: %s (%s)", item.ID, item.Title, item.Status))
+		}
 		return Result{
 			Name:    name,
 			Passed:  false,
 			Warning: true,
-			Reason:  fmt.Sprintf("%d/%d roadmap items completed (%d pending)", completed, total, pending),
+			Reason:  reason,
+			Output:  strings.Join(lines, "\n"),
 		}
 	}
 
 	return Result{
 		Name:   name,
 		Passed: true,
-		Output: fmt.Sprintf("%d/%d items completed", completed, total),
+		Output: fmt.Sprintf("%d/%d items completed", completed, len(targeted)),
 	}
 }
 
@@ -329,26 +236,15 @@ func (c *PMChecker) checkRoadmapAlignment(dir, version string) Result {
 func (c *PMChecker) checkDeprecationNotices(dir, version string) Result {
 	name := "PM: deprecation-notices"
 
-	changelogPath := filepath.Join(dir, "CHANGELOG.json")
-	data, err := os.ReadFile(changelogPath)
-	if err != nil {
+	release, err := changelogRelease(dir, version)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
 		return Result{
 			Name:   name,
 			Passed: true,
 			Output: "No deprecations (CHANGELOG.json not found)",
 		}
-	}
-
-	var changelog struct {
-		Releases []struct {
-			Version    string `json:"version"`
-			Deprecated []struct {
-				Description string `json:"description"`
-			} `json:"deprecated"`
-		} `json:"releases"`
-	}
-
-	if err := json.Unmarshal(data, &changelog); err != nil {
+	case err != nil:
 		return Result{
 			Name:   name,
 			Passed: true,
@@ -356,27 +252,44 @@ func (c *PMChecker) checkDeprecationNotices(dir, version string) Result {
 		}
 	}
 
-	// Find the version entry
-	for _, release := range changelog.Releases {
-		if release.Version == version {
-			if len(release.Deprecated) == 0 {
-				return Result{
-					Name:   name,
-					Passed: true,
-					Output: "No deprecations",
-				}
-			}
-			return Result{
-				Name:   name,
-				Passed: true,
-				Output: fmt.Sprintf("%d deprecation notices", len(release.Deprecated)),
-			}
+	deprecated := 0
+	if release != nil {
+		deprecated = len(release.Entries(changelog.Deprecated))
+	}
+	if deprecated == 0 {
+		return Result{
+			Name:   name,
+			Passed: true,
+			Output: "No deprecations",
 		}
 	}
-
 	return Result{
 		Name:   name,
 		Passed: true,
-		Output: "No deprecations",
+		Output: fmt.Sprintf("%d deprecation notices", deprecated),
+	}
+}
+
+// changelogRelease returns the CHANGELOG.json entry for version, or nil if
+// there is none. A missing changelog is an os.ErrNotExist error.
+func changelogRelease(dir, version string) (*changelog.Release, error) {
+	c, err := changelog.Load(filepath.Join(dir, changelog.FileName))
+	if err != nil || version == "" {
+		return nil, err
+	}
+	return c.Release(version), nil
+}
+
+// changelogWarning reports a CHANGELOG.json that is missing or can't be parsed.
+func changelogWarning(name string, err error) Result {
+	reason := "Failed to parse CHANGELOG.json"
+	if errors.Is(err, os.ErrNotExist) {
+		reason = "CHANGELOG.json not found"
+	}
+	return Result{
+		Name:    name,
+		Passed:  false,
+		Warning: true,
+		Reason:  reason,
 	}
 }
@@ -5,10 +5,14 @@
 package checks
 
 import (
+	"context"
 	"fmt"
-	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/agentplexus/agent-team-release/pkg/changelog"
+	"github.com/agentplexus/agent-team-release/pkg/detect"
+	"github.com/agentplexus/agent-team-release/pkg/proc"
 )
 
 // ReleaseChecker implements release management checks.
@@ -22,21 +26,44 @@ func (c *ReleaseChecker) Name() string {
 // ReleaseOptions configures release checks.
 type ReleaseOptions struct {
 	Version string // Target release version (e.g., "v0.2.0")
+	Tag     string // Release tag, if not the version (e.g., "sdk/go/v0.2.0")
 	Verbose bool
+
+	// ManifestDir is the directory whose manifest versions must match the
+	// release version (default: the checked directory)
+	ManifestDir string
+
+	// BumpPending skips the manifest version check because the release
+	// bumps the manifests later (dry runs, or a bump step still to run)
+	BumpPending bool
+
+	Context context.Context // Cancels the checks' git commands (nil = never)
 }
 
 // Check runs release management checks on the specified directory.
 func (c *ReleaseChecker) Check(dir string, opts ReleaseOptions) []Result {
 	var results []Result
+	ctx := orBackground(opts.Context)
 
 	// Check version format and availability
-	results = append(results, c.checkVersionAvailable(dir, opts.Version))
+	tag := opts.Tag
+	if tag == "" {
+		tag = opts.Version
+	}
+	results = append(results, c.checkVersionAvailable(ctx, dir, tag))
+
+	// Check manifest versions agree with the release version
+	manifestDir := opts.ManifestDir
+	if manifestDir == "" {
+		manifestDir = dir
+	}
+	results = append(results, c.checkManifestVersions(manifestDir, opts.Version, opts.BumpPending))
 
 	// Check git status (clean working directory for release)
-	results = append(results, c.checkGitStatus(dir))
+	results = append(results, c.checkGitStatus(ctx, dir))
 
 	// Check git remote is configured
-	results = append(results, c.checkGitRemote(dir))
+	results = append(results, c.checkGitRemote(ctx, dir))
 
 	// Check CHANGELOG.json exists and is valid
 	results = append(results, c.checkChangelogJSON(dir))
@@ -47,7 +74,7 @@ func (c *ReleaseChecker) Check(dir string, opts ReleaseOptions) []Result {
 	return results
 }
 
-func (c *ReleaseChecker) checkVersionAvailable(dir string, version string) Result {
+func (c *ReleaseChecker) checkVersionAvailable(ctx context.Context, dir string, version string) Result {
 	name := "Release: version available"
 
 	if version == "" {
@@ -58,13 +85,13 @@ func (c *ReleaseChecker) checkVersionAvailable(dir string, version string) Resul
 		}
 	}
 
-	// Ensure version has v prefix
-	if !strings.HasPrefix(version, "v") {
-		version = "v" + version
+	// Ensure version has v prefix, after any module prefix
+	if i := strings.LastIndex(version, "/") + 1; !strings.HasPrefix(version[i:], "v") {
+		version = version[:i] + "v" + version[i:]
 	}
 
 	// Check if tag already exists
-	cmd := exec.Command("git", "tag", "-l", version)
+	cmd := proc.Command(ctx, "git", "tag", "-l", version)
 	cmd.Dir = dir
 	output, err := cmd.Output()
 	if err != nil {
@@ -90,10 +117,65 @@ func (c *ReleaseChecker) checkVersionAvailable(dir string, version string) Resul
 	}
 }
 
-func (c *ReleaseChecker) checkGitStatus(dir string) Result {
+func (c *ReleaseChecker) checkManifestVersions(dir, version string, bumpPending bool) Result {
+	name := "Release: manifest versions"
+
+	if version == "" {
+		return Result{
+			Name:    name,
+			Skipped: true,
+			Reason:  "No version specified",
+		}
+	}
+	if bumpPending {
+		return Result{
+			Name:    name,
+			Skipped: true,
+			Reason:  "Versions are bumped by the release",
+		}
+	}
+
+	fields, err := detect.VersionFields(dir)
+	if err != nil {
+		return Result{
+			Name:   name,
+			Passed: false,
+			Error:  err,
+		}
+	}
+	if len(fields) == 0 {
+		return Result{
+			Name:    name,
+			Skipped: true,
+			Reason:  "No version fields found",
+		}
+	}
+
+	var mismatches []string
+	for _, f := range fields {
+		if want := f.Format(version); f.Value != want {
+			mismatches = append(mismatches, fmt.Sprintf("%s:%d %s is %s, want %s", f.File, f.Line, f.Key, f.Value, want))
+		}
+	}
+	if len(mismatches) > 0 {
+		return Result{
+			Name:   name,
+			Passed: false,
+			Output: strings.Join(mismatches, "; ") + ". Run: atrelease bump " + version,
+		}
+	}
+
+	return Result{
+		Name:   name,
+		Passed: true,
+		Output: fmt.Sprintf("%d version field(s) match %s", len(fields), version),
+	}
+}
+
+func (c *ReleaseChecker) checkGitStatus(ctx context.Context, dir string) Result {
 	name := "Release: git working directory"
 
-	cmd := exec.Command("git", "status", "--porcelain")
+	cmd := proc.Command(ctx, "git", "status", "--porcelain")
 	cmd.Dir = dir
 	output, err := cmd.Output()
 	if err != nil {
@@ -126,10 +208,10 @@ func (c *ReleaseChecker) checkGitStatus(dir string) Result {
 	}
 }
 
-func (c *ReleaseChecker) checkGitRemote(dir string) Result {
+func (c *ReleaseChecker) checkGitRemote(ctx context.Context, dir string) Result {
 	name := "Release: git remote"
 
-	cmd := exec.Command("git", "remote", "get-url", "origin")
+	cmd := proc.Command(ctx, "git", "remote", "get-url", "origin")
 	cmd.Dir = dir
 	output, err := cmd.Output()
 	if err != nil {
@@ -150,25 +232,25 @@ func (c *ReleaseChecker) checkGitRemote(dir string) Result {
 
 func (c *ReleaseChecker) checkChangelogJSON(dir string) Result {
 	name := "Release: CHANGELOG.json"
-	changelogPath := filepath.Join(dir, "CHANGELOG.json")
+	changelogPath := filepath.Join(dir, changelog.FileName)
 
 	if !FileExists(changelogPath) {
 		return Result{
 			Name:   name,
 			Passed: false,
-			Output: "CHANGELOG.json not found. Run: schangelog init",
+			Output: "CHANGELOG.json not found",
 		}
 	}
 
-	// Validate with schangelog if available
-	if CommandExists("schangelog") {
-		result := RunCommand("validate", dir, "schangelog", "validate", "CHANGELOG.json")
-		if !result.Passed {
-			return Result{
-				Name:   name,
-				Passed: false,
-				Output: "CHANGELOG.json validation failed",
-			}
+	cl, err := changelog.Load(changelogPath)
+	if err == nil {
+		err = cl.Validate()
+	}
+	if err != nil {
+		return Result{
+			Name:   name,
+			Passed: false,
+			Output: "CHANGELOG.json validation failed: " + strings.ReplaceAll(err.Error(), "\n", "; "),
 		}
 	}
 
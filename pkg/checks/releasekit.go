@@ -1,15 +1,24 @@
 package checks
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 
 	multiagentspec "github.com/agentplexus/multi-agent-spec/sdk/go"
+
+	"github.com/agentplexus/agent-team-release/pkg/proc"
 )
 
 // RunReleasekit executes `releasekit validate` and returns the results as checks.Result.
 // It shells out to the releasekit CLI and parses the AgentResult JSON output.
 func RunReleasekit(dir string, opts Options) ([]Result, error) {
+	return RunReleasekitContext(context.Background(), dir, opts)
+}
+
+// RunReleasekitContext is like RunReleasekit but kills releasekit, and any
+// linters or tests it started, when ctx is cancelled.
+func RunReleasekitContext(ctx context.Context, dir string, opts Options) ([]Result, error) {
 	args := []string{"validate", "--format", "json"}
 
 	if !opts.Lint {
@@ -27,8 +36,11 @@ func RunReleasekit(dir string, opts Options) ([]Result, error) {
 
 	args = append(args, dir)
 
-	cmd := exec.Command("releasekit", args...)
+	cmd := proc.Command(ctx, "releasekit", args...)
 	output, err := cmd.Output()
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("releasekit: %w", ctx.Err())
+	}
 
 	// releasekit exits with code 2 for NO-GO, which is not an error for our purposes
 	if err != nil {
@@ -120,7 +132,7 @@ func RunReleasekitRaw(dir string, opts Options) (*multiagentspec.AgentResult, er
 
 	args = append(args, dir)
 
-	cmd := exec.Command("releasekit", args...)
+	cmd := proc.Command(context.Background(), "releasekit", args...)
 	output, err := cmd.Output()
 
 	if err != nil {
@@ -5,9 +5,11 @@
 package checks
 
 import (
-	"os/exec"
+	"context"
 	"path/filepath"
 	"strings"
+
+	"github.com/agentplexus/agent-team-release/pkg/proc"
 )
 
 // SecurityChecker implements security and compliance checks.
@@ -20,29 +22,32 @@ func (c *SecurityChecker) Name() string {
 
 // SecurityOptions configures security checks.
 type SecurityOptions struct {
+	Root    string // Repository root, searched for a LICENSE when dir is a module within it
 	Verbose bool
+	Context context.Context // Cancels the checks' commands (nil = never)
 }
 
 // Check runs security checks on the specified directory.
 func (c *SecurityChecker) Check(dir string, opts SecurityOptions) []Result {
 	var results []Result
+	ctx := orBackground(opts.Context)
 
 	// Check LICENSE file exists
-	results = append(results, c.checkLicense(dir))
+	results = append(results, c.checkLicense(dir, opts.Root))
 
 	// Check for known vulnerabilities (Go)
-	results = append(results, c.checkGoVulncheck(dir))
+	results = append(results, c.checkGoVulncheck(ctx, dir))
 
 	// Check for dependency audit (Go)
-	results = append(results, c.checkGoModAudit(dir))
+	results = append(results, c.checkGoModAudit(ctx, dir))
 
 	// Check for secrets in code
-	results = append(results, c.checkNoSecrets(dir))
+	results = append(results, c.checkNoSecrets(ctx, dir))
 
 	return results
 }
 
-func (c *SecurityChecker) checkLicense(dir string) Result {
+func (c *SecurityChecker) checkLicense(dir, root string) Result {
 	name := "Security: LICENSE file"
 
 	licenseFiles := []string{
@@ -62,6 +67,19 @@ func (c *SecurityChecker) checkLicense(dir string) Result {
 		}
 	}
 
+	// A module inside a repository is covered by the repository's LICENSE
+	if root != "" && root != dir {
+		for _, f := range licenseFiles {
+			if FileExists(filepath.Join(root, f)) {
+				return Result{
+					Name:   name,
+					Passed: true,
+					Output: filepath.Join(root, f),
+				}
+			}
+		}
+	}
+
 	return Result{
 		Name:   name,
 		Passed: false,
@@ -69,7 +87,7 @@ func (c *SecurityChecker) checkLicense(dir string) Result {
 	}
 }
 
-func (c *SecurityChecker) checkGoVulncheck(dir string) Result {
+func (c *SecurityChecker) checkGoVulncheck(ctx context.Context, dir string) Result {
 	name := "Security: vulnerability scan"
 
 	// Check if this is a Go project
@@ -91,7 +109,7 @@ func (c *SecurityChecker) checkGoVulncheck(dir string) Result {
 	}
 
 	// Run govulncheck
-	result := RunCommand(name, dir, "govulncheck", "./...")
+	result := RunCommandContext(ctx, name, dir, "govulncheck", "./...")
 	if !result.Passed {
 		// Check if it's a real vulnerability or just an error
 		if strings.Contains(result.Output, "Vulnerability") {
@@ -110,7 +128,7 @@ func (c *SecurityChecker) checkGoVulncheck(dir string) Result {
 	}
 }
 
-func (c *SecurityChecker) checkGoModAudit(dir string) Result {
+func (c *SecurityChecker) checkGoModAudit(ctx context.Context, dir string) Result {
 	name := "Security: dependency audit"
 
 	// Check if this is a Go project
@@ -123,7 +141,7 @@ func (c *SecurityChecker) checkGoModAudit(dir string) Result {
 	}
 
 	// Use go list to check for dependency issues
-	cmd := exec.Command("go", "list", "-m", "-json", "all")
+	cmd := proc.Command(ctx, "go", "list", "-m", "-json", "all")
 	cmd.Dir = dir
 	_, err := cmd.Output()
 	if err != nil {
@@ -136,7 +154,7 @@ func (c *SecurityChecker) checkGoModAudit(dir string) Result {
 	}
 
 	// Check for retracted versions
-	cmd = exec.Command("go", "list", "-m", "-u", "-retracted", "all")
+	cmd = proc.Command(ctx, "go", "list", "-m", "-u", "-retracted", "all")
 	cmd.Dir = dir
 	output, _ := cmd.Output()
 
@@ -155,7 +173,7 @@ func (c *SecurityChecker) checkGoModAudit(dir string) Result {
 	}
 }
 
-func (c *SecurityChecker) checkNoSecrets(dir string) Result {
+func (c *SecurityChecker) checkNoSecrets(ctx context.Context, dir string) Result {
 	name := "Security: no hardcoded secrets"
 
 	// Check for common secret patterns in Go files
@@ -173,7 +191,7 @@ func (c *SecurityChecker) checkNoSecrets(dir string) Result {
 
 	for _, pattern := range secretPatterns {
 		// Exclude this file (security.go) which contains the patterns as string literals
-		cmd := exec.Command("grep", "-r", "-i", "-l", "--include=*.go", "--exclude=security.go", pattern, ".")
+		cmd := proc.Command(ctx, "grep", "-r", "-i", "-l", "--include=*.go", "--exclude=security.go", pattern, ".")
 		cmd.Dir = dir
 		output, err := cmd.Output()
 
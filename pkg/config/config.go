@@ -2,7 +2,9 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"sort"
 
 	"gopkg.in/yaml.v3"
 )
@@ -14,6 +16,13 @@ type Config struct {
 
 	// Language-specific settings
 	Languages map[string]LanguageConfig `yaml:"languages"`
+
+	// Custom workflows, keyed by name
+	Workflows map[string]WorkflowConfig `yaml:"workflows"`
+
+	// Hook scripts run around workflow steps, keyed by step reference
+	// (e.g., "push"), step name, or "*" for every step
+	Hooks map[string]HookSet `yaml:"hooks"`
 }
 
 // LanguageConfig holds settings for a specific language.
@@ -29,11 +38,61 @@ type LanguageConfig struct {
 	ExcludeCoverage string `yaml:"exclude_coverage"` // directories to exclude from coverage
 }
 
+// WorkflowConfig defines a named custom workflow.
+type WorkflowConfig struct {
+	Description string       `yaml:"description"`
+	Steps       []StepConfig `yaml:"steps"`
+}
+
+// StepConfig defines a single workflow step. Exactly one of Uses or Run must be set.
+type StepConfig struct {
+	Name         string            `yaml:"name"`         // display name (defaults to the built-in name or command)
+	Uses         string            `yaml:"uses"`         // built-in step reference (e.g., "changelog", "tag")
+	Run          string            `yaml:"run"`          // shell command
+	Env          map[string]string `yaml:"env"`          // extra environment variables for Run
+	Dir          string            `yaml:"working_dir"`  // working directory for Run, relative to the repo
+	Required     *bool             `yaml:"required"`     // nil means the built-in default, or true for Run
+	Timeout      string            `yaml:"timeout"`      // maximum step duration (e.g., "5m"); empty means no limit
+	DependsOn    []string          `yaml:"depends_on"`   // names of steps that must finish first
+	Irreversible bool              `yaml:"irreversible"` // ask for approval in interactive mode (e.g., publish)
+	Retry        *RetryConfig      `yaml:"retry"`        // nil means the built-in default, or no retries for Run
+}
+
+// RetryConfig defines how a failing step is retried with exponential backoff.
+type RetryConfig struct {
+	Attempts int     `yaml:"attempts"`  // total attempts including the first
+	Delay    string  `yaml:"delay"`     // wait before the first retry (default "1s"); doubles after each
+	MaxDelay string  `yaml:"max_delay"` // upper bound on the wait; empty means no limit
+	Jitter   float64 `yaml:"jitter"`    // fraction of each wait randomized, from 0 to 1
+}
+
+// HookSet holds the hooks for a step.
+type HookSet struct {
+	Before    []HookConfig `yaml:"before"`     // run before the step; a blocking failure prevents it
+	After     []HookConfig `yaml:"after"`      // run after the step succeeds; a blocking failure fails it
+	OnFailure []HookConfig `yaml:"on_failure"` // run after the step fails; never blocks
+}
+
+// HookConfig defines a single hook command.
+type HookConfig struct {
+	Run     string            `yaml:"run"`      // shell command
+	Env     map[string]string `yaml:"env"`      // extra environment variables
+	Timeout string            `yaml:"timeout"`  // duration (e.g., "30s"); empty means no limit
+	OnError string            `yaml:"on_error"` // "block" (default) or "warn"
+}
+
+// Blocking returns true if a failure of this hook should fail the step.
+func (h HookConfig) Blocking() bool {
+	return h.OnError != "warn"
+}
+
 // DefaultConfig returns a configuration with sensible defaults.
 func DefaultConfig() Config {
 	return Config{
 		Verbose:   false,
 		Languages: make(map[string]LanguageConfig),
+		Workflows: make(map[string]WorkflowConfig),
+		Hooks:     make(map[string]HookSet),
 	}
 }
 
@@ -65,10 +124,40 @@ func Load(dir string) (Config, error) {
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return cfg, err
 	}
+	if err := cfg.validateHooks(); err != nil {
+		return cfg, err
+	}
 
 	return cfg, nil
 }
 
+// validateHooks rejects hooks whose on_error is neither "block" nor "warn",
+// so a typo doesn't silently make a hook blocking.
+func (c *Config) validateHooks() error {
+	steps := make([]string, 0, len(c.Hooks))
+	for step := range c.Hooks {
+		steps = append(steps, step)
+	}
+	sort.Strings(steps)
+
+	for _, step := range steps {
+		set := c.Hooks[step]
+		for _, point := range []struct {
+			name  string
+			hooks []HookConfig
+		}{{"before", set.Before}, {"after", set.After}, {"on_failure", set.OnFailure}} {
+			for i, h := range point.hooks {
+				switch h.OnError {
+				case "", "block", "warn":
+				default:
+					return fmt.Errorf("hooks.%s.%s[%d]: on_error must be \"block\" or \"warn\", got %q", step, point.name, i, h.OnError)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // IsLanguageEnabled checks if a language is enabled in config.
 // Returns true if enabled is nil (auto-detect) or explicitly true.
 func (c *Config) IsLanguageEnabled(lang string) bool {
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -161,3 +162,81 @@ func TestBoolPtr(t *testing.T) {
 		t.Error("expected BoolPtr(false) to return pointer to false")
 	}
 }
+
+func TestLoad_Workflows(t *testing.T) {
+	dir := t.TempDir()
+
+	configContent := `
+workflows:
+  helm:
+    description: Release with Helm chart bump
+    steps:
+      - uses: validate-version
+      - name: Bump chart
+        run: ./bump.sh
+        working_dir: deploy
+        timeout: 2m
+        required: false
+        env:
+          CHART: app
+        depends_on: ["Validate version"]
+`
+	if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte(configContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	wf, ok := cfg.Workflows["helm"]
+	if !ok {
+		t.Fatal("expected helm workflow")
+	}
+	if len(wf.Steps) != 2 {
+		t.Fatalf("expected 2 steps, got %d", len(wf.Steps))
+	}
+
+	step := wf.Steps[1]
+	if step.Run != "./bump.sh" || step.Dir != "deploy" || step.Timeout != "2m" {
+		t.Errorf("unexpected step: %+v", step)
+	}
+	if step.Required == nil || *step.Required {
+		t.Error("expected required to be false")
+	}
+	if step.Env["CHART"] != "app" {
+		t.Errorf("expected env CHART=app, got %v", step.Env)
+	}
+	if len(step.DependsOn) != 1 {
+		t.Errorf("expected 1 dependency, got %v", step.DependsOn)
+	}
+}
+
+func TestLoad_HookOnError(t *testing.T) {
+	tests := []struct {
+		onError string
+		wantErr bool
+	}{
+		{"", false},
+		{"block", false},
+		{"warn", false},
+		{"contiue", true},
+	}
+
+	for _, tt := range tests {
+		dir := t.TempDir()
+		configContent := "hooks:\n  push:\n    after:\n      - run: ./notify.sh\n        on_error: \"" + tt.onError + "\"\n"
+		if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte(configContent), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := Load(dir)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Load() with on_error %q error = %v, wantErr %v", tt.onError, err, tt.wantErr)
+		}
+		if err != nil && !strings.Contains(err.Error(), "hooks.push.after[0]") {
+			t.Errorf("error should name the hook, got: %v", err)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package detect
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// VersionFile is the name of Go source files holding version constants.
+const VersionFile = "version.go"
+
+// VersionField is a version number declared in a manifest or a Go
+// version.go file.
+type VersionField struct {
+	File  string // Path relative to the scanned directory
+	Key   string // Field holding the version, e.g. "version" or "[package] version"
+	Value string // Declared version
+	Line  int    // 1-based line of the declaration
+	Start int    // Byte offset of the value in the file
+	End   int    // Byte offset just past the value
+}
+
+// Format returns version (e.g., "v2.3.0") written the way the field writes
+// versions: manifests take it without the "v" prefix, and Go constants keep
+// whichever form they already use.
+func (f VersionField) Format(version string) string {
+	bare := strings.TrimPrefix(version, "v")
+	if strings.HasSuffix(f.File, ".go") && strings.HasPrefix(f.Value, "v") {
+		return "v" + bare
+	}
+	return bare
+}
+
+// tomlVersionSections lists the TOML tables whose "version" key is the
+// project's version, by manifest.
+var tomlVersionSections = map[string][]string{
+	"Cargo.toml":     {"package", "workspace.package"},
+	"pyproject.toml": {"project", "tool.poetry"},
+}
+
+var (
+	tomlSectionRegex = regexp.MustCompile(`^\s*\[([^\[\]]+)\]`)
+	tomlVersionRegex = regexp.MustCompile(`^\s*version\s*=\s*"([^"]*)"`)
+	goVersionRegex   = regexp.MustCompile(`(?m)^\s*(?:(?:const|var)\s+)?(Version)\s*(?:string\s*)?=\s*"(v?\d+\.\d+\.\d+[^"]*)"`)
+)
+
+// VersionFields returns the version fields of the projects detected in dir:
+// the version of each package.json, Cargo.toml and pyproject.toml, and the
+// Version string constant or variable of each version.go in a Go module.
+// Other constants such as APIVersion or MinGoVersion are versioned on their
+// own and left alone. Files inside nested Go modules are skipped, since
+// those are versioned separately.
+func VersionFields(dir string) ([]VersionField, error) {
+	detections, err := Detect(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Directories of nested Go modules, relative to dir
+	var nested []string
+	for _, d := range GetByLanguage(detections, Go) {
+		if rel, err := filepath.Rel(dir, d.Path); err == nil && rel != "." {
+			nested = append(nested, rel)
+		}
+	}
+	inNested := func(rel string) bool {
+		for _, n := range nested {
+			if strings.HasPrefix(rel, n+string(filepath.Separator)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var fields []VersionField
+	seen := make(map[string]bool)
+	scan := func(path string) error {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || seen[rel] || inNested(rel) {
+			return nil
+		}
+		seen[rel] = true
+		found, err := fileVersionFields(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		for _, f := range found {
+			f.File = rel
+			fields = append(fields, f)
+		}
+		return nil
+	}
+
+	for _, d := range detections {
+		if d.Language == Go {
+			if err := walkVersionFiles(d.Path, scan); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		for _, path := range d.Files {
+			if err := scan(path); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].File < fields[j].File })
+	return fields, nil
+}
+
+// walkVersionFiles calls fn for each version.go file in a Go module,
+// skipping hidden, vendor and testdata directories.
+func walkVersionFiles(root string, fn func(path string) error) error {
+	return filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (name[0] == '.' || name == "vendor" || name == "testdata" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() == VersionFile {
+			return fn(path)
+		}
+		return nil
+	})
+}
+
+// fileVersionFields returns the version fields declared in a file.
+func fileVersionFields(path string) ([]VersionField, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	name := filepath.Base(path)
+	switch {
+	case name == "package.json":
+		return packageJSONVersion(data)
+	case tomlVersionSections[name] != nil:
+		return tomlVersion(data, tomlVersionSections[name]), nil
+	case name == VersionFile:
+		return goVersions(data), nil
+	}
+	return nil, nil
+}
+
+// packageJSONVersion returns the top-level "version" of a package.json.
+func packageJSONVersion(data []byte) ([]VersionField, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	depth := 0
+	key := true // The next string at depth 1 is an object key
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			// No version, or not JSON the detector can read
+			return nil, nil
+		}
+		switch t := tok.(type) {
+		case json.Delim:
+			if t == '{' || t == '[' {
+				depth++
+			} else {
+				depth--
+			}
+			if depth == 1 {
+				key = true
+			}
+			continue
+		case string:
+			if depth != 1 {
+				continue
+			}
+			if !key {
+				key = true
+				continue
+			}
+			key = false
+			if t != "version" {
+				continue
+			}
+			value, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			v, ok := value.(string)
+			if !ok {
+				return nil, nil
+			}
+			end := int(dec.InputOffset()) - 1 // Before the closing quote
+			start := end - len(v)
+			if start < 0 || string(data[start:end]) != v {
+				return nil, fmt.Errorf("unsupported escapes in version %q", v)
+			}
+			return []VersionField{{
+				Key:   "version",
+				Value: v,
+				Line:  bytes.Count(data[:start], []byte("\n")) + 1,
+				Start: start,
+				End:   end,
+			}}, nil
+		default:
+			if depth == 1 {
+				key = true
+			}
+		}
+	}
+}
+
+// tomlVersion returns the "version" key of the first of sections present
+// in a TOML manifest.
+func tomlVersion(data []byte, sections []string) []VersionField {
+	section := ""
+	offset := 0
+	for i, line := range strings.SplitAfter(string(data), "\n") {
+		lineStart := offset
+		offset += len(line)
+
+		if m := tomlSectionRegex.FindStringSubmatch(line); m != nil {
+			section = strings.TrimSpace(m[1])
+			continue
+		}
+		m := tomlVersionRegex.FindStringSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+		for _, s := range sections {
+			if s == section {
+				return []VersionField{{
+					Key:   "[" + section + "] version",
+					Value: line[m[2]:m[3]],
+					Line:  i + 1,
+					Start: lineStart + m[2],
+					End:   lineStart + m[3],
+				}}
+			}
+		}
+	}
+	return nil
+}
+
+// goVersions returns the Version string constants and variables of a
+// version.go file whose values look like semantic versions.
+func goVersions(data []byte) []VersionField {
+	var fields []VersionField
+	for _, m := range goVersionRegex.FindAllSubmatchIndex(data, -1) {
+		fields = append(fields, VersionField{
+			Key:   string(data[m[2]:m[3]]),
+			Value: string(data[m[4]:m[5]]),
+			Line:  bytes.Count(data[:m[4]], []byte("\n")) + 1,
+			Start: m[4],
+			End:   m[5],
+		})
+	}
+	return fields
+}
@@ -0,0 +1,107 @@
+package detect
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestVersionFields(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"package.json": `{
+  "name": "app",
+  "engines": {"node": ">=18", "version": "0.0.1"},
+  "keywords": ["version"],
+  "version": "2.2.0"
+}
+`,
+		"crates/core/Cargo.toml": `[package]
+name = "core"
+version = "2.2.0"
+
+[dependencies]
+serde = { version = "1.0" }
+`,
+		"python/pyproject.toml": `[build-system]
+requires = ["hatchling"]
+
+[project]
+name = "app"
+version = "2.1.0"
+`,
+		"go.mod": "module example.com/app\n",
+		"internal/version/version.go": `package version
+
+// Version is the release version.
+const Version = "v2.2.0"
+
+const (
+	APIVersion   = "2.2.0"
+	MinGoVersion = "1.21.0"
+	Name         = "app"
+	buildVersion string = "dev"
+)
+`,
+		"sdk/go.mod":          "module example.com/app/sdk\n",
+		"sdk/version.go":      "package sdk\n\nconst Version = \"v0.4.0\"\n",
+		"testdata/version.go": "package testdata\n\nconst Version = \"v9.9.9\"\n",
+	})
+
+	fields, err := VersionFields(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []VersionField{
+		{File: filepath.Join("crates", "core", "Cargo.toml"), Key: "[package] version", Value: "2.2.0", Line: 3},
+		{File: filepath.Join("internal", "version", "version.go"), Key: "Version", Value: "v2.2.0", Line: 4},
+		{File: "package.json", Key: "version", Value: "2.2.0", Line: 5},
+		{File: filepath.Join("python", "pyproject.toml"), Key: "[project] version", Value: "2.1.0", Line: 6},
+	}
+	if len(fields) != len(want) {
+		t.Fatalf("VersionFields() = %+v", fields)
+	}
+	for i, w := range want {
+		f := fields[i]
+		if f.File != w.File || f.Key != w.Key || f.Value != w.Value || f.Line != w.Line {
+			t.Errorf("field %d = %+v, want %+v", i, f, w)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, f.File))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(data[f.Start:f.End]); got != f.Value {
+			t.Errorf("%s: value at offsets = %q, want %q", f.File, got, f.Value)
+		}
+	}
+}
+
+func TestVersionField_Format(t *testing.T) {
+	tests := []struct {
+		field VersionField
+		want  string
+	}{
+		{VersionField{File: "package.json", Value: "1.0.0"}, "2.3.0"},
+		{VersionField{File: "version.go", Value: "v1.0.0"}, "v2.3.0"},
+		{VersionField{File: "version.go", Value: "1.0.0"}, "2.3.0"},
+	}
+	for _, tt := range tests {
+		if got := tt.field.Format("v2.3.0"); got != tt.want {
+			t.Errorf("Format(%s %s) = %q, want %q", tt.field.File, tt.field.Value, got, tt.want)
+		}
+	}
+}
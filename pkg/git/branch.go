@@ -0,0 +1,123 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// RefExists reports whether a fully qualified ref (e.g., "refs/heads/main") exists.
+func (g *Git) RefExists(ref string) (bool, error) {
+	_, err := g.run("show-ref", "--verify", "--quiet", ref)
+	if err != nil {
+		// Exit code 1 means the ref doesn't exist, which is not an error
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// BranchExists reports whether a local branch exists.
+func (g *Git) BranchExists(branch string) (bool, error) {
+	return g.RefExists("refs/heads/" + branch)
+}
+
+// RemoteBranchExists reports whether the remote has a branch, as of the last fetch.
+func (g *Git) RemoteBranchExists(branch string) (bool, error) {
+	return g.RefExists("refs/remotes/" + g.Remote + "/" + branch)
+}
+
+// ResolveCommit returns the full hash of the commit ref points to.
+func (g *Git) ResolveCommit(ref string) (string, error) {
+	output, err := g.run("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("unknown commit %s: %w", ref, err)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// CreateBranch creates a branch at start without checking it out.
+// A branch created from a remote-tracking branch tracks it.
+func (g *Git) CreateBranch(branch, start string) error {
+	_, err := g.run("branch", branch, start)
+	if err != nil {
+		return fmt.Errorf("failed to create branch %s from %s: %w", branch, start, err)
+	}
+	return nil
+}
+
+// DeleteBranch force-deletes a local branch.
+func (g *Git) DeleteBranch(branch string) error {
+	_, err := g.run("branch", "-D", branch)
+	if err != nil {
+		return fmt.Errorf("failed to delete branch %s: %w", branch, err)
+	}
+	return nil
+}
+
+// Checkout switches the working tree to a branch or commit.
+func (g *Git) Checkout(ref string) error {
+	_, err := g.run("checkout", ref)
+	if err != nil {
+		return fmt.Errorf("failed to check out %s: %w", ref, err)
+	}
+	return nil
+}
+
+// CherryPick applies a commit to the current branch, recording its origin in
+// the message. A conflicting cherry-pick is aborted, leaving HEAD unchanged.
+func (g *Git) CherryPick(commit string) error {
+	_, err := g.run("cherry-pick", "-x", commit)
+	if err != nil {
+		_, _ = g.run("cherry-pick", "--abort")
+		return fmt.Errorf("failed to cherry-pick %s: %w", commit, err)
+	}
+	return nil
+}
+
+// FastForward advances the current branch to ref, failing if it has diverged.
+func (g *Git) FastForward(ref string) error {
+	_, err := g.run("merge", "--ff-only", ref)
+	if err != nil {
+		return fmt.Errorf("failed to fast-forward to %s: %w", ref, err)
+	}
+	return nil
+}
+
+// ResetHard moves HEAD to ref, discarding staged and unstaged changes.
+func (g *Git) ResetHard(ref string) error {
+	_, err := g.run("reset", "--hard", ref)
+	if err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", ref, err)
+	}
+	return nil
+}
+
+// DefaultBranch returns the remote's default branch, falling back to a local
+// "main" or "master" branch when the remote HEAD is unknown.
+func (g *Git) DefaultBranch() (string, error) {
+	output, err := g.run("symbolic-ref", "--short", "refs/remotes/"+g.Remote+"/HEAD")
+	if err == nil {
+		return strings.TrimPrefix(strings.TrimSpace(output), g.Remote+"/"), nil
+	}
+
+	for _, branch := range []string{"main", "master"} {
+		if ok, err := g.BranchExists(branch); err == nil && ok {
+			return branch, nil
+		}
+	}
+	return "", fmt.Errorf("cannot determine the default branch of %s", g.Remote)
+}
+
+// ShowFile returns the content of a file at ref.
+func (g *Git) ShowFile(ref, path string) (string, error) {
+	output, err := g.run("show", ref+":"+path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s at %s: %w", path, ref, err)
+	}
+	return output, nil
+}
@@ -2,13 +2,21 @@ package git
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os/exec"
-	"regexp"
 	"strings"
 	"time"
+
+	"github.com/agentplexus/agent-team-release/pkg/proc"
 )
 
+// ErrCIFailed is returned by WaitForCI when a check fails.
+var ErrCIFailed = errors.New("CI failed")
+
+// ErrCITimeout is returned by WaitForCI when checks don't finish in time.
+var ErrCITimeout = errors.New("CI timeout")
+
 // CIStatus represents the combined status of CI checks.
 type CIStatus struct {
 	State       string        // "success", "pending", "failure", "error"
@@ -138,6 +146,7 @@ func (g *Git) GetCIStatus(ref string) (*CIStatus, error) {
 }
 
 // WaitForCI waits for CI to complete with a timeout.
+// It returns early with the context's error if g's context is cancelled.
 func (g *Git) WaitForCI(timeout time.Duration) error {
 	if !commandExists("gh") {
 		return fmt.Errorf("gh CLI not found in PATH")
@@ -151,6 +160,7 @@ func (g *Git) WaitForCI(timeout time.Duration) error {
 	deadline := time.Now().Add(timeout)
 	pollInterval := 10 * time.Second
 
+	ctx := g.context()
 	for time.Now().Before(deadline) {
 		status, err := g.GetCIStatus(ref)
 		if err != nil {
@@ -161,14 +171,20 @@ func (g *Git) WaitForCI(timeout time.Duration) error {
 		case "success":
 			return nil
 		case "failure", "error":
-			return fmt.Errorf("CI failed with state: %s", status.State)
+			return fmt.Errorf("%w with state: %s", ErrCIFailed, status.State)
 		}
 
 		// Still pending, wait and retry
-		time.Sleep(pollInterval)
+		timer := time.NewTimer(pollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("waiting for CI: %w", ctx.Err())
+		case <-timer.C:
+		}
 	}
 
-	return fmt.Errorf("CI timeout after %v", timeout)
+	return fmt.Errorf("%w after %v", ErrCITimeout, timeout)
 }
 
 // IsCIPassing checks if CI is currently passing (without waiting).
@@ -180,34 +196,28 @@ func (g *Git) IsCIPassing(ref string) (bool, error) {
 	return status.State == "success", nil
 }
 
-// parseRemoteURL extracts owner and repo from the remote URL.
+// parseRemoteURL extracts owner and repo from the remote URL, which must
+// point at GitHub for the gh CLI.
 func (g *Git) parseRemoteURL() (owner string, repo string, err error) {
-	url, err := g.RemoteURL()
+	r, err := g.Repository()
 	if err != nil {
 		return "", "", err
 	}
-
-	// Handle SSH format: git@github.com:owner/repo.git
-	sshRegex := regexp.MustCompile(`git@github\.com:([^/]+)/(.+?)(?:\.git)?$`)
-	if m := sshRegex.FindStringSubmatch(url); m != nil {
-		return m[1], m[2], nil
+	if r.Forge != GitHub {
+		return "", "", fmt.Errorf("not a GitHub repository: %s", r.URL())
 	}
-
-	// Handle HTTPS format: https://github.com/owner/repo.git
-	httpsRegex := regexp.MustCompile(`https://github\.com/([^/]+)/(.+?)(?:\.git)?$`)
-	if m := httpsRegex.FindStringSubmatch(url); m != nil {
-		return m[1], m[2], nil
-	}
-
-	return "", "", fmt.Errorf("could not parse GitHub URL: %s", url)
+	return r.Owner, r.Name, nil
 }
 
 // runGH executes a gh command and returns the output.
 func (g *Git) runGH(args ...string) (string, error) {
-	cmd := exec.Command("gh", args...)
+	cmd := proc.Command(g.context(), "gh", args...)
 	cmd.Dir = g.Dir
 
 	output, err := cmd.Output()
+	if ctxErr := g.context().Err(); ctxErr != nil {
+		return "", ctxErr
+	}
 	if err != nil {
 		return "", err
 	}
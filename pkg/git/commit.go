@@ -0,0 +1,233 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Person is a commit author, committer or co-author.
+type Person struct {
+	Name  string
+	Email string
+}
+
+// String returns the person as "Name <email>".
+func (p Person) String() string {
+	switch {
+	case p.Email == "":
+		return p.Name
+	case p.Name == "":
+		return "<" + p.Email + ">"
+	}
+	return fmt.Sprintf("%s <%s>", p.Name, p.Email)
+}
+
+// IsBot reports whether the person is a bot account, such as
+// "dependabot[bot]".
+func (p Person) IsBot() bool {
+	return strings.HasSuffix(p.Name, "[bot]") || strings.Contains(p.Email, "[bot]@")
+}
+
+// Trailer is a "Key: value" line in the last paragraph of a commit
+// message, e.g. "Co-authored-by: Ada <ada@example.com>".
+type Trailer struct {
+	Key   string
+	Value string
+}
+
+// CoAuthoredBy is the trailer crediting a commit's co-authors.
+const CoAuthoredBy = "Co-authored-by"
+
+// Commit is a commit read from the log.
+type Commit struct {
+	Hash       string
+	Parents    []string // Parent hashes; merges have more than one
+	Author     Person
+	AuthorDate time.Time
+	Committer  Person
+	CommitDate time.Time
+	Subject    string    // First line of the message
+	Body       string    // Message after the subject, trailers included
+	Trailers   []Trailer // In message order, with folded lines joined
+	Files      []string  // Changed files, if requested (see LogOptions.Files)
+
+	Conventional // Subject and footers parsed as a conventional commit
+}
+
+// ShortHash returns the abbreviated commit hash.
+func (c Commit) ShortHash() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+	return c.Hash
+}
+
+// Message returns the full commit message.
+func (c Commit) Message() string {
+	if c.Body == "" {
+		return c.Subject
+	}
+	return c.Subject + "\n\n" + c.Body
+}
+
+// IsMerge reports whether the commit has more than one parent.
+func (c Commit) IsMerge() bool {
+	return len(c.Parents) > 1
+}
+
+// Trailer returns the values of the trailers with the given key, matched
+// case-insensitively.
+func (c Commit) Trailer(key string) []string {
+	var values []string
+	for _, t := range c.Trailers {
+		if strings.EqualFold(t.Key, key) {
+			values = append(values, t.Value)
+		}
+	}
+	return values
+}
+
+// CoAuthors returns the people credited in Co-authored-by trailers.
+func (c Commit) CoAuthors() []Person {
+	var people []Person
+	for _, v := range c.Trailer(CoAuthoredBy) {
+		if p, ok := ParsePerson(v); ok {
+			people = append(people, p)
+		}
+	}
+	return people
+}
+
+// personRegex matches "Name <email>".
+var personRegex = regexp.MustCompile(`^\s*(.*?)\s*<([^<>]*)>\s*$`)
+
+// ParsePerson parses "Name <email>". A value without an email is a name.
+func ParsePerson(s string) (Person, bool) {
+	if m := personRegex.FindStringSubmatch(s); m != nil {
+		return Person{Name: m[1], Email: m[2]}, true
+	}
+	s = strings.TrimSpace(s)
+	return Person{Name: s}, s != ""
+}
+
+// LogOptions selects the commits of a log query.
+type LogOptions struct {
+	NoMerges    bool     // Leave out merge commits
+	FirstParent bool     // Follow only the first parent, so merged branches show as their merge commit
+	Files       bool     // List changed files; merges list those changed relative to their first parent
+	Paths       []string // Only commits touching these pathspecs (all commits if none)
+}
+
+// Fields of a log record, separated by 0x1f. Records start with 0x1e, and
+// with Files the changed files follow the last field.
+const commitFormat = "%x1e%H%x1f%P%x1f%an%x1f%ae%x1f%aI%x1f%cn%x1f%ce%x1f%cI%x1f%B%x1f%(trailers:only,unfold)%x1f"
+
+const commitFields = 11 // Including the files after the last separator
+
+// Commits returns the commits reachable from to but not from from, newest
+// first. Either may be a tag, branch or hash; an empty from returns all of
+// to's history, and an empty to means HEAD. For example, Commits("v1.0.0",
+// "v1.1.0", opts) lists the commits released in v1.1.0.
+func (g *Git) Commits(from, to string, opts LogOptions) ([]Commit, error) {
+	if to == "" {
+		to = "HEAD"
+	}
+	revRange := to
+	if from != "" {
+		revRange = from + ".." + to
+	}
+	return g.log(opts, revRange)
+}
+
+// ShowCommit returns a single commit, with its changed files.
+func (g *Git) ShowCommit(ref string) (*Commit, error) {
+	commits, err := g.log(LogOptions{Files: true}, "-1", ref)
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("commit %s not found", ref)
+	}
+	return &commits[0], nil
+}
+
+// log runs git log with the commit format and parses its records.
+func (g *Git) log(opts LogOptions, revs ...string) ([]Commit, error) {
+	args := []string{"-c", "core.quotePath=false", "log", "--format=" + commitFormat}
+	if opts.NoMerges {
+		args = append(args, "--no-merges")
+	}
+	if opts.FirstParent {
+		args = append(args, "--first-parent")
+	}
+	if opts.Files {
+		args = append(args, "--name-only", "--diff-merges=first-parent")
+	}
+	args = append(args, revs...)
+	args = append(append(args, "--"), opts.Paths...)
+
+	output, err := g.run(args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseCommits(output)
+}
+
+// parseCommits parses log output written with commitFormat.
+func parseCommits(output string) ([]Commit, error) {
+	var commits []Commit
+	for _, record := range strings.Split(output, "\x1e") {
+		if strings.TrimSpace(record) == "" {
+			continue
+		}
+		fields := strings.Split(record, "\x1f")
+		if len(fields) != commitFields {
+			return nil, fmt.Errorf("unexpected git log record: %q", record)
+		}
+
+		c := Commit{
+			Hash:      fields[0],
+			Parents:   strings.Fields(fields[1]),
+			Author:    Person{Name: fields[2], Email: fields[3]},
+			Committer: Person{Name: fields[5], Email: fields[6]},
+		}
+		var err error
+		if c.AuthorDate, err = time.Parse(time.RFC3339, fields[4]); err != nil {
+			return nil, fmt.Errorf("commit %s: %w", c.Hash, err)
+		}
+		if c.CommitDate, err = time.Parse(time.RFC3339, fields[7]); err != nil {
+			return nil, fmt.Errorf("commit %s: %w", c.Hash, err)
+		}
+
+		message := strings.TrimSpace(fields[8])
+		subject, body, _ := strings.Cut(message, "\n")
+		c.Subject = strings.TrimSpace(subject)
+		c.Body = strings.TrimSpace(body)
+		c.Conventional = ParseConventional(message)
+		c.Trailers = parseTrailers(fields[9])
+
+		for _, f := range strings.Split(fields[10], "\n") {
+			if f = strings.TrimSpace(f); f != "" {
+				c.Files = append(c.Files, f)
+			}
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+// parseTrailers parses the "Key: value" lines printed by
+// %(trailers:only,unfold).
+func parseTrailers(s string) []Trailer {
+	var trailers []Trailer
+	for _, line := range strings.Split(s, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(key) == "" {
+			continue
+		}
+		trailers = append(trailers, Trailer{Key: strings.TrimSpace(key), Value: strings.TrimSpace(value)})
+	}
+	return trailers
+}
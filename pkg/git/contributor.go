@@ -0,0 +1,175 @@
+package git
+
+import (
+	"sort"
+	"strings"
+)
+
+// Contributor is a person credited with commits as author or co-author.
+type Contributor struct {
+	Person
+	Commits   int  // Commits authored or co-authored
+	FirstTime bool // Not credited in any earlier tag
+}
+
+// Contributors returns the people who authored or co-authored (see
+// Commit.CoAuthors) the commits in from..to (see Commits) touching paths,
+// or all of them if none are given, leaving out merge commits. Identities
+// are mapped through the repository's mailmap and merged by email, so each
+// person appears once, with the most commits first. People not credited in
+// the history of any tag that doesn't contain the range are flagged as
+// first-time contributors; nobody is if there is no such tag.
+func (g *Git) Contributors(from, to string, paths ...string) ([]Contributor, error) {
+	commits, err := g.Commits(from, to, LogOptions{NoMerges: true, Paths: paths})
+	if err != nil || len(commits) == 0 {
+		return nil, err
+	}
+
+	var people []Person
+	credits := make([][]Person, len(commits))
+	for i, c := range commits {
+		credits[i] = append([]Person{c.Author}, c.CoAuthors()...)
+		people = append(people, credits[i]...)
+	}
+	earlier, err := g.earlierCredits(commits)
+	if err != nil {
+		return nil, err
+	}
+	mapped, err := g.mailmap(append(people, earlier...))
+	if err != nil {
+		return nil, err
+	}
+
+	var contributors []*Contributor
+	byKey := make(map[string]*Contributor)
+	for _, credited := range credits {
+		counted := make(map[string]bool)
+		for _, p := range credited {
+			p = mapped[p]
+			key := personKey(p)
+			if key == "" || counted[key] {
+				continue
+			}
+			counted[key] = true
+			c, ok := byKey[key]
+			if !ok {
+				c = &Contributor{Person: p}
+				byKey[key] = c
+				contributors = append(contributors, c)
+			}
+			c.Commits++
+		}
+	}
+
+	if len(earlier) > 0 {
+		known := make(map[string]bool)
+		for _, p := range earlier {
+			known[personKey(mapped[p])] = true
+		}
+		for _, c := range contributors {
+			c.FirstTime = !known[personKey(c.Person)]
+		}
+	}
+
+	// Most commits first, then by name
+	sort.SliceStable(contributors, func(i, j int) bool {
+		if contributors[i].Commits != contributors[j].Commits {
+			return contributors[i].Commits > contributors[j].Commits
+		}
+		return strings.ToLower(contributors[i].Name) < strings.ToLower(contributors[j].Name)
+	})
+	result := make([]Contributor, len(contributors))
+	for i, c := range contributors {
+		result[i] = *c
+	}
+	return result, nil
+}
+
+// earlierCredits returns the authors and co-authors of the history of the
+// tags that contain none of the given commits, or nil if there are none.
+func (g *Git) earlierCredits(commits []Commit) ([]Person, error) {
+	inRange := make(map[string]bool, len(commits))
+	for _, c := range commits {
+		inRange[c.Hash] = true
+	}
+	// A tag containing any commit contains one whose parents are outside
+	args := []string{"tag", "--list"}
+	for _, c := range commits {
+		oldest := true
+		for _, p := range c.Parents {
+			if inRange[p] {
+				oldest = false
+			}
+		}
+		if oldest {
+			args = append(args, "--no-contains", c.Hash)
+		}
+	}
+	output, err := g.run(args...)
+	if err != nil {
+		return nil, err
+	}
+	tags := strings.Fields(output)
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	history, err := g.log(LogOptions{NoMerges: true}, tags...)
+	if err != nil {
+		return nil, err
+	}
+	var people []Person
+	for _, c := range history {
+		people = append(people, c.Author)
+		people = append(people, c.CoAuthors()...)
+	}
+	return people, nil
+}
+
+// mailmap maps people to their canonical identities in the repository's
+// mailmap (.mailmap, or the mailmap.file and mailmap.blob settings). People
+// without an email map to themselves.
+func (g *Git) mailmap(people []Person) (map[Person]Person, error) {
+	mapped := make(map[Person]Person, len(people))
+	var query []Person
+	for _, p := range people {
+		if _, ok := mapped[p]; ok {
+			continue
+		}
+		mapped[p] = p
+		if p.Email != "" {
+			query = append(query, p)
+		}
+	}
+
+	// Batched to keep command lines short
+	const batch = 200
+	for start := 0; start < len(query); start += batch {
+		end := min(start+batch, len(query))
+		args := []string{"check-mailmap"}
+		for _, p := range query[start:end] {
+			args = append(args, p.String())
+		}
+		output, err := g.run(args...)
+		if err != nil {
+			return nil, err
+		}
+		lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+		for i, p := range query[start:end] {
+			if i < len(lines) {
+				if canonical, ok := ParsePerson(lines[i]); ok {
+					mapped[p] = canonical
+				}
+			}
+		}
+	}
+	return mapped, nil
+}
+
+// personKey identifies a person by email, or by name without one.
+func personKey(p Person) string {
+	if p.Email != "" {
+		return strings.ToLower(p.Email)
+	}
+	return strings.ToLower(strings.TrimSpace(p.Name))
+}
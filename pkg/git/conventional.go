@@ -0,0 +1,44 @@
+package git
+
+import (
+	"regexp"
+	"strings"
+)
+
+// conventionalRegex matches a conventional commit subject, e.g.
+// "feat(api)!: add streaming".
+var conventionalRegex = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?:\s*(.*)$`)
+
+// Conventional is a commit message parsed as a conventional commit
+// (https://www.conventionalcommits.org).
+type Conventional struct {
+	Type        string // e.g., "feat", "fix"; empty if the subject isn't conventional
+	Scope       string // e.g., "api"
+	Breaking    bool   // "!" after the type, or a BREAKING CHANGE footer
+	Description string // Subject without the type and scope
+}
+
+// ParseConventional parses a commit message's subject and footers. A
+// message that doesn't follow the convention has no type, but a BREAKING
+// CHANGE footer still marks it breaking.
+func ParseConventional(message string) Conventional {
+	subject, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+
+	var c Conventional
+	if m := conventionalRegex.FindStringSubmatch(subject); m != nil {
+		c.Type = strings.ToLower(m[1])
+		c.Scope = m[2]
+		c.Breaking = m[3] == "!"
+		c.Description = m[4]
+	} else {
+		c.Description = subject
+	}
+
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "BREAKING CHANGE:") || strings.HasPrefix(line, "BREAKING-CHANGE:") {
+			c.Breaking = true
+			break
+		}
+	}
+	return c
+}
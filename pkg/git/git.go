@@ -3,16 +3,23 @@ package git
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os/exec"
 	"regexp"
+	"strconv"
 	"strings"
+
+	"github.com/agentplexus/agent-team-release/pkg/proc"
 )
 
 // Git provides git operations for a repository.
 type Git struct {
 	Dir    string // Repository directory
 	Remote string // Remote name (default: origin)
+
+	ctx context.Context // Cancels running commands (nil = never)
 }
 
 // New creates a new Git instance for the given directory.
@@ -23,6 +30,22 @@ func New(dir string) *Git {
 	}
 }
 
+// WithContext returns a copy of g whose commands are killed when ctx is
+// cancelled.
+func (g *Git) WithContext(ctx context.Context) *Git {
+	cp := *g
+	cp.ctx = ctx
+	return &cp
+}
+
+// context returns the context commands run under.
+func (g *Git) context() context.Context {
+	if g.ctx == nil {
+		return context.Background()
+	}
+	return g.ctx
+}
+
 // Status represents the current git status.
 type Status struct {
 	Branch       string   // Current branch name
@@ -45,6 +68,16 @@ func (g *Git) LatestTag() (string, error) {
 	return strings.TrimSpace(output), nil
 }
 
+// LatestTagMatching returns the most recent tag reachable from HEAD that
+// matches a glob pattern such as "sdk/go/v*".
+func (g *Git) LatestTagMatching(pattern string) (string, error) {
+	output, err := g.run("describe", "--tags", "--abbrev=0", "--match", pattern)
+	if err != nil {
+		return "", fmt.Errorf("no tags matching %s found: %w", pattern, err)
+	}
+	return strings.TrimSpace(output), nil
+}
+
 // AllTags returns all tags in the repository, sorted by version.
 func (g *Git) AllTags() ([]string, error) {
 	output, err := g.run("tag", "--sort=-version:refname")
@@ -81,6 +114,15 @@ func (g *Git) DeleteTag(tag string) error {
 	return err
 }
 
+// DeleteRemoteTag deletes a tag from the remote.
+func (g *Git) DeleteRemoteTag(tag string) error {
+	_, err := g.run("push", g.Remote, "--delete", "refs/tags/"+tag)
+	if err != nil {
+		return fmt.Errorf("failed to delete remote tag %s: %w", tag, err)
+	}
+	return nil
+}
+
 // Push pushes refs to the remote.
 func (g *Git) Push(refs ...string) error {
 	args := []string{"push", g.Remote}
@@ -151,6 +193,24 @@ func (g *Git) Commit(message string, sign bool) error {
 	return nil
 }
 
+// Revert creates a new commit that reverts the given commit.
+func (g *Git) Revert(commit string) error {
+	_, err := g.run("revert", "--no-edit", commit)
+	if err != nil {
+		return fmt.Errorf("failed to revert %s: %w", commit, err)
+	}
+	return nil
+}
+
+// ResetSoft moves HEAD to ref, keeping the changes of the undone commits staged.
+func (g *Git) ResetSoft(ref string) error {
+	_, err := g.run("reset", "--soft", ref)
+	if err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", ref, err)
+	}
+	return nil
+}
+
 // Status returns the current git status.
 func (g *Git) Status() (*Status, error) {
 	status := &Status{}
@@ -271,6 +331,29 @@ func (g *Git) ShortCommit() (string, error) {
 	return strings.TrimSpace(output), nil
 }
 
+// GitDir returns the absolute path of the repository's .git directory.
+func (g *Git) GitDir() (string, error) {
+	output, err := g.run("rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// Config returns the value of a git config key, or "" if it isn't set.
+func (g *Git) Config(key string) (string, error) {
+	output, err := g.run("config", "--get", key)
+	if err != nil {
+		// Exit code 1 means the key isn't set, which is not an error
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
 // RemoteURL returns the URL of the remote.
 func (g *Git) RemoteURL() (string, error) {
 	output, err := g.run("remote", "get-url", g.Remote)
@@ -285,7 +368,8 @@ func (g *Git) IsAncestor(ancestor, descendant string) (bool, error) {
 	_, err := g.run("merge-base", "--is-ancestor", ancestor, descendant)
 	if err != nil {
 		// Exit code 1 means not an ancestor, which is not an error
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
 			return false, nil
 		}
 		return false, err
@@ -305,7 +389,8 @@ func (g *Git) FetchTags() error {
 	return err
 }
 
-// Log returns commit messages between two refs.
+// Log returns commit messages between two refs, formatted with format.
+// Use Commits for parsed commits.
 func (g *Git) Log(from, to string, format string) (string, error) {
 	if format == "" {
 		format = "%h %s"
@@ -318,9 +403,23 @@ func (g *Git) Log(from, to string, format string) (string, error) {
 	return output, nil
 }
 
+// CountCommits returns the number of commits in a revision range such as
+// "v1.0.0..HEAD" that touch the given pathspecs (all commits if none).
+func (g *Git) CountCommits(revRange string, paths ...string) (int, error) {
+	args := []string{"rev-list", "--count", revRange}
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+	output, err := g.run(args...)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(output))
+}
+
 // run executes a git command and returns the output.
 func (g *Git) run(args ...string) (string, error) {
-	cmd := exec.Command("git", args...)
+	cmd := proc.Command(g.context(), "git", args...)
 	cmd.Dir = g.Dir
 
 	var stdout, stderr bytes.Buffer
@@ -328,6 +427,9 @@ func (g *Git) run(args ...string) (string, error) {
 	cmd.Stderr = &stderr
 
 	err := cmd.Run()
+	if ctxErr := g.context().Err(); ctxErr != nil {
+		return "", fmt.Errorf("git %s: %w", args[0], ctxErr)
+	}
 	if err != nil {
 		errMsg := stderr.String()
 		if errMsg == "" {
@@ -338,3 +440,37 @@ func (g *Git) run(args ...string) (string, error) {
 
 	return stdout.String(), nil
 }
+
+// transientMessages are fragments of git errors caused by network or server
+// trouble rather than by the repository state.
+var transientMessages = []string{
+	"could not resolve host",
+	"connection timed out",
+	"connection reset",
+	"connection refused",
+	"operation timed out",
+	"early eof",
+	"the remote end hung up unexpectedly",
+	"rpc failed",
+	"temporary failure",
+	"internal server error",
+	"bad gateway",
+	"service unavailable",
+	"gateway timeout",
+	"returned error: 50", // HTTP 5xx
+}
+
+// IsTransient reports whether err looks like a temporary network or server
+// failure that may succeed if retried. Cancellation is never transient.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, m := range transientMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package git
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ReferenceKind is what a reference points at.
+type ReferenceKind string
+
+const (
+	IssueRef        ReferenceKind = "issue"
+	PullRequestRef  ReferenceKind = "pull_request"
+	MergeRequestRef ReferenceKind = "merge_request" // GitLab's "!12"
+)
+
+// Reference is an issue or pull request a commit refers to, e.g. "#12",
+// "owner/repo#12", "!12" or an issue URL.
+type Reference struct {
+	Kind   ReferenceKind // IssueRef unless the syntax or context says otherwise
+	Repo   string        // "owner/name" if in another repository, else ""
+	Number int
+	URL    string // Set if the reference was written as a URL
+	Closes bool   // Preceded by a closing keyword, e.g. "Fixes #12"
+}
+
+// String returns the reference as written in commit messages, e.g. "#12",
+// "owner/repo#12" or "!12".
+func (r Reference) String() string {
+	sigil := "#"
+	if r.Kind == MergeRequestRef {
+		sigil = "!"
+	}
+	return r.Repo + sigil + strconv.Itoa(r.Number)
+}
+
+// refPattern matches a reference: "#12", "!12", "owner/repo#12", or an
+// issue, pull request or merge request URL.
+const refPattern = `(?:([\w.-]+/[\w.-]+)?([#!])(\d+)|(https?://[^\s/]+/([^\s]+?)/(?:-/)?(issues|pull|pulls|merge_requests)/(\d+)))\b`
+
+var (
+	// mentionRegex matches a reference at the start of the text or after
+	// whitespace or punctuation, so "feat!:" and "abc#1" aren't references.
+	mentionRegex = regexp.MustCompile(`(?:^|[\s(\[,;])` + refPattern)
+	// closingRegex matches a reference after a closing keyword.
+	closingRegex = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?):?\s+` + refPattern)
+	// pullSubjectRegex matches the pull request number GitHub adds to
+	// squash-merged and merge commit subjects.
+	pullSubjectRegex = regexp.MustCompile(`(?:\(#(\d+)\)\s*$|^Merge pull request #(\d+)\b)`)
+)
+
+// pullRequestTrailers name pull requests in their values.
+var pullRequestTrailers = []string{"PR", "Pull-Request", "Merge-Request"}
+
+// References returns the issues and pull requests the commit message refers
+// to, in order of first mention, each once. "Fixes", "Closes" and
+// "Resolves", with or without a colon as in trailers, mark a reference as
+// closing it. The "(#12)" suffix GitHub adds to squash-merged subjects, and
+// PR trailers, mark pull requests.
+func (c Commit) References() []Reference {
+	var refs []Reference
+	index := make(map[string]int)
+	add := func(ref Reference) {
+		// GitLab numbers issues (#12) and merge requests (!12) separately
+		key := strings.ToLower(ref.String())
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(refs)
+			refs = append(refs, ref)
+			return
+		}
+		if refs[i].Kind == IssueRef {
+			refs[i].Kind = ref.Kind
+		}
+		if refs[i].URL == "" {
+			refs[i].URL = ref.URL
+		}
+		refs[i].Closes = refs[i].Closes || ref.Closes
+	}
+
+	message := c.Message()
+	for _, m := range mentionRegex.FindAllStringSubmatch(message, -1) {
+		add(referenceOf(m[1:]))
+	}
+	for _, m := range closingRegex.FindAllStringSubmatch(message, -1) {
+		ref := referenceOf(m[1:])
+		ref.Closes = true
+		add(ref)
+	}
+	if m := pullSubjectRegex.FindStringSubmatch(c.Subject); m != nil {
+		n, _ := strconv.Atoi(m[1] + m[2])
+		add(Reference{Kind: PullRequestRef, Number: n})
+	}
+	for _, key := range pullRequestTrailers {
+		for _, v := range c.Trailer(key) {
+			for _, m := range mentionRegex.FindAllStringSubmatch(v, -1) {
+				ref := referenceOf(m[1:])
+				if ref.Kind == IssueRef {
+					ref.Kind = PullRequestRef
+				}
+				add(ref)
+			}
+		}
+	}
+	return refs
+}
+
+// referenceOf builds a reference from the submatches of refPattern.
+func referenceOf(m []string) Reference {
+	if m[3] != "" {
+		n, _ := strconv.Atoi(m[6])
+		ref := Reference{Kind: IssueRef, Repo: m[4], Number: n, URL: m[3]}
+		switch m[5] {
+		case "pull", "pulls":
+			ref.Kind = PullRequestRef
+		case "merge_requests":
+			ref.Kind = MergeRequestRef
+		}
+		return ref
+	}
+	n, _ := strconv.Atoi(m[2])
+	ref := Reference{Kind: IssueRef, Repo: m[0], Number: n}
+	if m[1] == "!" {
+		ref.Kind = MergeRequestRef
+	}
+	return ref
+}
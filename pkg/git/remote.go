@@ -0,0 +1,126 @@
+package git
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Forge is the code hosting service a repository lives on.
+type Forge string
+
+const (
+	GitHub Forge = "github"
+	GitLab Forge = "gitlab"
+	Gitea  Forge = "gitea"
+)
+
+// Repository is a repository on a forge, parsed from a remote URL.
+type Repository struct {
+	Forge Forge  // Empty if the host isn't recognized
+	Host  string // e.g. "github.com"
+	Owner string // User or organization; a GitLab group path may have subgroups
+	Name  string
+}
+
+// scpRemoteRegex matches the scp-like SSH syntax, e.g. "git@github.com:owner/repo.git".
+var scpRemoteRegex = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+
+// ParseRepositoryURL parses a remote URL in the HTTPS, SSH or scp-like
+// syntax, e.g. "https://gitlab.com/group/sub/repo.git" or
+// "git@github.com:owner/repo.git". The forge is recognized from the host.
+func ParseRepositoryURL(remote string) (*Repository, error) {
+	remote = strings.TrimSpace(remote)
+
+	var host, path string
+	if u, err := url.Parse(remote); err == nil && u.Scheme != "" && u.Host != "" {
+		host, path = u.Hostname(), u.Path
+	} else if m := scpRemoteRegex.FindStringSubmatch(remote); m != nil && !strings.Contains(remote, "://") {
+		host, path = m[1], m[2]
+	} else {
+		return nil, fmt.Errorf("could not parse remote URL: %s", remote)
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	slash := strings.LastIndex(path, "/")
+	if host == "" || slash <= 0 || slash == len(path)-1 {
+		return nil, fmt.Errorf("could not parse remote URL: %s", remote)
+	}
+
+	return &Repository{
+		Forge: forgeOf(host),
+		Host:  strings.ToLower(host),
+		Owner: path[:slash],
+		Name:  path[slash+1:],
+	}, nil
+}
+
+// forgeOf recognizes the forge from a host name.
+func forgeOf(host string) Forge {
+	host = strings.ToLower(host)
+	switch {
+	case strings.Contains(host, "github"):
+		return GitHub
+	case strings.Contains(host, "gitlab"):
+		return GitLab
+	case strings.Contains(host, "gitea") || host == "codeberg.org":
+		return Gitea
+	}
+	return ""
+}
+
+// Repository parses the remote URL.
+func (g *Git) Repository() (*Repository, error) {
+	remote, err := g.RemoteURL()
+	if err != nil {
+		return nil, err
+	}
+	return ParseRepositoryURL(remote)
+}
+
+// Path returns "owner/name".
+func (r *Repository) Path() string {
+	return r.Owner + "/" + r.Name
+}
+
+// URL returns the repository's web URL.
+func (r *Repository) URL() string {
+	return "https://" + r.Host + "/" + r.Path()
+}
+
+// Local returns ref with its repository cleared if it names r.
+func (r *Repository) Local(ref Reference) Reference {
+	if strings.EqualFold(ref.Repo, r.Path()) {
+		ref.Repo = ""
+	}
+	return ref
+}
+
+// ReferenceURL returns the web URL of an issue or pull request, which may
+// be in another repository on the same host. A reference written as a URL
+// keeps it.
+func (r *Repository) ReferenceURL(ref Reference) string {
+	if ref.URL != "" {
+		return ref.URL
+	}
+	base := r.URL()
+	if ref.Repo != "" {
+		base = "https://" + r.Host + "/" + ref.Repo
+	}
+	n := strconv.Itoa(ref.Number)
+
+	switch {
+	case r.Forge == GitLab && ref.Kind == IssueRef:
+		return base + "/-/issues/" + n
+	case r.Forge == GitLab:
+		return base + "/-/merge_requests/" + n
+	case r.Forge == GitHub && ref.Kind != IssueRef:
+		return base + "/pull/" + n
+	case r.Forge == Gitea && ref.Kind != IssueRef:
+		return base + "/pulls/" + n
+	}
+	// GitHub and Gitea redirect issue URLs of pull requests
+	return base + "/issues/" + n
+}
@@ -0,0 +1,146 @@
+// Package gomod reads go.mod files and edits their requirements without
+// the go command, preserving the files' formatting and comments.
+package gomod
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// FileName is the name of a Go module's definition file.
+const FileName = "go.mod"
+
+// File is the parsed content of a go.mod file.
+type File struct {
+	Module  string    // Module path
+	Require []Require // Required modules, in file order
+}
+
+// Require is a module requirement.
+type Require struct {
+	Path     string
+	Version  string
+	Indirect bool // Marked "// indirect"
+}
+
+// ReadFile parses the go.mod file at path.
+func ReadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Parse parses the module path and requirements of a go.mod file.
+func Parse(data []byte) (*File, error) {
+	f := &File{}
+	block := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		fields, comment := splitLine(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if block != "" {
+			if fields[0] == ")" {
+				block = ""
+			} else if block == "require" && len(fields) >= 2 {
+				f.addRequire(fields[0], fields[1], comment)
+			}
+			continue
+		}
+
+		switch {
+		case fields[len(fields)-1] == "(":
+			block = fields[0]
+		case fields[0] == "module" && len(fields) >= 2:
+			f.Module = unquote(fields[1])
+		case fields[0] == "require" && len(fields) >= 3:
+			f.addRequire(fields[1], fields[2], comment)
+		}
+	}
+
+	if f.Module == "" {
+		return nil, fmt.Errorf("no module directive")
+	}
+	return f, nil
+}
+
+func (f *File) addRequire(path, version, comment string) {
+	f.Require = append(f.Require, Require{
+		Path:     unquote(path),
+		Version:  version,
+		Indirect: strings.Contains(comment, "indirect"),
+	})
+}
+
+// Requires returns the required version of a module, or "" if it isn't required.
+func (f *File) Requires(path string) string {
+	for _, r := range f.Require {
+		if r.Path == path {
+			return r.Version
+		}
+	}
+	return ""
+}
+
+// SetRequire changes the required version of a module, leaving the rest
+// of the file as it was. It reports whether the module was required.
+func SetRequire(data []byte, path, version string) ([]byte, bool) {
+	lines := strings.Split(string(data), "\n")
+	found := false
+	block := ""
+	for i, line := range lines {
+		fields, _ := splitLine(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var modPath, old string
+		switch {
+		case block != "":
+			if fields[0] == ")" {
+				block = ""
+				continue
+			}
+			if block != "require" || len(fields) < 2 {
+				continue
+			}
+			modPath, old = fields[0], fields[1]
+		case fields[len(fields)-1] == "(":
+			block = fields[0]
+			continue
+		case fields[0] == "require" && len(fields) >= 3:
+			modPath, old = fields[1], fields[2]
+		default:
+			continue
+		}
+
+		if unquote(modPath) != path {
+			continue
+		}
+		found = true
+		start := strings.Index(line, modPath) + len(modPath)
+		j := strings.Index(line[start:], old)
+		lines[i] = line[:start+j] + version + line[start+j+len(old):]
+	}
+	return []byte(strings.Join(lines, "\n")), found
+}
+
+// splitLine returns the fields of a go.mod line and its trailing comment.
+func splitLine(line string) (fields []string, comment string) {
+	if i := strings.Index(line, "//"); i >= 0 {
+		line, comment = line[:i], line[i+2:]
+	}
+	return strings.Fields(line), strings.TrimSpace(comment)
+}
+
+func unquote(s string) string {
+	return strings.Trim(s, "\"`")
+}
@@ -0,0 +1,88 @@
+package gomod
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testGoMod = `// Deprecated: use example.com/app/v2.
+module "example.com/app"
+
+go 1.24
+
+require example.com/core v1.2.0
+
+require (
+	example.com/sdk v0.3.1
+	github.com/spf13/cobra v1.10.2 // indirect
+)
+
+replace (
+	example.com/core => ../core
+)
+
+exclude example.com/sdk v0.1.0
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse([]byte(testGoMod))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Module != "example.com/app" {
+		t.Errorf("Module = %q", f.Module)
+	}
+	want := []Require{
+		{Path: "example.com/core", Version: "v1.2.0"},
+		{Path: "example.com/sdk", Version: "v0.3.1"},
+		{Path: "github.com/spf13/cobra", Version: "v1.10.2", Indirect: true},
+	}
+	if !reflect.DeepEqual(f.Require, want) {
+		t.Errorf("Require = %+v, want %+v", f.Require, want)
+	}
+	if f.Requires("example.com/sdk") != "v0.3.1" || f.Requires("example.com/other") != "" {
+		t.Error("Requires() returned the wrong versions")
+	}
+
+	if _, err := Parse([]byte("go 1.24\n")); err == nil {
+		t.Error("Parse() without a module directive should fail")
+	}
+}
+
+func TestSetRequire(t *testing.T) {
+	data, ok := SetRequire([]byte(testGoMod), "example.com/core", "v1.3.0")
+	if !ok {
+		t.Fatal("SetRequire(core) found no requirement")
+	}
+	data, ok = SetRequire(data, "github.com/spf13/cobra", "v1.11.0")
+	if !ok {
+		t.Fatal("SetRequire(cobra) found no requirement")
+	}
+
+	want := `// Deprecated: use example.com/app/v2.
+module "example.com/app"
+
+go 1.24
+
+require example.com/core v1.3.0
+
+require (
+	example.com/sdk v0.3.1
+	github.com/spf13/cobra v1.11.0 // indirect
+)
+
+replace (
+	example.com/core => ../core
+)
+
+exclude example.com/sdk v0.1.0
+`
+	if string(data) != want {
+		t.Errorf("SetRequire() =\n%s\nwant\n%s", data, want)
+	}
+
+	// Exclusions and replacements aren't requirements
+	if _, ok := SetRequire([]byte(testGoMod), "example.com/other", "v1.0.0"); ok {
+		t.Error("SetRequire(other) reported a requirement")
+	}
+}
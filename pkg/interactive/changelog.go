@@ -0,0 +1,67 @@
+package interactive
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agentplexus/agent-team-release/pkg/changelog"
+)
+
+// omitOption is the option that leaves a commit out of the changelog.
+const omitOption = "omit"
+
+// ClassifyChanges walks through the uncertain classifications of a drafted
+// release entry, asking where each commit's change belongs. An empty answer
+// keeps the guess. Settled items become certain.
+func ClassifyChanges(p Prompter, d *changelog.Draft) error {
+	uncertain := d.Uncertain()
+	if len(uncertain) == 0 {
+		return nil
+	}
+	p.Info(fmt.Sprintf("%d of %d commit(s) need a decision for %s", len(uncertain), len(d.Items), d.Version))
+
+	options := make([]Option, 0, len(changelog.DraftCategories)+1)
+	for _, c := range changelog.DraftCategories {
+		options = append(options, Option{ID: string(c), Label: c.Title()})
+	}
+	options = append(options, Option{ID: omitOption, Label: "Omit", Description: "Leave this commit out of the changelog"})
+
+	for _, item := range uncertain {
+		guess := string(item.Category)
+		if guess == "" {
+			guess = omitOption
+		}
+
+		var context strings.Builder
+		fmt.Fprintf(&context, "Guess: %s (%s)", guess, item.Reason)
+		if len(item.Commit.Files) > 0 {
+			fmt.Fprintf(&context, "\nFiles: %s", strings.Join(item.Commit.Files, ", "))
+		}
+
+		answer, err := p.Ask(Question{
+			ID:      "classify_" + item.Commit.ShortHash(),
+			Text:    fmt.Sprintf("Where does %s %q belong?", item.Commit.ShortHash(), item.Commit.Subject),
+			Type:    QuestionTypeSingleChoice,
+			Options: options,
+			Default: guess,
+			Context: context.String(),
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(answer.Selected) > 0 {
+			switch selected := answer.Selected[0]; {
+			case selected == omitOption:
+				item.Category = ""
+			case changelog.Category(selected).Valid():
+				item.Category = changelog.Category(selected)
+			default:
+				return fmt.Errorf("unknown category %q for %s", selected, item.Commit.ShortHash())
+			}
+		}
+		item.Certain = true
+		item.Reason = "chosen interactively"
+	}
+	return nil
+}
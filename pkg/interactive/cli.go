@@ -211,8 +211,12 @@ func (p *CLIPrompter) ShowProposal(proposal actions.Proposal) error {
 		fmt.Printf("\nFile: %s\n", proposal.FilePath)
 	}
 
-	// Show diff if we have old and new content
-	if proposal.OldContent != "" || proposal.NewContent != "" {
+	// Show a unified diff when the file content is known, else a summary
+	if diff := proposal.Diff(); diff != "" {
+		fmt.Println("\nChanges:")
+		fmt.Println("─────────")
+		fmt.Print(diff)
+	} else if proposal.OldContent != "" || proposal.NewContent != "" {
 		fmt.Println("\nChanges:")
 		fmt.Println("─────────")
 
@@ -149,3 +149,70 @@ func ReviewProposal(p Prompter, proposal actions.Proposal) (ProposalAction, erro
 		return ProposalActionApply, nil
 	}
 }
+
+// ApprovalAction represents the decision at an approval gate.
+type ApprovalAction int
+
+const (
+	// ApprovalProceed runs the gated step.
+	ApprovalProceed ApprovalAction = iota
+	// ApprovalSkip skips the gated step and continues.
+	ApprovalSkip
+	// ApprovalAbort stops the operation.
+	ApprovalAbort
+)
+
+// String returns the string representation of the approval action.
+func (aa ApprovalAction) String() string {
+	switch aa {
+	case ApprovalProceed:
+		return "proceed"
+	case ApprovalSkip:
+		return "skip"
+	case ApprovalAbort:
+		return "abort"
+	default:
+		return "unknown"
+	}
+}
+
+// RequestApproval presents what an irreversible operation will do and asks
+// whether to proceed. Unlike ReviewProposal, an empty answer aborts, so
+// nothing irreversible happens without an explicit decision.
+func RequestApproval(p Prompter, id string, text string, proposals []actions.Proposal) (ApprovalAction, error) {
+	for _, proposal := range proposals {
+		if err := p.ShowProposal(proposal); err != nil {
+			return ApprovalAbort, err
+		}
+	}
+
+	q := Question{
+		ID:   id,
+		Text: text,
+		Type: QuestionTypeSingleChoice,
+		Options: []Option{
+			{ID: "proceed", Label: "Proceed", Description: "Run this step"},
+			{ID: "skip", Label: "Skip", Description: "Skip this step and continue"},
+			{ID: "abort", Label: "Abort", Description: "Stop the release"},
+		},
+		Default: "abort",
+	}
+
+	answer, err := p.Ask(q)
+	if err != nil {
+		return ApprovalAbort, err
+	}
+
+	if len(answer.Selected) == 0 {
+		return ApprovalAbort, nil
+	}
+
+	switch answer.Selected[0] {
+	case "proceed":
+		return ApprovalProceed, nil
+	case "skip":
+		return ApprovalSkip, nil
+	default:
+		return ApprovalAbort, nil
+	}
+}
@@ -2,10 +2,13 @@ package interactive
 
 import (
 	"bytes"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/agentplexus/agent-team-release/pkg/actions"
+	"github.com/agentplexus/agent-team-release/pkg/changelog"
+	"github.com/agentplexus/agent-team-release/pkg/git"
 )
 
 func TestQuestionTypeString(t *testing.T) {
@@ -162,6 +165,64 @@ func TestReviewProposal_Default(t *testing.T) {
 	}
 }
 
+func TestApprovalActionString(t *testing.T) {
+	tests := []struct {
+		aa   ApprovalAction
+		want string
+	}{
+		{ApprovalProceed, "proceed"},
+		{ApprovalSkip, "skip"},
+		{ApprovalAbort, "abort"},
+		{ApprovalAction(99), "unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.aa.String(); got != tt.want {
+			t.Errorf("ApprovalAction(%d).String() = %s, want %s", tt.aa, got, tt.want)
+		}
+	}
+}
+
+func TestRequestApproval(t *testing.T) {
+	tests := []struct {
+		selected []string
+		want     ApprovalAction
+	}{
+		{[]string{"proceed"}, ApprovalProceed},
+		{[]string{"skip"}, ApprovalSkip},
+		{[]string{"abort"}, ApprovalAbort},
+		{nil, ApprovalAbort},
+	}
+
+	for _, tt := range tests {
+		shown := 0
+		mock := &MockPrompter{
+			AskFunc: func(q Question) (Answer, error) {
+				if q.ID != "approve_push" {
+					t.Errorf("question ID = %s, want approve_push", q.ID)
+				}
+				return Answer{QuestionID: q.ID, Selected: tt.selected}, nil
+			},
+			ShowProposalFunc: func(p actions.Proposal) error {
+				shown++
+				return nil
+			},
+		}
+
+		proposals := []actions.Proposal{{Description: "Push main"}, {Description: "Push tag"}}
+		got, err := RequestApproval(mock, "approve_push", "Push to origin?", proposals)
+		if err != nil {
+			t.Fatalf("RequestApproval() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("RequestApproval(%v) = %v, want %v", tt.selected, got, tt.want)
+		}
+		if shown != 2 {
+			t.Errorf("ShowProposal called %d times, want 2", shown)
+		}
+	}
+}
+
 func TestQuestion(t *testing.T) {
 	q := Question{
 		ID:      "test",
@@ -320,3 +381,54 @@ func TestDefaultJSONPrompter(t *testing.T) {
 		t.Error("DefaultJSONPrompter() returned nil")
 	}
 }
+
+func TestClassifyChanges(t *testing.T) {
+	commit := func(hash, subject string) git.Commit {
+		return git.Commit{Hash: hash, Subject: subject, Files: []string{"main.go"}, Conventional: git.ParseConventional(subject)}
+	}
+	d := changelog.NewDraft("v1.1.0", "2026-05-01", []git.Commit{
+		commit("ccc3333aaaa", "Tweak buffers"),
+		commit("bbb2222aaaa", "refactor: split parser"),
+		commit("aaa1111aaaa", "feat: streaming"),
+	}, nil)
+
+	answers := map[string][]string{
+		"classify_bbb2222": {"changed"},
+		"classify_ccc3333": {"omit"},
+	}
+	var asked []string
+	mock := &MockPrompter{
+		AskFunc: func(q Question) (Answer, error) {
+			asked = append(asked, q.ID)
+			if q.Type != QuestionTypeSingleChoice || len(q.Options) != len(changelog.DraftCategories)+1 {
+				t.Errorf("question %s = %+v, want a single choice of categories", q.ID, q)
+			}
+			return Answer{QuestionID: q.ID, Selected: answers[q.ID]}, nil
+		},
+	}
+
+	if err := ClassifyChanges(mock, d); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"classify_bbb2222", "classify_ccc3333"}; !reflect.DeepEqual(asked, want) {
+		t.Errorf("asked %v, want %v", asked, want)
+	}
+	var got []changelog.Category
+	for _, item := range d.Items {
+		got = append(got, item.Category)
+	}
+	if want := []changelog.Category{changelog.Added, changelog.Changed, ""}; !reflect.DeepEqual(got, want) {
+		t.Errorf("categories = %v, want %v", got, want)
+	}
+	if len(d.Uncertain()) != 0 {
+		t.Error("answered items should be certain")
+	}
+
+	mock.AskFunc = func(q Question) (Answer, error) {
+		return Answer{QuestionID: q.ID, Selected: []string{"misc"}}, nil
+	}
+	d.Items[0].Certain = false
+	if err := ClassifyChanges(mock, d); err == nil {
+		t.Error("ClassifyChanges() should reject unknown categories")
+	}
+}
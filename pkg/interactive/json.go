@@ -73,6 +73,7 @@ type jsonProposalMessage struct {
 	FilePath    string            `json:"file_path,omitempty"`
 	OldContent  string            `json:"old_content,omitempty"`
 	NewContent  string            `json:"new_content,omitempty"`
+	Diff        string            `json:"diff,omitempty"`
 	Metadata    map[string]string `json:"metadata,omitempty"`
 	WaitingFor  string            `json:"waiting_for"`
 	Actions     []string          `json:"actions"`
@@ -144,6 +145,7 @@ func (p *JSONPrompter) ShowProposal(proposal actions.Proposal) error {
 		FilePath:    proposal.FilePath,
 		OldContent:  proposal.OldContent,
 		NewContent:  proposal.NewContent,
+		Diff:        proposal.Diff(),
 		Metadata:    proposal.Metadata,
 		WaitingFor:  "user_approval",
 		Actions:     []string{"apply", "skip", "abort"},
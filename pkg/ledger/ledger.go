@@ -0,0 +1,309 @@
+// Package ledger keeps an append-only record of release and validation
+// runs: who ran what, on which commit, with which flags, and how each step
+// and validation area fared.
+package ledger
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/agentplexus/agent-team-release/pkg/checks"
+	"github.com/agentplexus/agent-team-release/pkg/git"
+	"github.com/agentplexus/agent-team-release/pkg/workflow"
+)
+
+// FileName is the ledger file, kept under .git/atrelease/ next to the
+// workflow checkpoints so it is never committed.
+const FileName = "ledger.jsonl"
+
+// Step statuses.
+const (
+	StatusPassed    = "passed"
+	StatusFailed    = "failed"
+	StatusSkipped   = "skipped"
+	StatusResumed   = "resumed"
+	StatusCancelled = "cancelled"
+)
+
+// Record is one release or validation run.
+type Record struct {
+	Command   string    `json:"command" toon:"command"` // e.g., "release", "validate", "hotfix"
+	Version   string    `json:"version,omitempty" toon:"version,omitempty"`
+	Module    string    `json:"module,omitempty" toon:"module,omitempty"` // Module directory of a module release
+	Commit    string    `json:"commit,omitempty" toon:"commit,omitempty"` // HEAD when the run started
+	Branch    string    `json:"branch,omitempty" toon:"branch,omitempty"`
+	User      string    `json:"user,omitempty" toon:"user,omitempty"`
+	Flags     []string  `json:"flags,omitempty" toon:"flags,omitempty"` // Flags set on the command line
+	StartedAt time.Time `json:"started_at" toon:"started_at"`
+	Duration  string    `json:"duration" toon:"duration"`
+	Success   bool      `json:"success" toon:"success"`
+	Cancelled bool      `json:"cancelled,omitempty" toon:"cancelled,omitempty"`
+	Error     string    `json:"error,omitempty" toon:"error,omitempty"`
+	Steps     []Step    `json:"steps,omitempty" toon:"steps,omitempty"`
+	Rollback  []Step    `json:"rollback,omitempty" toon:"rollback,omitempty"`
+	Areas     []Area    `json:"areas,omitempty" toon:"areas,omitempty"`
+}
+
+// Step is the outcome of a workflow step.
+type Step struct {
+	Name     string `json:"name" toon:"name"`
+	Status   string `json:"status" toon:"status"`
+	Error    string `json:"error,omitempty" toon:"error,omitempty"`
+	Duration string `json:"duration" toon:"duration"`
+	Attempts int    `json:"attempts,omitempty" toon:"attempts,omitempty"` // Set when the step was retried
+	Approval string `json:"approval,omitempty" toon:"approval,omitempty"`
+}
+
+// Area is the Go/No-Go status of a validation area.
+type Area struct {
+	Area    string   `json:"area" toon:"area"`
+	Status  string   `json:"status" toon:"status"`
+	Failed  []string `json:"failed,omitempty" toon:"failed,omitempty"`   // Checks that failed
+	Skipped []string `json:"skipped,omitempty" toon:"skipped,omitempty"` // Checks that were skipped
+}
+
+// Path returns the ledger file of the repository at dir.
+func Path(dir string) (string, error) {
+	gitDir, err := git.New(dir).GitDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate .git directory: %w", err)
+	}
+	return filepath.Join(gitDir, "atrelease", FileName), nil
+}
+
+// NewRecord starts a record of a run in the repository at dir, noting the
+// current commit, branch and user.
+func NewRecord(dir, command, version string, flags []string) Record {
+	rec := Record{
+		Command:   command,
+		Version:   version,
+		Flags:     flags,
+		StartedAt: time.Now().UTC(),
+	}
+
+	g := git.New(dir)
+	if commit, err := g.CurrentCommit(); err == nil {
+		rec.Commit = commit
+	}
+	if branch, err := g.CurrentBranch(); err == nil && branch != "HEAD" {
+		rec.Branch = branch
+	}
+	rec.User = currentUser(g)
+	return rec
+}
+
+// currentUser returns the git identity, falling back to the OS user.
+func currentUser(g *git.Git) string {
+	name, _ := g.Config("user.name")
+	email, _ := g.Config("user.email")
+	switch {
+	case name != "" && email != "":
+		return fmt.Sprintf("%s <%s>", name, email)
+	case name != "":
+		return name
+	case email != "":
+		return email
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
+
+// AddWorkflow fills the record from a workflow result and the data of its
+// context, which holds the statuses of the validation areas it checked.
+func (r *Record) AddWorkflow(result *workflow.WorkflowResult, data map[string]string) {
+	r.Duration = roundDuration(result.Duration)
+	r.Success = result.Success
+	r.Cancelled = result.Cancelled
+	if result.Error != nil {
+		r.Error = result.Error.Error()
+	}
+
+	for _, step := range result.Steps {
+		r.Steps = append(r.Steps, fromStepResult(step))
+	}
+	for _, undo := range result.Rollback {
+		r.Rollback = append(r.Rollback, fromStepResult(undo))
+	}
+
+	statuses := workflow.AreaStatuses(data)
+	areas := make([]string, 0, len(statuses))
+	for area := range statuses {
+		areas = append(areas, string(area))
+	}
+	sort.Strings(areas)
+	for _, area := range areas {
+		r.Areas = append(r.Areas, Area{
+			Area:   area,
+			Status: string(statuses[checks.ValidationArea(area)]),
+		})
+	}
+}
+
+func fromStepResult(step workflow.StepResult) Step {
+	s := Step{
+		Name:     step.Name,
+		Duration: roundDuration(step.Duration),
+		Approval: step.Approval,
+	}
+	switch {
+	case step.Cancelled:
+		s.Status = StatusCancelled
+	case step.Resumed:
+		s.Status = StatusResumed
+	case step.Skipped:
+		s.Status = StatusSkipped
+	case step.Success:
+		s.Status = StatusPassed
+	default:
+		s.Status = StatusFailed
+	}
+	if step.Error != nil {
+		s.Error = step.Error.Error()
+	}
+	if len(step.Attempts) > 1 {
+		s.Attempts = len(step.Attempts)
+	}
+	return s
+}
+
+// AddValidation fills the record from a validation report.
+func (r *Record) AddValidation(report *checks.ValidationReport) {
+	r.Duration = roundDuration(time.Since(r.StartedAt))
+	r.Success = report.IsGo()
+
+	for _, area := range report.Areas {
+		a := Area{Area: string(area.Area), Status: string(area.Status)}
+		for _, res := range area.Results {
+			switch {
+			case res.Skipped:
+				a.Skipped = append(a.Skipped, res.Name)
+			case !res.Passed && !res.Warning:
+				a.Failed = append(a.Failed, res.Name)
+			}
+		}
+		r.Areas = append(r.Areas, a)
+	}
+}
+
+func roundDuration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
+
+// Append adds a record to the ledger, creating the file if needed.
+// Records are never rewritten.
+func Append(path string, rec Record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating ledger directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("opening ledger: %w", err)
+	}
+	// One write per record keeps concurrent runs from interleaving lines
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("writing ledger: %w", err)
+	}
+	return f.Close()
+}
+
+// Read returns the ledger's records, oldest first. A missing ledger has no records.
+func Read(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening ledger: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading ledger: %w", err)
+	}
+	return records, nil
+}
+
+// Query selects ledger records. Zero fields match everything.
+type Query struct {
+	Command string    // Only runs of this command
+	Version string    // Only runs for this version or module tag (e.g., sdk/go/v1.2.0)
+	User    string    // Only runs whose user contains this
+	Since   time.Time // Only runs started at or after this time
+	Failed  bool      // Only runs that failed or were cancelled
+	Limit   int       // At most this many of the newest matches (0 = all)
+}
+
+// Filter returns the records matching the query, newest first.
+func (q Query) Filter(records []Record) []Record {
+	var matched []Record
+	for i := len(records) - 1; i >= 0; i-- {
+		rec := records[i]
+		if q.matches(rec) {
+			matched = append(matched, rec)
+			if q.Limit > 0 && len(matched) == q.Limit {
+				break
+			}
+		}
+	}
+	return matched
+}
+
+func (q Query) matches(rec Record) bool {
+	switch {
+	case q.Command != "" && rec.Command != q.Command:
+		return false
+	case q.Version != "" && !sameVersion(rec.Version, q.Version) && !sameVersion(rec.tag(), q.Version):
+		return false
+	case q.User != "" && !containsFold(rec.User, q.User):
+		return false
+	case !q.Since.IsZero() && rec.StartedAt.Before(q.Since):
+		return false
+	case q.Failed && rec.Success:
+		return false
+	}
+	return true
+}
+
+// tag returns the tag the record's run released.
+func (r Record) tag() string {
+	return workflow.ModuleTag(r.Module, r.Version)
+}
+
+// sameVersion reports whether two version strings name the same version,
+// ignoring a leading "v".
+func sameVersion(a, b string) bool {
+	return strings.TrimPrefix(a, "v") == strings.TrimPrefix(b, "v")
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
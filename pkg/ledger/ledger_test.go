@@ -0,0 +1,184 @@
+package ledger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agentplexus/agent-team-release/pkg/checks"
+	"github.com/agentplexus/agent-team-release/pkg/workflow"
+)
+
+func TestAppendRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "atrelease", FileName)
+
+	records, err := Read(path)
+	if err != nil || records != nil {
+		t.Fatalf("Read(missing) = %v, %v", records, err)
+	}
+
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	want := []Record{
+		{Command: "validate", Version: "v1.0.0", StartedAt: start, Duration: "2s", Success: true,
+			Areas: []Area{{Area: "QA", Status: "GO"}}},
+		{Command: "release", Version: "v1.0.0", StartedAt: start.Add(time.Hour), Duration: "1m0s",
+			Flags: []string{"--skip-checks"}, Steps: []Step{{Name: "Create tag", Status: StatusFailed, Error: "boom", Duration: "1s"}}},
+	}
+	for _, rec := range want {
+		if err := Append(path, rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() = %+v, want %+v", got, want)
+	}
+
+	// Records are appended, never rewritten
+	data, _ := os.ReadFile(path)
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("ledger has %d lines, want 2", lines)
+	}
+}
+
+func TestRead_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte(`{"command":"release"}`+"\n{oops\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(path); err == nil || !strings.Contains(err.Error(), ":2:") {
+		t.Errorf("Read(corrupt) error = %v, want line 2", err)
+	}
+}
+
+func TestQueryFilter(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	records := []Record{
+		{Command: "validate", Version: "v1.0.0", User: "Alice <alice@example.com>", StartedAt: start, Success: true},
+		{Command: "release", Version: "v1.0.0", User: "Bob", StartedAt: start.Add(24 * time.Hour)},
+		{Command: "release", Version: "v1.0.0", User: "alice", StartedAt: start.Add(48 * time.Hour), Success: true},
+		{Command: "release", Version: "v1.1.0", User: "Bob", StartedAt: start.Add(72 * time.Hour), Success: true},
+		{Command: "release", Version: "v1.0.0", Module: "sdk/go", User: "Bob", StartedAt: start.Add(96 * time.Hour), Success: true},
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []int // indexes into records, newest first
+	}{
+		{"all", Query{}, []int{4, 3, 2, 1, 0}},
+		{"command", Query{Command: "release"}, []int{4, 3, 2, 1}},
+		{"version without v", Query{Version: "1.0.0"}, []int{4, 2, 1, 0}},
+		{"module tag", Query{Version: "sdk/go/v1.0.0"}, []int{4}},
+		{"user", Query{User: "ALICE"}, []int{2, 0}},
+		{"since", Query{Since: start.Add(48 * time.Hour)}, []int{4, 3, 2}},
+		{"failed", Query{Failed: true}, []int{1}},
+		{"limit", Query{Command: "release", Limit: 2}, []int{4, 3}},
+	}
+	for _, tt := range tests {
+		got := tt.query.Filter(records)
+		var want []Record
+		for _, i := range tt.want {
+			want = append(want, records[i])
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Filter() = %+v, want %+v", tt.name, got, want)
+		}
+	}
+}
+
+func TestAddWorkflow(t *testing.T) {
+	result := &workflow.WorkflowResult{
+		Success:  false,
+		Error:    errors.New("step Push to remote failed"),
+		Duration: 1500 * time.Millisecond,
+		Steps: []workflow.StepResult{
+			{Name: "Validate version", Success: true, Resumed: true},
+			{Name: "Security validation", Success: true, Duration: time.Second},
+			{Name: "Push to remote", Error: errors.New("rejected"), Attempts: make([]workflow.StepAttempt, 3)},
+			{Name: "Create tag", Skipped: true},
+		},
+		Rollback: []workflow.StepResult{{Name: "Undo Create release commit", Success: true}},
+	}
+	data := map[string]string{
+		"area_status:Security": "GO",
+		"area_status:PM":       "SKIP",
+		"release_commit":       "abc",
+	}
+
+	var rec Record
+	rec.AddWorkflow(result, data)
+
+	if rec.Success || rec.Error != "step Push to remote failed" || rec.Duration != "1.5s" {
+		t.Errorf("record = %+v", rec)
+	}
+	statuses := make([]string, len(rec.Steps))
+	for i, s := range rec.Steps {
+		statuses[i] = s.Status
+	}
+	if want := []string{StatusResumed, StatusPassed, StatusFailed, StatusSkipped}; !reflect.DeepEqual(statuses, want) {
+		t.Errorf("step statuses = %v, want %v", statuses, want)
+	}
+	if rec.Steps[2].Attempts != 3 || rec.Steps[2].Error != "rejected" {
+		t.Errorf("push step = %+v", rec.Steps[2])
+	}
+	if len(rec.Rollback) != 1 {
+		t.Errorf("rollback = %+v", rec.Rollback)
+	}
+	if want := []Area{{Area: "PM", Status: "SKIP"}, {Area: "Security", Status: "GO"}}; !reflect.DeepEqual(rec.Areas, want) {
+		t.Errorf("areas = %+v, want %+v", rec.Areas, want)
+	}
+}
+
+func TestAddValidation(t *testing.T) {
+	report := &checks.ValidationReport{
+		Areas: []checks.AreaResult{{
+			Area:   checks.AreaQA,
+			Status: checks.StatusNoGo,
+			Results: []checks.Result{
+				{Name: "QA: tests", Passed: false},
+				{Name: "QA: lint", Passed: false, Warning: true},
+				{Name: "QA: coverage", Skipped: true},
+				{Name: "QA: build", Passed: true},
+			},
+		}},
+	}
+
+	rec := Record{StartedAt: time.Now()}
+	rec.AddValidation(report)
+
+	want := []Area{{Area: "QA", Status: "NO-GO", Failed: []string{"QA: tests"}, Skipped: []string{"QA: coverage"}}}
+	if rec.Success || !reflect.DeepEqual(rec.Areas, want) {
+		t.Errorf("record = %+v, want areas %+v", rec, want)
+	}
+}
+
+func TestDetails(t *testing.T) {
+	rec := Record{
+		Command:   "release",
+		Version:   "v1.2.0",
+		Commit:    "0123456789abcdef",
+		User:      "Alice",
+		Flags:     []string{"--skip-checks"},
+		StartedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Duration:  "3s",
+		Steps:     []Step{{Name: "Push to remote", Status: StatusFailed, Duration: "1s", Attempts: 3, Error: "rejected"}},
+		Areas:     []Area{{Area: "Security", Status: "SKIP"}},
+	}
+
+	details := rec.Details()
+	for _, want := range []string{"❌ release", "v1.2.0", "0123456", "Alice", "--skip-checks",
+		"✗ Push to remote (1s) [3 attempts]: rejected", "Security       SKIP"} {
+		if !strings.Contains(details, want) {
+			t.Errorf("Details() missing %q:\n%s", want, details)
+		}
+	}
+}
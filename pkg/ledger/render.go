@@ -0,0 +1,106 @@
+package ledger
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Line returns a one-line summary of the record.
+func (r Record) Line() string {
+	status := "✅"
+	switch {
+	case r.Cancelled:
+		status = "⏸️"
+	case !r.Success:
+		status = "❌"
+	}
+
+	version := r.Version
+	if version == "" {
+		version = "-"
+	} else if r.Module != "" {
+		version = r.tag()
+	}
+	line := fmt.Sprintf("%s  %s %-9s %-14s %8s  %s  %s",
+		r.StartedAt.Local().Format("2006-01-02 15:04"), status, r.Command, version,
+		r.Duration, shortCommit(r.Commit), r.User)
+	if len(r.Flags) > 0 {
+		line += "  " + strings.Join(r.Flags, " ")
+	}
+	return line
+}
+
+// Details returns the record's summary line followed by its steps,
+// rollback and validation areas.
+func (r Record) Details() string {
+	var sb strings.Builder
+	sb.WriteString(r.Line())
+	sb.WriteString("\n")
+	if r.Branch != "" {
+		fmt.Fprintf(&sb, "    Branch: %s\n", r.Branch)
+	}
+	if r.Error != "" {
+		fmt.Fprintf(&sb, "    Error: %s\n", r.Error)
+	}
+
+	writeSteps := func(title string, steps []Step) {
+		if len(steps) == 0 {
+			return
+		}
+		fmt.Fprintf(&sb, "    %s:\n", title)
+		for _, s := range steps {
+			fmt.Fprintf(&sb, "      %s %s (%s)", stepIcon(s.Status), s.Name, s.Duration)
+			if s.Attempts > 1 {
+				fmt.Fprintf(&sb, " [%d attempts]", s.Attempts)
+			}
+			if s.Approval != "" {
+				fmt.Fprintf(&sb, " [%s]", s.Approval)
+			}
+			if s.Error != "" {
+				fmt.Fprintf(&sb, ": %s", s.Error)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	writeSteps("Steps", r.Steps)
+	writeSteps("Rollback", r.Rollback)
+
+	if len(r.Areas) > 0 {
+		sb.WriteString("    Areas:\n")
+		for _, a := range r.Areas {
+			fmt.Fprintf(&sb, "      %-14s %s\n", a.Area, a.Status)
+			if len(a.Failed) > 0 {
+				fmt.Fprintf(&sb, "        failed: %s\n", strings.Join(a.Failed, ", "))
+			}
+			if len(a.Skipped) > 0 {
+				fmt.Fprintf(&sb, "        skipped: %s\n", strings.Join(a.Skipped, ", "))
+			}
+		}
+	}
+	return sb.String()
+}
+
+func stepIcon(status string) string {
+	switch status {
+	case StatusPassed:
+		return "✓"
+	case StatusFailed:
+		return "✗"
+	case StatusResumed:
+		return "↻"
+	case StatusCancelled:
+		return "⏸"
+	default:
+		return "⊘"
+	}
+}
+
+func shortCommit(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	if sha == "" {
+		return "-------"
+	}
+	return sha
+}
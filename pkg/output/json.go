@@ -152,6 +152,15 @@ func NewJSONWriter(w io.Writer) *JSONWriter {
 	}
 }
 
+// NewJSONLinesWriter creates a JSONWriter that writes one compact JSON
+// document per line, for streaming newline-delimited JSON.
+func NewJSONLinesWriter(w io.Writer) *JSONWriter {
+	return &JSONWriter{
+		writer:  w,
+		encoder: json.NewEncoder(w),
+	}
+}
+
 // DefaultJSONWriter returns a JSONWriter writing to stdout.
 func DefaultJSONWriter() *JSONWriter {
 	return NewJSONWriter(os.Stdout)
@@ -195,6 +204,7 @@ func (jw *JSONWriter) WriteProposal(p actions.Proposal) error {
 		FilePath:    p.FilePath,
 		OldContent:  p.OldContent,
 		NewContent:  p.NewContent,
+		Diff:        p.Diff(),
 		Metadata:    p.Metadata,
 		WaitingFor:  "user_approval",
 		Actions:     []string{"apply", "skip", "abort"},
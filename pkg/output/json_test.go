@@ -313,3 +313,27 @@ func TestWorkflowResultMessage(t *testing.T) {
 		t.Error("JSON should contain 'Release'")
 	}
 }
+
+func TestJSONLinesWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewJSONLinesWriter(&buf)
+
+	if err := writer.WriteInfo("first"); err != nil {
+		t.Fatalf("WriteInfo() error = %v", err)
+	}
+	if err := writer.WriteProgress(1, 2, "build", "running"); err != nil {
+		t.Fatalf("WriteProgress() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %q", len(lines), buf.String())
+	}
+	var msg ProgressMessage
+	if err := json.Unmarshal([]byte(lines[1]), &msg); err != nil {
+		t.Fatalf("line is not a JSON document: %v", err)
+	}
+	if msg.StepName != "build" {
+		t.Errorf("StepName = %s, want build", msg.StepName)
+	}
+}
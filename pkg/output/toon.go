@@ -77,6 +77,7 @@ func (tw *TOONWriter) WriteProposal(p actions.Proposal) error {
 		FilePath:    p.FilePath,
 		OldContent:  p.OldContent,
 		NewContent:  p.NewContent,
+		Diff:        p.Diff(),
 		Metadata:    p.Metadata,
 		WaitingFor:  "user_approval",
 		Actions:     []string{"apply", "skip", "abort"},
@@ -0,0 +1,25 @@
+// Package proc provides cancellable external command execution.
+package proc
+
+import (
+	"context"
+	"os/exec"
+	"time"
+)
+
+// waitDelay bounds how long Wait blocks on output pipes after the process
+// is killed, in case orphaned grandchildren keep them open.
+const waitDelay = 2 * time.Second
+
+// Command returns an exec.Cmd bound to ctx. When ctx is cancelled or its
+// deadline passes, the command and any processes it started are killed.
+// A nil ctx is treated as context.Background().
+func Command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.WaitDelay = waitDelay
+	setProcessGroup(cmd)
+	return cmd
+}
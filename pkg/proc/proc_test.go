@@ -0,0 +1,39 @@
+package proc
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCommand(t *testing.T) {
+	out, err := Command(context.Background(), "sh", "-c", "echo hello").Output()
+	if err != nil {
+		t.Fatalf("Command() error: %v", err)
+	}
+	if string(out) != "hello\n" {
+		t.Errorf("output = %q, want %q", out, "hello\n")
+	}
+}
+
+func TestCommand_Cancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// The grandchild sleep holds stdout open; killing the group must release it
+	start := time.Now()
+	_, err := Command(ctx, "sh", "-c", "sleep 10; echo done").Output()
+	if err == nil {
+		t.Fatal("expected error from cancelled command")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("command took %s to cancel", elapsed)
+	}
+}
+
+func TestCommand_NilContext(t *testing.T) {
+	//nolint:staticcheck // nil context is explicitly supported
+	if err := Command(nil, "true").Run(); err != nil {
+		t.Errorf("Command(nil) error: %v", err)
+	}
+}
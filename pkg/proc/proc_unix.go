@@ -0,0 +1,38 @@
+//go:build !windows
+
+package proc
+
+import (
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+)
+
+// setProcessGroup runs the command in its own process group and kills the
+// whole group on cancellation, so child processes are not left behind.
+//
+// With a controlling terminal the command stays in the foreground process
+// group instead: git and ssh prompt for credentials and passphrases on the
+// terminal, and a background group would be stopped by SIGTTIN/SIGTTOU.
+// Cancellation then kills only the command's own process.
+func setProcessGroup(cmd *exec.Cmd) {
+	if hasTerminal() {
+		return
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
+
+// hasTerminal reports whether the process has a controlling terminal,
+// which is where git and ssh prompt even when stdin is redirected.
+var hasTerminal = sync.OnceValue(func() bool {
+	f, err := os.Open("/dev/tty")
+	if err != nil {
+		return false
+	}
+	_ = f.Close()
+	return true
+})
@@ -0,0 +1,9 @@
+//go:build windows
+
+package proc
+
+import "os/exec"
+
+// setProcessGroup is a no-op on Windows; cancellation kills only the
+// command's own process.
+func setProcessGroup(cmd *exec.Cmd) {}
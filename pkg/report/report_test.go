@@ -218,3 +218,26 @@ func TestRendererNoGo(t *testing.T) {
 		t.Error("Output should contain NO-GO message")
 	}
 }
+
+func TestGetPhases_Orchestrator(t *testing.T) {
+	team := &multiagentspec.Team{
+		Orchestrator: "coordinator",
+		Workflow: &multiagentspec.Workflow{
+			Steps: []multiagentspec.Step{
+				{Name: "qa-validation", Agent: "qa"},
+				{Name: "ship-it", Agent: "coordinator", DependsOn: []string{"qa-validation"}},
+			},
+		},
+	}
+
+	phases := GetPhases(team)
+	if len(phases[0].Steps) != 1 || phases[0].Steps[0].Name != "qa-validation" {
+		t.Errorf("review phase = %v", phases[0].Steps)
+	}
+	if len(phases[1].Steps) != 1 || phases[1].Steps[0].Name != "ship-it" {
+		t.Errorf("execute phase = %v", phases[1].Steps)
+	}
+	if len(GetValidationSteps(team)) != 1 {
+		t.Error("orchestrator steps should be excluded from validation steps")
+	}
+}
@@ -17,21 +17,36 @@ import (
 // LoadTeamSpec loads a team.json file from the given directory.
 // Returns a multiagentspec.Team parsed from the JSON file.
 func LoadTeamSpec(dir string) (*multiagentspec.Team, error) {
-	path := filepath.Join(dir, "team.json")
+	return LoadTeamSpecFile(filepath.Join(dir, "team.json"))
+}
+
+// LoadTeamSpecFile loads a team definition from the given path.
+func LoadTeamSpecFile(path string) (*multiagentspec.Team, error) {
+	name := filepath.Base(path)
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("reading team.json: %w", err)
+		return nil, fmt.Errorf("reading %s: %w", name, err)
 	}
 
 	var spec multiagentspec.Team
 	if err := json.Unmarshal(data, &spec); err != nil {
-		return nil, fmt.Errorf("parsing team.json: %w", err)
+		return nil, fmt.Errorf("parsing %s: %w", name, err)
 	}
 
 	return &spec, nil
 }
 
-// GetValidationSteps returns only the validation steps (excludes execute-release).
+// IsExecutionStep reports whether a step belongs to the execution phase.
+// Steps run by the team's orchestrator execute the release; all others validate it.
+// Teams without an orchestrator fall back to the execute-release step name.
+func IsExecutionStep(team *multiagentspec.Team, step multiagentspec.Step) bool {
+	if team.Orchestrator != "" {
+		return step.Agent == team.Orchestrator
+	}
+	return step.Name == "execute-release"
+}
+
+// GetValidationSteps returns only the validation steps (excludes execution steps).
 func GetValidationSteps(team *multiagentspec.Team) []multiagentspec.Step {
 	if team.Workflow == nil {
 		return nil
@@ -39,7 +54,7 @@ func GetValidationSteps(team *multiagentspec.Team) []multiagentspec.Step {
 	var steps []multiagentspec.Step
 	for _, step := range team.Workflow.Steps {
 		// Exclude execution steps, only include validation steps
-		if step.Name != "execute-release" {
+		if !IsExecutionStep(team, step) {
 			steps = append(steps, step)
 		}
 	}
@@ -53,8 +68,8 @@ type Phase struct {
 }
 
 // GetPhases groups steps into phases based on dependencies.
-// Phase 1 (REVIEW): Steps with no dependencies or only pm-validation dependency
-// Phase 2 (EXECUTE): Steps that depend on validation completion
+// Phase 1 (REVIEW): Validation steps
+// Phase 2 (EXECUTE): Steps run by the orchestrator (see IsExecutionStep)
 func GetPhases(team *multiagentspec.Team) []Phase {
 	phases := []Phase{
 		{Name: "PHASE 1: REVIEW", Steps: []multiagentspec.Step{}},
@@ -66,7 +81,7 @@ func GetPhases(team *multiagentspec.Team) []Phase {
 	}
 
 	for _, step := range team.Workflow.Steps {
-		if step.Name == "execute-release" {
+		if IsExecutionStep(team, step) {
 			phases[1].Steps = append(phases[1].Steps, step)
 		} else {
 			phases[0].Steps = append(phases[0].Steps, step)
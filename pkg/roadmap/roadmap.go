@@ -0,0 +1,275 @@
+// Package roadmap reads ROADMAP.json, the structured roadmap sroadmap
+// renders as ROADMAP.md.
+package roadmap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/agentplexus/agent-team-release/pkg/semver"
+)
+
+// FileName is the name of the structured roadmap.
+const FileName = "ROADMAP.json"
+
+// MarkdownFileName is the name of the roadmap rendered from ROADMAP.json.
+const MarkdownFileName = "ROADMAP.md"
+
+// Status is the state of a roadmap item or phase, e.g. "planned".
+type Status string
+
+// Statuses sroadmap knows without a legend entry.
+const (
+	Completed  Status = "completed"
+	InProgress Status = "in_progress"
+	Planned    Status = "planned"
+	Future     Status = "future"
+)
+
+// Statuses lists the known statuses, from done to furthest off.
+var Statuses = []Status{Completed, InProgress, Planned, Future}
+
+// Done reports whether the status marks the work finished.
+func (s Status) Done() bool {
+	return s == Completed
+}
+
+// Item is a piece of work on the roadmap.
+type Item struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Status      Status `json:"status"`
+	Version     string `json:"version,omitempty"` // Release it targets, e.g. "0.6.0"
+	Phase       string `json:"phase,omitempty"`   // ID of its phase
+	Area        string `json:"area,omitempty"`    // ID of its area
+	Type        string `json:"type,omitempty"`    // Changelog category, e.g. "Added"
+	Priority    string `json:"priority,omitempty"`
+}
+
+// Open reports whether the item still has work to do.
+func (i Item) Open() bool {
+	return !i.Status.Done()
+}
+
+// Targets reports whether the item is planned for version, ignoring a
+// leading "v".
+func (i Item) Targets(version string) bool {
+	return i.Version != "" && strings.TrimPrefix(i.Version, "v") == strings.TrimPrefix(version, "v")
+}
+
+// Area is a part of the project roadmap items are grouped by.
+type Area struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Priority int    `json:"priority,omitempty"`
+}
+
+// Phase is a stage of the roadmap, usually a minor release.
+type Phase struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Status      Status `json:"status,omitempty"`
+	Order       int    `json:"order,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// LegendEntry describes how ROADMAP.md marks a status.
+type LegendEntry struct {
+	Emoji       string `json:"emoji,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Roadmap is a parsed ROADMAP.json. Its sections and version history,
+// which only ROADMAP.md needs, aren't decoded.
+type Roadmap struct {
+	IRVersion  string                 `json:"ir_version,omitempty"`
+	Project    string                 `json:"project,omitempty"`
+	Repository string                 `json:"repository,omitempty"`
+	Legend     map[Status]LegendEntry `json:"legend,omitempty"`
+	Areas      []Area                 `json:"areas,omitempty"`
+	Phases     []Phase                `json:"phases,omitempty"`
+	Items      []Item                 `json:"items"`
+}
+
+// Parse parses a ROADMAP.json document.
+func Parse(data []byte) (*Roadmap, error) {
+	r := &Roadmap{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", FileName, err)
+	}
+	return r, nil
+}
+
+// Load reads and parses a ROADMAP.json file.
+func Load(path string) (*Roadmap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Area returns the area with the given ID, or nil if there is none.
+func (r *Roadmap) Area(id string) *Area {
+	for i := range r.Areas {
+		if r.Areas[i].ID == id {
+			return &r.Areas[i]
+		}
+	}
+	return nil
+}
+
+// Phase returns the phase with the given ID, or nil if there is none.
+func (r *Roadmap) Phase(id string) *Phase {
+	for i := range r.Phases {
+		if r.Phases[i].ID == id {
+			return &r.Phases[i]
+		}
+	}
+	return nil
+}
+
+// Targeting returns the items planned for version, in roadmap order.
+func (r *Roadmap) Targeting(version string) []Item {
+	var items []Item
+	for _, i := range r.Items {
+		if i.Targets(version) {
+			items = append(items, i)
+		}
+	}
+	return items
+}
+
+// Open returns the items planned for version that still have work to do.
+func (r *Roadmap) Open(version string) []Item {
+	var items []Item
+	for _, i := range r.Targeting(version) {
+		if i.Open() {
+			items = append(items, i)
+		}
+	}
+	return items
+}
+
+// Versions returns the versions items target, oldest first.
+func (r *Roadmap) Versions() []string {
+	seen := make(map[string]bool)
+	var versions []string
+	for _, i := range r.Items {
+		v := strings.TrimPrefix(i.Version, "v")
+		if v != "" && !seen[v] {
+			seen[v] = true
+			versions = append(versions, v)
+		}
+	}
+	sort.SliceStable(versions, func(a, b int) bool {
+		if semver.IsValid(versions[a]) && semver.IsValid(versions[b]) {
+			return semver.Compare(versions[a], versions[b]) < 0
+		}
+		return versions[a] < versions[b]
+	})
+	return versions
+}
+
+// Count returns the number of items with each status.
+func (r *Roadmap) Count() map[Status]int {
+	counts := make(map[Status]int)
+	for _, i := range r.Items {
+		counts[i.Status]++
+	}
+	return counts
+}
+
+// Summary describes the roadmap in a few lines: items by status, then the
+// open items of each targeted version.
+func (r *Roadmap) Summary() string {
+	var sb strings.Builder
+	counts := r.Count()
+	sb.WriteString(fmt.Sprintf("%d items", len(r.Items)))
+	for _, s := range r.statuses() {
+		if counts[s] > 0 {
+			sb.WriteString(fmt.Sprintf(", %d %s", counts[s], strings.ReplaceAll(string(s), "_", " ")))
+		}
+	}
+	sb.WriteString("\n")
+	for _, v := range r.Versions() {
+		targeted, open := len(r.Targeting(v)), len(r.Open(v))
+		if open == 0 {
+			sb.WriteString(fmt.Sprintf("  v%s: %d/%d completed\n", v, targeted, targeted))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("  v%s: %d/%d completed (%d open)\n", v, targeted-open, targeted, open))
+	}
+	return sb.String()
+}
+
+// statuses returns the built-in statuses, then any others items use, sorted.
+func (r *Roadmap) statuses() []Status {
+	statuses := append([]Status(nil), Statuses...)
+	var others []Status
+	for s := range r.Count() {
+		if !isBuiltin(s) {
+			others = append(others, s)
+		}
+	}
+	sort.Slice(others, func(a, b int) bool { return others[a] < others[b] })
+	return append(statuses, others...)
+}
+
+// knownStatus reports whether s is a built-in status or in the legend.
+func (r *Roadmap) knownStatus(s Status) bool {
+	_, ok := r.Legend[s]
+	return ok || isBuiltin(s)
+}
+
+// isBuiltin reports whether s is one of Statuses.
+func isBuiltin(s Status) bool {
+	for _, k := range Statuses {
+		if k == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that items have unique IDs, titles and known statuses,
+// target valid versions, and refer to areas and phases that exist.
+func (r *Roadmap) Validate() error {
+	var errs []error
+	seen := make(map[string]bool)
+	for n, i := range r.Items {
+		name := i.ID
+		if name == "" {
+			name = fmt.Sprintf("#%d", n+1)
+		}
+		switch {
+		case i.ID == "":
+			errs = append(errs, fmt.Errorf("item %s: missing id", name))
+		case seen[i.ID]:
+			errs = append(errs, fmt.Errorf("item %s: duplicate id", name))
+		}
+		seen[i.ID] = true
+
+		if strings.TrimSpace(i.Title) == "" {
+			errs = append(errs, fmt.Errorf("item %s: missing title", name))
+		}
+		if !r.knownStatus(i.Status) {
+			errs = append(errs, fmt.Errorf("item %s: unknown status %q", name, i.Status))
+		}
+		if i.Version != "" && !semver.IsValid(i.Version) {
+			errs = append(errs, fmt.Errorf("item %s: invalid version %q", name, i.Version))
+		}
+		if i.Area != "" && r.Area(i.Area) == nil {
+			errs = append(errs, fmt.Errorf("item %s: unknown area %q", name, i.Area))
+		}
+		if i.Phase != "" && r.Phase(i.Phase) == nil {
+			errs = append(errs, fmt.Errorf("item %s: unknown phase %q", name, i.Phase))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,119 @@
+package roadmap
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sample = `{
+  "ir_version": "1.0",
+  "project": "demo",
+  "legend": {
+    "completed": {"emoji": "✅", "description": "Completed"},
+    "blocked": {"emoji": "⛔", "description": "Blocked"}
+  },
+  "areas": [{"id": "core", "name": "Core", "priority": 1}],
+  "phases": [{"id": "v0.2", "name": "v0.2.0", "status": "planned", "order": 2}],
+  "items": [
+    {"id": "detect", "title": "Detection", "status": "completed", "version": "0.1.0", "area": "core"},
+    {"id": "python", "title": "Python checks", "status": "in_progress", "version": "v0.2.0", "phase": "v0.2"},
+    {"id": "rust", "title": "Rust checks", "status": "blocked", "version": "0.2.0"},
+    {"id": "config", "title": "Config file", "status": "completed", "version": "0.2.0"},
+    {"id": "market", "title": "Marketplace", "status": "future"}
+  ],
+  "sections": [{"id": "overview", "title": "Overview"}]
+}`
+
+func TestParse(t *testing.T) {
+	r, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Project != "demo" || len(r.Items) != 5 || len(r.Areas) != 1 || len(r.Phases) != 1 {
+		t.Fatalf("Parse() = %+v", r)
+	}
+	if a := r.Area("core"); a == nil || a.Name != "Core" {
+		t.Errorf("Area(core) = %+v", a)
+	}
+	if r.Area("docs") != nil || r.Phase("v0.9") != nil {
+		t.Error("Area and Phase should be nil for unknown IDs")
+	}
+	if err := r.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+
+	if _, err := Parse([]byte(`{"items": {}}`)); err == nil {
+		t.Error("Parse() of malformed items should fail")
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), FileName)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Load() of a missing file = %v", err)
+	}
+}
+
+func TestRoadmap_Open(t *testing.T) {
+	r, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := func(items []Item) string {
+		var s []string
+		for _, i := range items {
+			s = append(s, i.ID)
+		}
+		return strings.Join(s, ",")
+	}
+	if got := ids(r.Targeting("v0.2.0")); got != "python,rust,config" {
+		t.Errorf("Targeting(v0.2.0) = %s", got)
+	}
+	if got := ids(r.Open("0.2.0")); got != "python,rust" {
+		t.Errorf("Open(0.2.0) = %s", got)
+	}
+	if got := ids(r.Open("0.1.0")); got != "" {
+		t.Errorf("Open(0.1.0) = %s", got)
+	}
+	if got := strings.Join(r.Versions(), ","); got != "0.1.0,0.2.0" {
+		t.Errorf("Versions() = %s", got)
+	}
+
+	want := "5 items, 2 completed, 1 in progress, 1 future, 1 blocked\n" +
+		"  v0.1.0: 1/1 completed\n" +
+		"  v0.2.0: 1/3 completed (2 open)\n"
+	if got := r.Summary(); got != want {
+		t.Errorf("Summary() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRoadmap_Validate(t *testing.T) {
+	r, err := Parse([]byte(`{
+  "areas": [{"id": "core", "name": "Core"}],
+  "items": [
+    {"id": "a", "title": "A", "status": "completed", "version": "0.1.0", "area": "core"},
+    {"id": "a", "title": "", "status": "done", "version": "next", "area": "docs", "phase": "v9"},
+    {"title": "B", "status": "planned"}
+  ]
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = r.Validate()
+	if err == nil {
+		t.Fatal("Validate() should fail")
+	}
+	for _, want := range []string{
+		"item a: duplicate id",
+		"item a: missing title",
+		`item a: unknown status "done"`,
+		`item a: invalid version "next"`,
+		`item a: unknown area "docs"`,
+		`item a: unknown phase "v9"`,
+		"item #3: missing id",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() = %v, want %q", err, want)
+		}
+	}
+}
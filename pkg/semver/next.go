@@ -0,0 +1,62 @@
+package semver
+
+// Bump is the size of a version increment.
+type Bump int
+
+const (
+	BumpNone Bump = iota
+	BumpPatch
+	BumpMinor
+	BumpMajor
+)
+
+// String returns the bump's name: "none", "patch", "minor" or "major".
+func (b Bump) String() string {
+	switch b {
+	case BumpPatch:
+		return "patch"
+	case BumpMinor:
+		return "minor"
+	case BumpMajor:
+		return "major"
+	default:
+		return "none"
+	}
+}
+
+// Next returns the release after v for a bump. While the major version is
+// 0, a major bump increments the minor version, since 0.x releases make no
+// compatibility promises. A prerelease of the bumped version is released
+// as is: the next release after v1.3.0-rc.2 with a minor bump is v1.3.0.
+func (v Version) Next(b Bump) Version {
+	if b == BumpMajor && v.Major == 0 {
+		b = BumpMinor
+	}
+
+	next := v.Release()
+	if v.Prerelease != "" && bumps(v, b) {
+		return next
+	}
+	switch b {
+	case BumpMajor:
+		next = Version{Major: v.Major + 1}
+	case BumpMinor:
+		next = Version{Major: v.Major, Minor: v.Minor + 1}
+	case BumpPatch:
+		next.Patch++
+	}
+	return next
+}
+
+// bumps reports whether the prerelease v already carries a bump: v2.0.0-rc.1
+// is a major bump, v1.3.0-rc.1 a minor one and v1.2.4-rc.1 a patch.
+func bumps(v Version, b Bump) bool {
+	switch {
+	case v.Minor == 0 && v.Patch == 0:
+		return true
+	case v.Patch == 0:
+		return b <= BumpMinor
+	default:
+		return b <= BumpPatch
+	}
+}
@@ -0,0 +1,173 @@
+// Package semver parses and compares semantic version tags such as v1.2.3-rc.1.
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// versionRegex matches a semantic version with an optional leading "v".
+var versionRegex = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-([0-9A-Za-z.-]+))?(?:\+([0-9A-Za-z.-]+))?$`)
+
+// Version is a parsed semantic version.
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string // e.g., "rc.1" (empty for a release)
+	Build      string // build metadata, ignored when comparing
+}
+
+// Parse parses a version such as "v1.2.3" or "1.2.3-rc.1+build.5".
+func Parse(s string) (Version, error) {
+	m := versionRegex.FindStringSubmatch(s)
+	if m == nil {
+		return Version{}, fmt.Errorf("invalid semantic version %q", s)
+	}
+
+	var v Version
+	var err error
+	if v.Major, err = strconv.Atoi(m[1]); err != nil {
+		return Version{}, fmt.Errorf("invalid major version in %q: %w", s, err)
+	}
+	if v.Minor, err = strconv.Atoi(m[2]); err != nil {
+		return Version{}, fmt.Errorf("invalid minor version in %q: %w", s, err)
+	}
+	if v.Patch, err = strconv.Atoi(m[3]); err != nil {
+		return Version{}, fmt.Errorf("invalid patch version in %q: %w", s, err)
+	}
+	v.Prerelease = m[4]
+	v.Build = m[5]
+	return v, nil
+}
+
+// IsValid reports whether s is a semantic version.
+func IsValid(s string) bool {
+	_, err := Parse(s)
+	return err == nil
+}
+
+// String returns the version with a leading "v".
+func (v Version) String() string {
+	s := fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Release returns the version without prerelease and build metadata.
+func (v Version) Release() Version {
+	return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+}
+
+// Candidate returns N for a release candidate "vX.Y.Z-rc.N".
+func (v Version) Candidate() (int, bool) {
+	n, ok := strings.CutPrefix(v.Prerelease, "rc.")
+	if !ok {
+		return 0, false
+	}
+	num, err := strconv.Atoi(n)
+	if err != nil || num < 1 {
+		return 0, false
+	}
+	return num, true
+}
+
+// NextCandidate returns the release candidate following the highest
+// existing "-rc.N" tag of release, or rc.1 if there is none.
+func NextCandidate(tags []string, release Version) Version {
+	release = release.Release()
+	next := 1
+	for _, tag := range tags {
+		v, err := Parse(tag)
+		if err != nil || v.Release() != release {
+			continue
+		}
+		if n, ok := v.Candidate(); ok && n >= next {
+			next = n + 1
+		}
+	}
+	release.Prerelease = fmt.Sprintf("rc.%d", next)
+	return release
+}
+
+// Compare returns -1, 0 or 1 as v has lower, equal or higher precedence than o.
+func (v Version) Compare(o Version) int {
+	if c := compareInt(v.Major, o.Major); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Minor, o.Minor); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Patch, o.Patch); c != 0 {
+		return c
+	}
+	return comparePrerelease(v.Prerelease, o.Prerelease)
+}
+
+// Compare parses and compares two versions. Invalid versions sort before
+// valid ones and compare equal to each other.
+func Compare(a, b string) int {
+	va, errA := Parse(a)
+	vb, errB := Parse(b)
+	switch {
+	case errA != nil && errB != nil:
+		return 0
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	}
+	return va.Compare(vb)
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// comparePrerelease compares prerelease strings by semver rules: a release
+// outranks any prerelease, and dot-separated identifiers compare numerically
+// when both are numbers and lexically otherwise.
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if c := compareInt(an, bn); c != 0 {
+				return c
+			}
+		case aErr == nil:
+			return -1 // numeric identifiers sort first
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return compareInt(len(as), len(bs))
+}
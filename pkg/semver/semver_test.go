@@ -0,0 +1,150 @@
+package semver
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Version
+	}{
+		{"v1.2.3", Version{Major: 1, Minor: 2, Patch: 3}},
+		{"0.10.0", Version{Minor: 10}},
+		{"v2.0.0-rc.1", Version{Major: 2, Prerelease: "rc.1"}},
+		{"v1.0.0-beta+exp.sha.5114f85", Version{Major: 1, Prerelease: "beta", Build: "exp.sha.5114f85"}},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "v1", "v1.2", "1.2.3.4", "v01.2.3", "latest", "v1.2.3-"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) should fail", bad)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	for _, s := range []string{"v1.2.3", "v0.1.0-rc.2", "v1.0.0+build"} {
+		v, err := Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.String() != s {
+			t.Errorf("String() = %q, want %q", v.String(), s)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// Ordered by increasing precedence, from the semver specification
+	ordered := []string{
+		"v1.0.0-alpha",
+		"v1.0.0-alpha.1",
+		"v1.0.0-alpha.beta",
+		"v1.0.0-beta",
+		"v1.0.0-beta.2",
+		"v1.0.0-beta.11",
+		"v1.0.0-rc.1",
+		"v1.0.0",
+		"v1.0.1",
+		"v1.2.0",
+		"v1.10.0",
+		"v2.0.0",
+	}
+
+	for i := range ordered {
+		for j := range ordered {
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			if got := Compare(ordered[i], ordered[j]); got != want {
+				t.Errorf("Compare(%s, %s) = %d, want %d", ordered[i], ordered[j], got, want)
+			}
+		}
+	}
+
+	if Compare("v1.0.0+a", "v1.0.0+b") != 0 {
+		t.Error("build metadata should not affect precedence")
+	}
+	if Compare("unreleased", "v0.1.0") != -1 || Compare("v0.1.0", "unreleased") != 1 {
+		t.Error("invalid versions should sort first")
+	}
+}
+
+func TestCandidate(t *testing.T) {
+	tests := map[string]int{
+		"v1.2.0-rc.3":  3,
+		"v1.2.0-rc.10": 10,
+		"v1.2.0":       0,
+		"v1.2.0-beta":  0,
+		"v1.2.0-rc.0":  0,
+		"v1.2.0-rc.x":  0,
+	}
+	for in, want := range tests {
+		n, ok := mustParse(t, in).Candidate()
+		if n != want || ok != (want > 0) {
+			t.Errorf("%s.Candidate() = %d, %v, want %d", in, n, ok, want)
+		}
+	}
+}
+
+func TestNextCandidate(t *testing.T) {
+	tags := []string{"v1.1.0", "v1.2.0-rc.1", "v1.2.0-rc.2", "v1.2.0-beta.5", "v1.3.0-rc.7", "junk"}
+
+	tests := map[string]string{
+		"v1.2.0":      "v1.2.0-rc.3",
+		"v1.3.0":      "v1.3.0-rc.8",
+		"v1.4.0":      "v1.4.0-rc.1",
+		"v1.2.0-rc.1": "v1.2.0-rc.3",
+	}
+	for release, want := range tests {
+		if got := NextCandidate(tags, mustParse(t, release)).String(); got != want {
+			t.Errorf("NextCandidate(%s) = %s, want %s", release, got, want)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		version string
+		bump    Bump
+		want    string
+	}{
+		{"v1.2.3", BumpMajor, "v2.0.0"},
+		{"v1.2.3", BumpMinor, "v1.3.0"},
+		{"v1.2.3", BumpPatch, "v1.2.4"},
+		{"v1.2.3", BumpNone, "v1.2.3"},
+		{"v0.8.1", BumpMajor, "v0.9.0"},
+		{"v0.8.1", BumpMinor, "v0.9.0"},
+		{"v0.8.1", BumpPatch, "v0.8.2"},
+		{"v1.3.0-rc.2", BumpMinor, "v1.3.0"},
+		{"v1.3.0-rc.2", BumpMajor, "v2.0.0"},
+		{"v1.2.4-rc.1", BumpMinor, "v1.3.0"},
+		{"v2.0.0-rc.1", BumpMajor, "v2.0.0"},
+	}
+	for _, tt := range tests {
+		if got := mustParse(t, tt.version).Next(tt.bump).String(); got != tt.want {
+			t.Errorf("%s.Next(%s) = %s, want %s", tt.version, tt.bump, got, tt.want)
+		}
+	}
+}
+
+func mustParse(t *testing.T, s string) Version {
+	t.Helper()
+	v, err := Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
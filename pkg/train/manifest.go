@@ -0,0 +1,111 @@
+// Package train releases several interdependent Go repositories together:
+// in dependency order, bumping each repository's requirements on the ones
+// released before it.
+package train
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/agentplexus/agent-team-release/pkg/gomod"
+)
+
+// DefaultManifest is the manifest file read when none is given.
+const DefaultManifest = "release-train.yaml"
+
+// Manifest lists the repositories of a release train.
+type Manifest struct {
+	Tidy  bool   `yaml:"tidy"` // Run "go mod tidy" after bumping requirements
+	Repos []Repo `yaml:"repos"`
+}
+
+// Repo is a repository of a release train.
+type Repo struct {
+	Name    string `yaml:"name"`    // Display name (default: the directory name)
+	Path    string `yaml:"path"`    // Repository directory, relative to the manifest
+	Version string `yaml:"version"` // Version to release
+
+	dir      string   // Resolved directory
+	module   string   // Module path of the root go.mod
+	requires []string // Modules the root go.mod requires
+}
+
+// Dir returns the repository's directory.
+func (r Repo) Dir() string {
+	return r.dir
+}
+
+// Module returns the module path of the repository's root go.mod.
+func (r Repo) Module() string {
+	return r.module
+}
+
+// LoadManifest reads a release train manifest and the go.mod file of each
+// of its repositories. Paths are resolved relative to the manifest.
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := m.resolve(filepath.Dir(path)); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &m, nil
+}
+
+// resolve validates the repositories and reads their module paths.
+func (m *Manifest) resolve(base string) error {
+	if len(m.Repos) == 0 {
+		return fmt.Errorf("no repos listed")
+	}
+
+	names := make(map[string]bool)
+	modules := make(map[string]string)
+	for i := range m.Repos {
+		r := &m.Repos[i]
+		if r.Path == "" {
+			return fmt.Errorf("repo %d: path is required", i+1)
+		}
+		r.dir = r.Path
+		if !filepath.IsAbs(r.dir) {
+			r.dir = filepath.Join(base, r.dir)
+		}
+		if r.Name == "" {
+			r.Name = filepath.Base(filepath.Clean(r.dir))
+		}
+		if names[r.Name] {
+			return fmt.Errorf("repo %s is listed twice", r.Name)
+		}
+		names[r.Name] = true
+
+		if r.Version == "" {
+			return fmt.Errorf("repo %s: version is required", r.Name)
+		}
+		if !strings.HasPrefix(r.Version, "v") {
+			r.Version = "v" + r.Version
+		}
+
+		f, err := gomod.ReadFile(filepath.Join(r.dir, gomod.FileName))
+		if err != nil {
+			return fmt.Errorf("repo %s: %w", r.Name, err)
+		}
+		r.module = f.Module
+		for _, req := range f.Require {
+			r.requires = append(r.requires, req.Path)
+		}
+		if other, ok := modules[r.module]; ok {
+			return fmt.Errorf("repos %s and %s are both module %s", other, r.Name, r.module)
+		}
+		modules[r.module] = r.Name
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package train
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Order returns the manifest's repositories in dependency order: each
+// repository comes after every train repository its go.mod requires.
+// Repositories that don't depend on each other keep their manifest order.
+func (m *Manifest) Order() ([]Repo, error) {
+	byModule := make(map[string]int)
+	for i, r := range m.Repos {
+		byModule[r.module] = i
+	}
+
+	// deps[i] counts the unreleased train repositories repo i requires
+	deps := make([]int, len(m.Repos))
+	dependents := make([][]int, len(m.Repos))
+	for i, r := range m.Repos {
+		for _, req := range r.requires {
+			if j, ok := byModule[req]; ok && j != i {
+				deps[i]++
+				dependents[j] = append(dependents[j], i)
+			}
+		}
+	}
+
+	var order []Repo
+	done := make([]bool, len(m.Repos))
+	for len(order) < len(m.Repos) {
+		next := -1
+		for i := range m.Repos {
+			if !done[i] && deps[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			return nil, fmt.Errorf("dependency cycle between %s", strings.Join(m.cycle(done), ", "))
+		}
+		done[next] = true
+		order = append(order, m.Repos[next])
+		for _, d := range dependents[next] {
+			deps[d]--
+		}
+	}
+	return order, nil
+}
+
+// cycle returns the names of the repositories left unordered.
+func (m *Manifest) cycle(done []bool) []string {
+	var names []string
+	for i, r := range m.Repos {
+		if !done[i] {
+			names = append(names, r.Name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,107 @@
+package train
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agentplexus/agent-team-release/pkg/checks"
+)
+
+// Report is the outcome of a release train.
+type Report struct {
+	Success bool         `json:"success" toon:"success"`
+	Order   []string     `json:"order" toon:"order"` // Repository names in release order
+	Repos   []RepoResult `json:"repos" toon:"repos"`
+	Error   string       `json:"error,omitempty" toon:"error,omitempty"`
+}
+
+// RepoResult is the outcome of one repository of a release train.
+type RepoResult struct {
+	Name    string       `json:"name" toon:"name"`
+	Path    string       `json:"path" toon:"path"`
+	Module  string       `json:"module" toon:"module"`
+	Version string       `json:"version" toon:"version"`
+	Status  string       `json:"status" toon:"status"`
+	Bumps   []Bump       `json:"bumps,omitempty" toon:"bumps,omitempty"`
+	Areas   []AreaResult `json:"areas,omitempty" toon:"areas,omitempty"`
+	Error   string       `json:"error,omitempty" toon:"error,omitempty"`
+}
+
+// Bump is a requirement updated to a version released by the train.
+type Bump struct {
+	Module string `json:"module" toon:"module"`
+	From   string `json:"from" toon:"from"`
+	To     string `json:"to" toon:"to"`
+}
+
+// AreaResult is the status of a validation area for a repository.
+type AreaResult struct {
+	Area   string `json:"area" toon:"area"`
+	Status string `json:"status" toon:"status"`
+}
+
+func (r *RepoResult) fail(status string, err error) {
+	r.Status = status
+	if err != nil {
+		r.Error = err.Error()
+	}
+}
+
+// noGo reports whether any validation area of the repository is NO-GO.
+func (r *RepoResult) noGo() bool {
+	return len(r.noGoAreas()) > 0
+}
+
+// noGoAreas returns the repository's NO-GO validation areas.
+func (r *RepoResult) noGoAreas() []string {
+	var areas []string
+	for _, a := range r.Areas {
+		if a.Status == string(checks.StatusNoGo) {
+			areas = append(areas, a.Area)
+		}
+	}
+	return areas
+}
+
+// Summary returns a human-readable report of the train.
+func (r *Report) Summary() string {
+	var sb strings.Builder
+	sb.WriteString("Release train:\n")
+	for _, repo := range r.Repos {
+		fmt.Fprintf(&sb, "  %s %-20s %-10s %s\n", statusIcon(repo.Status), repo.Name, repo.Version, repo.Status)
+		for _, b := range repo.Bumps {
+			fmt.Fprintf(&sb, "      %s %s → %s\n", b.Module, b.From, b.To)
+		}
+		for _, a := range repo.Areas {
+			if a.Status == string(checks.StatusNoGo) {
+				fmt.Fprintf(&sb, "      %s: %s %s\n", a.Area, checks.StatusNoGo.Icon(), a.Status)
+			}
+		}
+		if repo.Error != "" {
+			fmt.Fprintf(&sb, "      Error: %s\n", repo.Error)
+		}
+	}
+
+	sb.WriteString("\n")
+	if r.Success {
+		sb.WriteString("✅ Release train completed\n")
+	} else {
+		fmt.Fprintf(&sb, "❌ %s\n", r.Error)
+	}
+	return sb.String()
+}
+
+func statusIcon(status string) string {
+	switch status {
+	case StatusReleased, StatusAlreadyReleased, StatusPlanned:
+		return "✓"
+	case StatusNoGo:
+		return "🛑"
+	case StatusFailed:
+		return "✗"
+	case StatusCancelled:
+		return "⏸"
+	default:
+		return "○"
+	}
+}
@@ -0,0 +1,207 @@
+package train
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/agentplexus/agent-team-release/pkg/git"
+	"github.com/agentplexus/agent-team-release/pkg/gomod"
+	"github.com/agentplexus/agent-team-release/pkg/proc"
+	"github.com/agentplexus/agent-team-release/pkg/workflow"
+)
+
+// Repository statuses in a train report.
+const (
+	StatusReleased        = "released"
+	StatusAlreadyReleased = "already released" // Tagged by an earlier run of the train
+	StatusPlanned         = "planned"          // Dry run succeeded
+	StatusNoGo            = "no-go"            // A validation area is NO-GO
+	StatusFailed          = "failed"
+	StatusCancelled       = "cancelled"
+	StatusNotStarted      = "not started"
+)
+
+// Train releases the repositories of a manifest in dependency order.
+type Train struct {
+	Manifest   *Manifest
+	DryRun     bool
+	SkipChecks bool
+	SkipCI     bool
+	Ctx        context.Context // Cancelled on interrupt (nil = never)
+
+	// Runner returns the workflow runner for a repository (nil = defaults)
+	Runner func(repo Repo) *workflow.Runner
+
+	// Released, if set, is called after each repository's workflow runs
+	Released func(repo Repo, ctx *workflow.Context, result *workflow.WorkflowResult)
+}
+
+// Run releases each repository in turn, bumping its requirements on the
+// repositories released before it in a "chore(deps)" commit first. The
+// train stops at the first repository that fails or is NO-GO; the report
+// lists what happened to every repository.
+//
+// A repository whose version is already tagged is skipped, so a stopped
+// train can be run again once the problem is fixed.
+func (t *Train) Run() *Report {
+	report := &Report{Success: true}
+	order, err := t.Manifest.Order()
+	if err != nil {
+		report.Success = false
+		report.Error = err.Error()
+		return report
+	}
+	for _, r := range order {
+		report.Order = append(report.Order, r.Name)
+		report.Repos = append(report.Repos, RepoResult{
+			Name:    r.Name,
+			Path:    r.Path,
+			Module:  r.module,
+			Version: r.Version,
+			Status:  StatusNotStarted,
+		})
+	}
+
+	// released maps the modules released so far to their versions
+	released := make(map[string]string)
+	for i, r := range order {
+		res := &report.Repos[i]
+		t.release(r, res, released)
+		switch res.Status {
+		case StatusReleased, StatusAlreadyReleased, StatusPlanned:
+			released[r.module] = r.Version
+			continue
+		}
+		report.Success = false
+		report.Error = fmt.Sprintf("train stopped at %s: %s", r.Name, res.Error)
+		break
+	}
+	return report
+}
+
+// release bumps and releases one repository, recording the outcome in res.
+func (t *Train) release(r Repo, res *RepoResult, released map[string]string) {
+	g := git.New(r.dir).WithContext(t.Ctx)
+	tags, err := g.AllTags()
+	if err != nil {
+		res.fail(StatusFailed, fmt.Errorf("failed to list tags: %w", err))
+		return
+	}
+	if slices.Contains(tags, r.Version) {
+		res.Status = StatusAlreadyReleased
+		return
+	}
+
+	bumps, err := t.bump(r, g, released)
+	res.Bumps = bumps
+	if err != nil {
+		res.fail(StatusFailed, err)
+		return
+	}
+
+	ctx := workflow.NewContext(r.dir, r.Version)
+	if t.Ctx != nil {
+		ctx.Ctx = t.Ctx
+	}
+	ctx.SkipChecks = t.SkipChecks
+	ctx.SkipCI = t.SkipCI
+
+	runner := workflow.NewRunner()
+	if t.Runner != nil {
+		runner = t.Runner(r)
+	}
+	runner.DryRun = t.DryRun
+
+	result := runner.Run(workflow.TrainWorkflow(r.Version), ctx)
+	if t.Released != nil {
+		t.Released(r, ctx, result)
+	}
+
+	for area, status := range workflow.AreaStatuses(ctx.Data) {
+		res.Areas = append(res.Areas, AreaResult{Area: string(area), Status: string(status)})
+	}
+	slices.SortFunc(res.Areas, func(a, b AreaResult) int { return strings.Compare(a.Area, b.Area) })
+
+	switch {
+	case result.Success && t.DryRun:
+		res.Status = StatusPlanned
+	case result.Success:
+		res.Status = StatusReleased
+	case result.Cancelled:
+		res.fail(StatusCancelled, fmt.Errorf("interrupted"))
+	case res.noGo():
+		res.fail(StatusNoGo, fmt.Errorf("%s NO-GO", strings.Join(res.noGoAreas(), ", ")))
+	default:
+		res.fail(StatusFailed, result.Error)
+	}
+}
+
+// bump updates the repository's requirements on released train modules and
+// commits the change. In a dry run, the bumps are only listed.
+func (t *Train) bump(r Repo, g *git.Git, released map[string]string) ([]Bump, error) {
+	path := filepath.Join(r.dir, gomod.FileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := gomod.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var bumps []Bump
+	for _, req := range f.Require {
+		version, ok := released[req.Path]
+		if !ok || req.Version == version {
+			continue
+		}
+		bumps = append(bumps, Bump{Module: req.Path, From: req.Version, To: version})
+		data, _ = gomod.SetRequire(data, req.Path, version)
+	}
+	if len(bumps) == 0 || t.DryRun {
+		return bumps, nil
+	}
+
+	dirty, err := g.IsDirty()
+	if err != nil {
+		return bumps, fmt.Errorf("failed to check git status: %w", err)
+	}
+	if dirty {
+		return bumps, fmt.Errorf("working directory has uncommitted changes; commit or stash them first")
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return bumps, err
+	}
+	if t.Manifest.Tidy {
+		cmd := proc.Command(t.Ctx, "go", "mod", "tidy")
+		cmd.Dir = r.dir
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return bumps, fmt.Errorf("go mod tidy failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+	}
+	if err := g.CommitAll(bumpMessage(bumps), false); err != nil {
+		return bumps, fmt.Errorf("failed to commit requirement bumps: %w", err)
+	}
+	return bumps, nil
+}
+
+// bumpMessage returns the commit message for a set of requirement bumps.
+func bumpMessage(bumps []Bump) string {
+	if len(bumps) == 1 {
+		return fmt.Sprintf("chore(deps): bump %s to %s", bumps[0].Module, bumps[0].To)
+	}
+	var sb strings.Builder
+	sb.WriteString("chore(deps): bump release train dependencies\n")
+	for _, b := range bumps {
+		fmt.Fprintf(&sb, "\n- %s %s -> %s", b.Module, b.From, b.To)
+	}
+	return sb.String()
+}
@@ -0,0 +1,250 @@
+package train
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func gitRun(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v: %s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// initRepo creates a repository for module path with a bare remote, a
+// go.mod requiring the given modules at v1.0.0, and a v1.0.0 tag.
+func initRepo(t *testing.T, root, name, path string, requires ...string) {
+	t.Helper()
+	dir := filepath.Join(root, name)
+	remote := filepath.Join(root, name+".git")
+	for _, d := range []string{dir, remote} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gitRun(t, remote, "init", "--bare")
+	gitRun(t, dir, "init")
+	gitRun(t, dir, "config", "user.email", "test@example.com")
+	gitRun(t, dir, "config", "user.name", "Test User")
+	gitRun(t, dir, "remote", "add", "origin", remote)
+
+	goMod := "module " + path + "\n\ngo 1.24\n"
+	if len(requires) > 0 {
+		goMod += "\nrequire (\n"
+		for _, r := range requires {
+			goMod += "\t" + r + " v1.0.0\n"
+		}
+		goMod += ")\n"
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, dir, "add", "-A")
+	gitRun(t, dir, "commit", "-m", "feat: initial")
+	gitRun(t, dir, "tag", "-a", "v1.0.0", "-m", "Release v1.0.0")
+	gitRun(t, dir, "push", "-u", "origin", "HEAD", "--tags")
+}
+
+// initTrain creates three repositories where app requires sdk and core, and
+// sdk requires core, listed in the manifest in reverse dependency order.
+func initTrain(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	root := t.TempDir()
+	initRepo(t, root, "core", "example.com/core")
+	initRepo(t, root, "sdk", "example.com/sdk", "example.com/core")
+	initRepo(t, root, "app", "example.com/app", "example.com/core", "example.com/sdk")
+
+	manifest := `repos:
+  - path: app
+    version: v1.1.0
+  - path: sdk
+    version: 1.1.0
+  - path: core
+    version: v1.1.0
+`
+	path := filepath.Join(root, DefaultManifest)
+	if err := os.WriteFile(path, []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadManifest(t *testing.T) {
+	path := initTrain(t)
+	m, err := LoadManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Repos) != 3 {
+		t.Fatalf("Repos = %+v", m.Repos)
+	}
+	sdk := m.Repos[1]
+	if sdk.Name != "sdk" || sdk.Version != "v1.1.0" || sdk.Module() != "example.com/sdk" {
+		t.Errorf("sdk = %+v", sdk)
+	}
+	if sdk.Dir() != filepath.Join(filepath.Dir(path), "sdk") {
+		t.Errorf("sdk.Dir() = %s", sdk.Dir())
+	}
+
+	for name, manifest := range map[string]string{
+		"empty":      "repos: []\n",
+		"no version": "repos:\n  - path: core\n",
+		"no go.mod":  "repos:\n  - path: missing\n    version: v1.0.0\n",
+		"duplicate":  "repos:\n  - path: core\n    version: v1.0.0\n  - path: ./core\n    version: v1.0.0\n",
+	} {
+		bad := filepath.Join(filepath.Dir(path), "bad.yaml")
+		if err := os.WriteFile(bad, []byte(manifest), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadManifest(bad); err == nil {
+			t.Errorf("LoadManifest(%s) should fail", name)
+		}
+	}
+}
+
+func TestOrder(t *testing.T) {
+	m := &Manifest{Repos: []Repo{
+		{Name: "app", module: "a", requires: []string{"s", "c", "x"}},
+		{Name: "docs", module: "d"},
+		{Name: "sdk", module: "s", requires: []string{"c"}},
+		{Name: "core", module: "c"},
+	}}
+	order, err := m.Order()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, r := range order {
+		names = append(names, r.Name)
+	}
+	if got := strings.Join(names, ","); got != "docs,core,sdk,app" {
+		t.Errorf("Order() = %s", got)
+	}
+
+	m.Repos[3].requires = []string{"a"}
+	if _, err := m.Order(); err == nil || !strings.Contains(err.Error(), "app, core, sdk") {
+		t.Errorf("Order() with a cycle = %v", err)
+	}
+}
+
+func TestTrain(t *testing.T) {
+	path := initTrain(t)
+	m, err := LoadManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := (&Train{Manifest: m, SkipChecks: true, SkipCI: true}).Run()
+	if !report.Success {
+		t.Fatalf("train failed:\n%s", report.Summary())
+	}
+	if got := strings.Join(report.Order, ","); got != "core,sdk,app" {
+		t.Errorf("Order = %s", got)
+	}
+
+	root := filepath.Dir(path)
+	for _, name := range []string{"core", "sdk", "app"} {
+		tags := gitRun(t, filepath.Join(root, name), "ls-remote", "--tags", "origin")
+		if !strings.Contains(tags, "refs/tags/v1.1.0") {
+			t.Errorf("%s: v1.1.0 not pushed:\n%s", name, tags)
+		}
+	}
+
+	goMod, err := os.ReadFile(filepath.Join(root, "app", "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"example.com/core v1.1.0", "example.com/sdk v1.1.0"} {
+		if !strings.Contains(string(goMod), want) {
+			t.Errorf("app go.mod missing %q:\n%s", want, goMod)
+		}
+	}
+	app := report.Repos[2]
+	if len(app.Bumps) != 2 || app.Bumps[0] != (Bump{Module: "example.com/core", From: "v1.0.0", To: "v1.1.0"}) {
+		t.Errorf("app bumps = %+v", app.Bumps)
+	}
+	log := gitRun(t, filepath.Join(root, "app"), "log", "--format=%s", "-3")
+	if !strings.Contains(log, "chore(deps): bump release train dependencies") {
+		t.Errorf("app log missing bump commit:\n%s", log)
+	}
+
+	// Running the train again skips the released repositories
+	again := (&Train{Manifest: m, SkipChecks: true, SkipCI: true}).Run()
+	if !again.Success || again.Repos[0].Status != StatusAlreadyReleased {
+		t.Errorf("second run:\n%s", again.Summary())
+	}
+}
+
+func TestTrain_DryRun(t *testing.T) {
+	path := initTrain(t)
+	m, err := LoadManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := (&Train{Manifest: m, DryRun: true, SkipChecks: true, SkipCI: true}).Run()
+	if !report.Success {
+		t.Fatalf("dry run failed:\n%s", report.Summary())
+	}
+	for _, r := range report.Repos {
+		if r.Status != StatusPlanned {
+			t.Errorf("%s status = %s", r.Name, r.Status)
+		}
+	}
+	if len(report.Repos[1].Bumps) != 1 {
+		t.Errorf("sdk bumps = %+v", report.Repos[1].Bumps)
+	}
+
+	root := filepath.Dir(path)
+	if tags := gitRun(t, filepath.Join(root, "core"), "tag"); strings.Contains(tags, "v1.1.0") {
+		t.Error("dry run created a tag")
+	}
+	if status := gitRun(t, filepath.Join(root, "sdk"), "status", "--porcelain"); status != "" {
+		t.Errorf("dry run changed sdk:\n%s", status)
+	}
+}
+
+func TestTrain_NoGo(t *testing.T) {
+	path := initTrain(t)
+	m, err := LoadManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The repositories have no README, LICENSE or CHANGELOG, so the
+	// validation areas of the first repository are NO-GO
+	report := (&Train{Manifest: m, SkipCI: true}).Run()
+	if report.Success {
+		t.Fatalf("train should stop:\n%s", report.Summary())
+	}
+	core, sdk := report.Repos[0], report.Repos[1]
+	if core.Status != StatusNoGo || !strings.Contains(report.Error, "train stopped at core") {
+		t.Errorf("core = %+v, error = %s", core, report.Error)
+	}
+	if sdk.Status != StatusNotStarted {
+		t.Errorf("sdk status = %s", sdk.Status)
+	}
+
+	root := filepath.Dir(path)
+	if tags := gitRun(t, filepath.Join(root, "core"), "ls-remote", "--tags", "origin"); strings.Contains(tags, "v1.1.0") {
+		t.Error("NO-GO repository was tagged")
+	}
+	summary := report.Summary()
+	for _, want := range []string{"🛑 core", "NO-GO", "○ sdk", "not started"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary missing %q:\n%s", want, summary)
+		}
+	}
+}
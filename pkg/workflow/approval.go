@@ -0,0 +1,129 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/agentplexus/agent-team-release/pkg/actions"
+	"github.com/agentplexus/agent-team-release/pkg/interactive"
+)
+
+// ErrAborted is returned for a step the user aborted at an approval gate.
+var ErrAborted = errors.New("aborted by user")
+
+// needsApproval reports whether a step must be confirmed before it runs.
+func (r *Runner) needsApproval(step *Step, ctx *Context) bool {
+	return step.Irreversible && ctx.Interactive && !ctx.DryRun && r.Prompter != nil && !ctx.rollback
+}
+
+// approve asks the prompter whether to run an irreversible step, showing the
+// step's preview. Prompts are serialized since steps may run concurrently.
+func (r *Runner) approve(step *Step, ctx *Context) (interactive.ApprovalAction, error) {
+	var proposals []actions.Proposal
+	if step.Preview != nil {
+		p, err := step.Preview(ctx)
+		if err != nil {
+			return interactive.ApprovalAbort, fmt.Errorf("preparing preview: %w", err)
+		}
+		proposals = p
+	}
+	if len(proposals) == 0 {
+		proposals = []actions.Proposal{{Description: step.Description}}
+	}
+
+	r.promptMu.Lock()
+	defer r.promptMu.Unlock()
+
+	id := "approve_" + strings.ReplaceAll(strings.ToLower(step.Name), " ", "_")
+	if step.Ref != "" {
+		id = "approve_" + step.Ref
+	}
+	return interactive.RequestApproval(r.Prompter, id, fmt.Sprintf("%s cannot be undone automatically. Proceed?", step.Name), proposals)
+}
+
+// ask runs fn with the runner's prompter, serialized with approval prompts.
+// It does nothing outside interactive mode or in a dry run.
+func (ctx *Context) ask(fn func(p interactive.Prompter) error) error {
+	r := ctx.runner
+	if !ctx.Interactive || ctx.DryRun || r == nil || r.Prompter == nil {
+		return nil
+	}
+	r.promptMu.Lock()
+	defer r.promptMu.Unlock()
+	return fn(r.Prompter)
+}
+
+// previewPush describes the refs a push will send to the remote.
+func previewPush(ctx *Context) ([]actions.Proposal, error) {
+	g := ctx.git()
+
+	branch, err := g.CurrentBranch()
+	if err != nil {
+		return nil, err
+	}
+	// A dry run stays on the current branch instead of switching
+	planned := ctx.DryRun && ctx.Data["planned_branch"] != "" && ctx.Data["planned_branch"] != branch
+	if planned {
+		branch = ctx.Data["planned_branch"]
+	}
+	meta := map[string]string{
+		"remote": g.Remote,
+		"ref":    "refs/heads/" + branch,
+	}
+	if url, err := g.RemoteURL(); err == nil && url != "" {
+		meta["remote_url"] = url
+	}
+
+	var commits string
+	if planned {
+		if ok, err := g.RemoteBranchExists(branch); err == nil && !ok {
+			meta["upstream"] = "none (the branch will be created on the remote)"
+		}
+	} else if status, err := g.Status(); err == nil && status.RemoteBranch != "" {
+		commits, _ = g.Log(status.RemoteBranch, "HEAD", "")
+		meta["commits"] = fmt.Sprintf("%d", status.Ahead)
+	} else if err == nil {
+		meta["upstream"] = "none (the branch will be created on the remote)"
+	}
+	// In a dry run the release commit doesn't exist yet
+	if planned := ctx.Data["planned_commit"]; planned != "" && ctx.DryRun {
+		commits = strings.TrimSpace(commits + "\n(new) " + planned)
+	}
+	if commits = strings.TrimSpace(commits); commits != "" {
+		meta["log"] = commits
+	}
+
+	return []actions.Proposal{{
+		Description: fmt.Sprintf("Push %s to %s", branch, g.Remote),
+		NewContent:  commits,
+		Metadata:    meta,
+	}}, nil
+}
+
+// previewTag describes the tag that will be created and pushed.
+func previewTag(ctx *Context) ([]actions.Proposal, error) {
+	g := ctx.git()
+	tag := ReleaseTag(ctx)
+
+	meta := map[string]string{
+		"tag":     tag,
+		"ref":     "refs/tags/" + tag,
+		"remote":  g.Remote,
+		"message": tagMessage(tag),
+	}
+	if planned := ctx.Data["planned_branch"]; ctx.DryRun && planned != "" {
+		meta["branch"] = planned
+	} else if head, err := g.ShortCommit(); err == nil {
+		meta["commit"] = head
+	}
+	if url, err := g.RemoteURL(); err == nil && url != "" {
+		meta["remote_url"] = url
+	}
+
+	return []actions.Proposal{{
+		Description: fmt.Sprintf("Create tag %s and push it to %s", tag, g.Remote),
+		NewContent:  tagMessage(tag),
+		Metadata:    meta,
+	}}, nil
+}
@@ -0,0 +1,165 @@
+package workflow
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/agent-team-release/pkg/actions"
+	"github.com/agentplexus/agent-team-release/pkg/interactive"
+)
+
+// approvalPrompter answers approval questions with a fixed choice.
+type approvalPrompter struct {
+	choice    string
+	questions []string
+	proposals []actions.Proposal
+}
+
+func (p *approvalPrompter) Ask(q interactive.Question) (interactive.Answer, error) {
+	p.questions = append(p.questions, q.ID)
+	return interactive.Answer{QuestionID: q.ID, Selected: []string{p.choice}}, nil
+}
+
+func (p *approvalPrompter) ShowProposal(proposal actions.Proposal) error {
+	p.proposals = append(p.proposals, proposal)
+	return nil
+}
+
+func (p *approvalPrompter) Confirm(message string) (bool, error) { return false, nil }
+func (p *approvalPrompter) Info(message string)                  {}
+func (p *approvalPrompter) Warn(message string)                  {}
+func (p *approvalPrompter) Error(message string)                 {}
+
+// gatedWorkflow returns a workflow with an undoable step followed by an
+// irreversible one, recording which functions ran.
+func gatedWorkflow(ran *[]string) *Workflow {
+	record := func(name string) StepFunc {
+		return func(ctx *Context) error {
+			*ran = append(*ran, name)
+			return nil
+		}
+	}
+	return &Workflow{
+		Name: "Gated",
+		Steps: []Step{
+			{Name: "Commit", Type: StepTypeFunc, Required: true, Func: record("Commit"), Undo: record("undo Commit")},
+			{Name: "Push", Ref: "push", Description: "Push to origin", Type: StepTypeFunc, Required: true, Irreversible: true,
+				Func: record("Push"),
+				Preview: func(ctx *Context) ([]actions.Proposal, error) {
+					return []actions.Proposal{{Description: "Push main", Metadata: map[string]string{"remote": "origin"}}}, nil
+				}},
+			{Name: "Announce", Type: StepTypeFunc, Required: false, Func: record("Announce")},
+		},
+	}
+}
+
+func TestRunnerRun_ApprovalProceed(t *testing.T) {
+	var ran []string
+	prompter := &approvalPrompter{choice: "proceed"}
+	runner := NewRunner()
+	runner.Interactive = true
+	runner.Prompter = prompter
+
+	result := runner.Run(gatedWorkflow(&ran), NewContext("/tmp", "v1.0.0"))
+
+	if !result.Success {
+		t.Fatalf("Workflow should succeed: %s", result.Output)
+	}
+	if strings.Join(ran, ",") != "Commit,Push,Announce" {
+		t.Errorf("ran = %v", ran)
+	}
+	if len(prompter.questions) != 1 || prompter.questions[0] != "approve_push" {
+		t.Errorf("questions = %v, want [approve_push]", prompter.questions)
+	}
+	if len(prompter.proposals) != 1 || prompter.proposals[0].Metadata["remote"] != "origin" {
+		t.Errorf("preview not shown: %+v", prompter.proposals)
+	}
+	if result.Steps[1].Approval != "proceed" {
+		t.Errorf("Approval = %q, want proceed", result.Steps[1].Approval)
+	}
+	if result.ToJSON().Steps[1].Approval != "proceed" {
+		t.Error("JSON result should record the approval")
+	}
+}
+
+func TestRunnerRun_ApprovalSkip(t *testing.T) {
+	var ran []string
+	runner := NewRunner()
+	runner.Interactive = true
+	runner.Prompter = &approvalPrompter{choice: "skip"}
+
+	result := runner.Run(gatedWorkflow(&ran), NewContext("/tmp", "v1.0.0"))
+
+	if !result.Success {
+		t.Fatalf("Workflow should succeed: %s", result.Output)
+	}
+	if strings.Join(ran, ",") != "Commit,Announce" {
+		t.Errorf("ran = %v, want Push skipped", ran)
+	}
+	if !result.Steps[1].Skipped || result.Steps[1].Approval != "skip" {
+		t.Errorf("Push result = %+v, want skipped at approval", result.Steps[1])
+	}
+}
+
+func TestRunnerRun_ApprovalAbort(t *testing.T) {
+	var ran []string
+	runner := NewRunner()
+	runner.Interactive = true
+	runner.Prompter = &approvalPrompter{choice: "abort"}
+
+	result := runner.Run(gatedWorkflow(&ran), NewContext("/tmp", "v1.0.0"))
+
+	if result.Success {
+		t.Fatal("Workflow should fail when aborted")
+	}
+	if strings.Join(ran, ",") != "Commit,undo Commit" {
+		t.Errorf("ran = %v, want Commit rolled back and nothing else run", ran)
+	}
+	if !errors.Is(result.Steps[1].Error, ErrAborted) {
+		t.Errorf("Push error = %v, want ErrAborted", result.Steps[1].Error)
+	}
+	if !strings.Contains(result.Summary(), "[approval: abort]") {
+		t.Errorf("Summary should show the approval:\n%s", result.Summary())
+	}
+}
+
+func TestRunnerRun_ApprovalNotInteractive(t *testing.T) {
+	var ran []string
+	prompter := &approvalPrompter{choice: "abort"}
+	runner := NewRunner()
+	runner.Prompter = prompter
+
+	result := runner.Run(gatedWorkflow(&ran), NewContext("/tmp", "v1.0.0"))
+
+	if !result.Success {
+		t.Fatalf("Workflow should succeed: %s", result.Output)
+	}
+	if len(prompter.questions) != 0 {
+		t.Errorf("non-interactive run should not prompt, got %v", prompter.questions)
+	}
+
+	// Dry runs make no changes, so there is nothing to approve
+	runner.Interactive = true
+	runner.DryRun = true
+	runner.Run(gatedWorkflow(&ran), NewContext("/tmp", "v1.0.0"))
+	if len(prompter.questions) != 0 {
+		t.Errorf("dry run should not prompt, got %v", prompter.questions)
+	}
+}
+
+func TestPreviewTag(t *testing.T) {
+	dir := initTestRepo(t)
+
+	proposals, err := previewTag(NewContext(dir, "v1.2.0"))
+	if err != nil {
+		t.Fatalf("previewTag() error: %v", err)
+	}
+	if len(proposals) != 1 {
+		t.Fatalf("expected 1 proposal, got %d", len(proposals))
+	}
+	meta := proposals[0].Metadata
+	if meta["ref"] != "refs/tags/v1.2.0" || meta["message"] != "Release v1.2.0" || meta["commit"] == "" {
+		t.Errorf("unexpected preview metadata: %v", meta)
+	}
+}
@@ -0,0 +1,144 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/agentplexus/agent-team-release/pkg/checks"
+)
+
+// Validation area step references.
+const (
+	StepPMValidation       = "pm-validation"
+	StepDocsValidation     = "docs-validation"
+	StepSecurityValidation = "security-validation"
+	StepReleaseValidation  = "release-validation"
+)
+
+// areaStatusPrefix marks Data keys recording the status of each validation
+// area a workflow checked, e.g. "area_status:Security" = "GO".
+const areaStatusPrefix = "area_status:"
+
+func init() {
+	RegisterStep(StepPMValidation, Step{
+		Name:        "PM validation",
+		Description: "Check version, release scope, changelog quality and roadmap alignment",
+		Type:        StepTypeFunc,
+		Required:    true,
+		Func:        runPMValidation,
+	})
+	RegisterStep(StepDocsValidation, Step{
+		Name:        "Documentation validation",
+		Description: "Check README, PRD, TRD, release notes and CHANGELOG",
+		Type:        StepTypeFunc,
+		Required:    true,
+		Func:        runDocsValidation,
+	})
+	RegisterStep(StepSecurityValidation, Step{
+		Name:        "Security validation",
+		Description: "Check LICENSE, vulnerabilities and dependencies",
+		Type:        StepTypeFunc,
+		Required:    true,
+		Func:        runSecurityValidation,
+	})
+	RegisterStep(StepReleaseValidation, Step{
+		Name:        "Release validation",
+		Description: "Check version availability, git status and CI configuration",
+		Type:        StepTypeFunc,
+		Required:    true,
+		Func:        runReleaseValidation,
+	})
+}
+
+// runPMValidation runs the PM area checks on the module being released.
+func runPMValidation(ctx *Context) error {
+	checker := &checks.PMChecker{}
+	return checkArea(ctx, checks.AreaPM, func() []checks.Result {
+		return checker.Check(moduleDir(ctx), checks.PMOptions{Version: ctx.Version, Verbose: ctx.Verbose})
+	})
+}
+
+// runDocsValidation runs the documentation area checks on the module being released.
+func runDocsValidation(ctx *Context) error {
+	checker := &checks.DocChecker{}
+	return checkArea(ctx, checks.AreaDocumentation, func() []checks.Result {
+		return checker.Check(moduleDir(ctx), checks.DocOptions{Version: ctx.Version, Verbose: ctx.Verbose})
+	})
+}
+
+// runSecurityValidation runs the security area checks on the module being released.
+func runSecurityValidation(ctx *Context) error {
+	checker := &checks.SecurityChecker{}
+	return checkArea(ctx, checks.AreaSecurity, func() []checks.Result {
+		return checker.Check(moduleDir(ctx), checks.SecurityOptions{Root: ctx.Dir, Verbose: ctx.Verbose, Context: ctx.context()})
+	})
+}
+
+// runReleaseValidation runs the release management area checks on the repository.
+func runReleaseValidation(ctx *Context) error {
+	checker := &checks.ReleaseChecker{}
+	return checkArea(ctx, checks.AreaRelease, func() []checks.Result {
+		return checker.Check(ctx.Dir, checks.ReleaseOptions{
+			Version:     ctx.Version,
+			Tag:         ReleaseTag(ctx),
+			ManifestDir: moduleDir(ctx),
+			BumpPending: ctx.DryRun || ctx.stepPending(StepBump), // Manifests not bumped yet
+			Verbose:     ctx.Verbose,
+			Context:     ctx.context(),
+		})
+	})
+}
+
+// checkArea runs an area's checks and fails the step if the area is NO-GO.
+func checkArea(ctx *Context, area checks.ValidationArea, run func() []checks.Result) error {
+	if ctx.SkipChecks {
+		ctx.Data[areaStatusPrefix+string(area)] = string(checks.StatusSkip)
+		ctx.Log("  Skipping %s checks (--skip-checks)", area)
+		return nil
+	}
+
+	results := run()
+	status := checks.ComputeAreaStatus(results)
+	ctx.Data[areaStatusPrefix+string(area)] = string(status)
+
+	for _, r := range results {
+		switch {
+		case r.Skipped:
+			if ctx.Verbose {
+				ctx.Log("    ⊘ %s: %s", r.Name, r.Reason)
+			}
+		case !r.Passed && r.Warning:
+			ctx.Log("    ⚠ %s: %s", r.Name, resultDetail(r))
+		case !r.Passed:
+			ctx.Log("    ✗ %s: %s", r.Name, resultDetail(r))
+		case ctx.Verbose:
+			ctx.Log("    ✓ %s", r.Name)
+		}
+	}
+
+	ctx.Log("  %s: %s %s", area, status.Icon(), status)
+	if status == checks.StatusNoGo {
+		return fmt.Errorf("%s validation is NO-GO", area)
+	}
+	return nil
+}
+
+// resultDetail returns the most useful description of a check result.
+func resultDetail(r checks.Result) string {
+	if r.Reason != "" {
+		return r.Reason
+	}
+	return r.Output
+}
+
+// AreaStatuses returns the validation area statuses recorded in a
+// workflow's data, keyed by area.
+func AreaStatuses(data map[string]string) map[checks.ValidationArea]checks.AreaStatus {
+	statuses := make(map[checks.ValidationArea]checks.AreaStatus)
+	for k, v := range data {
+		if area, ok := strings.CutPrefix(k, areaStatusPrefix); ok {
+			statuses[checks.ValidationArea(area)] = checks.AreaStatus(v)
+		}
+	}
+	return statuses
+}
@@ -0,0 +1,527 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/agentplexus/agent-team-release/pkg/actions"
+	"github.com/agentplexus/agent-team-release/pkg/changelog"
+	"github.com/agentplexus/agent-team-release/pkg/semver"
+)
+
+// Release-branch step references.
+const (
+	StepReleaseBranch  = "release-branch"
+	StepCherryPick     = "cherry-pick"
+	StepMergeChangelog = "merge-changelog"
+)
+
+func init() {
+	RegisterStep(StepReleaseBranch, Step{
+		Name:        "Prepare release branch",
+		Description: "Create or check out the release/X.Y branch",
+		Type:        StepTypeFunc,
+		Required:    true,
+		Func:        prepareReleaseBranch,
+		Undo:        undoReleaseBranch,
+	})
+	RegisterStep(StepCherryPick, Step{
+		Name:        "Cherry-pick commits",
+		Description: "Apply the selected commits to the release branch",
+		Type:        StepTypeFunc,
+		Required:    true,
+		Func:        cherryPickCommits,
+		Undo:        undoCherryPicks,
+	})
+	RegisterStep(StepMergeChangelog, Step{
+		Name:         "Merge changelog",
+		Description:  "Add the release's CHANGELOG.json entry to the default branch",
+		Type:         StepTypeFunc,
+		Required:     false,
+		Func:         mergeChangelog,
+		Irreversible: true,
+		Preview:      previewMergeChangelog,
+	})
+}
+
+// ReleaseBranchName returns the maintenance branch for a version's release
+// line, e.g. "release/1.4" for v1.4.3.
+func ReleaseBranchName(version string) (string, error) {
+	v, err := semver.Parse(version)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("release/%d.%d", v.Major, v.Minor), nil
+}
+
+// SetBranchRelease records the inputs of a release-branch workflow in ctx,
+// so they are checkpointed and restored on resume. base is the ref a new
+// branch starts from (empty = the latest tag of the release line, or the
+// default branch); picks are the commits
+// to cherry-pick onto the branch, in order.
+func SetBranchRelease(ctx *Context, base string, picks []string) error {
+	branch, err := ReleaseBranchName(ctx.Version)
+	if err != nil {
+		return err
+	}
+	ctx.Data["release_branch"] = branch
+	ctx.Data["release_base"] = base
+	ctx.Data["cherry_picks"] = strings.Join(picks, " ")
+	return nil
+}
+
+// IsBranchRelease reports whether a checkpoint belongs to a release-branch workflow.
+func IsBranchRelease(state *State) bool {
+	return state.Data["release_branch"] != ""
+}
+
+// ReleaseBranchWorkflow creates a workflow releasing a version from its
+// release/X.Y branch: the branch is created or reused, the selected commits
+// are cherry-picked and validated there, the tag is created on the branch,
+// and the changelog entry is added to the default branch.
+func ReleaseBranchWorkflow(version string) *Workflow {
+	name := "Release " + version
+	if branch, err := ReleaseBranchName(version); err == nil {
+		name += " on " + branch
+	}
+
+	return &Workflow{
+		Name:        name,
+		Description: "Release " + version + " from its maintenance branch",
+		Steps: []Step{
+			builtin(StepValidateVersion),
+			builtin(StepCheckWorkingDir),
+			builtin(StepReleaseBranch, "Validate version", "Check working directory"),
+			builtin(StepCherryPick, "Prepare release branch"),
+			builtin(StepBump, "Cherry-pick commits"),
+			builtin(StepValidate, "Bump versions"),
+			builtin(StepChangelog, "Run validation checks"),
+			builtin(StepReadme, "Run validation checks"),
+			builtin(StepCommit, "Generate changelog", "Update README"),
+			builtin(StepPush, "Create release commit"),
+			builtin(StepWaitCI, "Push to remote"),
+			builtin(StepTag, "Wait for CI"),
+			builtin(StepMergeChangelog, "Create tag"),
+		},
+	}
+}
+
+// prepareReleaseBranch checks out the release branch, reusing a local or
+// remote branch if there is one and otherwise creating it from the base.
+func prepareReleaseBranch(ctx *Context) error {
+	g := ctx.git()
+
+	branch := ctx.Data["release_branch"]
+	if branch == "" {
+		b, err := ReleaseBranchName(ctx.Version)
+		if err != nil {
+			return err
+		}
+		branch = b
+		ctx.Data["release_branch"] = branch
+	}
+
+	current, err := g.CurrentBranch()
+	if err != nil {
+		return err
+	}
+	if ctx.Data["original_branch"] == "" {
+		ctx.Data["original_branch"] = current
+	}
+	if current == branch {
+		ctx.Log("  Already on %s", branch)
+		return nil
+	}
+
+	// Refresh remote branches so an existing release line is reused
+	if err := g.Fetch(); err != nil {
+		ctx.Log("  Warning: fetch failed: %v", err)
+	}
+
+	local, err := g.BranchExists(branch)
+	if err != nil {
+		return err
+	}
+	remote, err := g.RemoteBranchExists(branch)
+	if err != nil {
+		return err
+	}
+
+	if ctx.DryRun {
+		// Lets push and tag previews describe the branch instead of the current one
+		ctx.Data["planned_branch"] = branch
+	}
+
+	switch {
+	case local:
+		if ctx.DryRun {
+			ctx.Log("  [Dry run] Would check out existing branch %s", branch)
+			return nil
+		}
+		if err := g.Checkout(branch); err != nil {
+			return err
+		}
+		if remote {
+			if err := g.FastForward(g.Remote + "/" + branch); err != nil {
+				return err
+			}
+		}
+		ctx.Log("  Checked out existing branch %s", branch)
+
+	case remote:
+		start := g.Remote + "/" + branch
+		if ctx.DryRun {
+			ctx.Log("  [Dry run] Would check out %s from %s", branch, start)
+			return nil
+		}
+		if err := createAndCheckout(ctx, branch, start); err != nil {
+			return err
+		}
+		ctx.Log("  Checked out %s from %s", branch, start)
+
+	default:
+		base := ctx.Data["release_base"]
+		if base == "" {
+			if base, err = defaultBase(ctx); err != nil {
+				return err
+			}
+		}
+		if _, err := g.ResolveCommit(base); err != nil {
+			return fmt.Errorf("invalid base: %w", err)
+		}
+		if ctx.DryRun {
+			ctx.Log("  [Dry run] Would create %s from %s", branch, base)
+			return nil
+		}
+		if err := createAndCheckout(ctx, branch, base); err != nil {
+			return err
+		}
+		ctx.Log("  Created %s from %s", branch, base)
+	}
+
+	return nil
+}
+
+// defaultBase returns the ref a new release branch starts from: the latest
+// earlier tag of the version's release line (e.g., v1.4.2 for v1.4.3), or
+// the default branch when the line has no tags yet.
+func defaultBase(ctx *Context) (string, error) {
+	tag, err := previousLineTag(ctx)
+	if err != nil || tag != "" {
+		return tag, err
+	}
+	return ctx.git().DefaultBranch()
+}
+
+// previousLineTag returns the latest tag of the version's release line that
+// precedes it, or "" if there is none.
+func previousLineTag(ctx *Context) (string, error) {
+	target, err := semver.Parse(ctx.Version)
+	if err != nil {
+		return "", err
+	}
+
+	tags, err := ctx.git().AllTags()
+	if err != nil {
+		return "", err
+	}
+	best := ""
+	var bestVersion semver.Version
+	for _, tag := range tags {
+		v, err := semver.Parse(tag)
+		if err != nil || v.Major != target.Major || v.Minor != target.Minor || v.Compare(target) >= 0 {
+			continue
+		}
+		if best == "" || v.Compare(bestVersion) > 0 {
+			best, bestVersion = tag, v
+		}
+	}
+	return best, nil
+}
+
+// createAndCheckout creates a branch at start and switches to it.
+func createAndCheckout(ctx *Context, branch, start string) error {
+	g := ctx.git()
+	if err := g.CreateBranch(branch, start); err != nil {
+		return err
+	}
+	ctx.Data["release_branch_created"] = "true"
+	return g.Checkout(branch)
+}
+
+// undoReleaseBranch returns to the original branch, deleting the release
+// branch if this run created it.
+func undoReleaseBranch(ctx *Context) error {
+	g := ctx.git()
+	branch := ctx.Data["release_branch"]
+
+	if original := ctx.Data["original_branch"]; original != "" && original != branch {
+		if err := g.Checkout(original); err != nil {
+			return err
+		}
+		ctx.Log("  Checked out %s", original)
+	}
+
+	if ctx.Data["release_branch_created"] != "" {
+		if err := g.DeleteBranch(branch); err != nil {
+			return err
+		}
+		delete(ctx.Data, "release_branch_created")
+		ctx.Log("  Deleted local branch %s", branch)
+	}
+	return nil
+}
+
+// cherryPickCommits applies the selected commits to the current branch,
+// skipping any it already contains.
+func cherryPickCommits(ctx *Context) error {
+	g := ctx.git()
+	// Recorded even without picks, so a rollback also discards later edits
+	if ctx.Data["cherry_pick_start"] == "" && !ctx.DryRun {
+		start, err := g.CurrentCommit()
+		if err != nil {
+			return err
+		}
+		ctx.Data["cherry_pick_start"] = start
+	}
+
+	picks := strings.Fields(ctx.Data["cherry_picks"])
+	if len(picks) == 0 {
+		ctx.Log("  No commits to cherry-pick")
+		return nil
+	}
+
+	for _, ref := range picks {
+		sha, err := g.ResolveCommit(ref)
+		if err != nil {
+			return err
+		}
+		desc := describeCommit(ctx, sha)
+
+		if ctx.DryRun {
+			ctx.Log("  [Dry run] Would cherry-pick %s", desc)
+			continue
+		}
+		if contained, err := g.IsAncestor(sha, "HEAD"); err == nil && contained {
+			ctx.Log("  Already contains %s", desc)
+			continue
+		}
+		if err := g.CherryPick(sha); err != nil {
+			return err
+		}
+		ctx.Log("  Cherry-picked %s", desc)
+	}
+	return nil
+}
+
+// describeCommit returns a commit's short hash and subject.
+func describeCommit(ctx *Context, sha string) string {
+	c, err := ctx.git().ShowCommit(sha)
+	if err != nil {
+		return shortSHA(sha)
+	}
+	return c.ShortHash() + " " + c.Subject
+}
+
+// undoCherryPicks drops the cherry-picked commits, and any uncommitted
+// changes made by later steps, if they were never pushed. undoPush keeps
+// pushed_commit after reverting, so a pushed branch is never reset.
+func undoCherryPicks(ctx *Context) error {
+	start := ctx.Data["cherry_pick_start"]
+	if start == "" {
+		ctx.Log("  Nothing to undo")
+		return nil
+	}
+	if ctx.Data["pushed_commit"] != "" {
+		ctx.Log("  Cherry-picks were pushed; leaving them on the branch")
+		return nil
+	}
+
+	if err := ctx.git().ResetHard(start); err != nil {
+		return err
+	}
+	delete(ctx.Data, "cherry_pick_start")
+	ctx.Log("  Reset release branch to %s", shortSHA(start))
+	return nil
+}
+
+// releaseEntry returns the version's CHANGELOG.json entry from the working
+// tree, or nil if the file or entry doesn't exist.
+func releaseEntry(ctx *Context) (*changelog.Release, error) {
+	cl, err := changelog.Load(filepath.Join(ctx.Dir, changelog.FileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cl.Release(ctx.Version), nil
+}
+
+// mergeTarget returns the branch the changelog entry is merged into, or ""
+// if the release is already on the default branch.
+func mergeTarget(ctx *Context) (string, error) {
+	target, err := ctx.git().DefaultBranch()
+	if err != nil {
+		return "", err
+	}
+	if target == ctx.Data["release_branch"] {
+		return "", nil
+	}
+	return target, nil
+}
+
+// changelogMergeMessage returns the message of the commit adding a release
+// branch's changelog entry to the default branch.
+func changelogMergeMessage(version, branch string) string {
+	return fmt.Sprintf("docs(changelog): add %s from %s", version, branch)
+}
+
+// mergeChangelog adds the release's CHANGELOG.json entry to the default
+// branch, regenerates CHANGELOG.md there, commits and pushes, then returns
+// to the branch the release started from.
+func mergeChangelog(ctx *Context) error {
+	g := ctx.git()
+	branch := ctx.Data["release_branch"]
+
+	if !ctx.DryRun {
+		defer returnToOriginalBranch(ctx)
+	}
+
+	target, err := mergeTarget(ctx)
+	if err != nil {
+		return err
+	}
+	if target == "" {
+		ctx.Log("  Released from the default branch; nothing to merge")
+		return nil
+	}
+
+	entry, err := releaseEntry(ctx)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		ctx.Log("  No %s entry for %s; nothing to merge", changelog.FileName, ctx.Version)
+		return nil
+	}
+
+	if ctx.DryRun {
+		ctx.Log("  [Dry run] Would add the %s changelog entry to %s", ctx.Version, target)
+		return nil
+	}
+
+	if err := g.Checkout(target); err != nil {
+		return err
+	}
+
+	if err := g.Fetch(); err != nil {
+		ctx.Log("  Warning: fetch failed: %v", err)
+	}
+	if ok, err := g.RemoteBranchExists(target); err == nil && ok {
+		if err := g.FastForward(g.Remote + "/" + target); err != nil {
+			return err
+		}
+	}
+
+	path := filepath.Join(ctx.Dir, changelog.FileName)
+	cl, err := changelog.Load(path)
+	if errors.Is(err, os.ErrNotExist) {
+		ctx.Log("  %s has no %s; nothing to merge", target, changelog.FileName)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = cl.InsertRelease(entry)
+	if errors.Is(err, changelog.ErrReleaseExists) {
+		ctx.Log("  %s already has the %s entry", target, ctx.Version)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := cl.Save(path); err != nil {
+		return err
+	}
+
+	if err := (&actions.ChangelogAction{}).Generate(ctx.Dir); err != nil {
+		ctx.Log("  Warning: failed to regenerate CHANGELOG.md: %v", err)
+	}
+
+	if err := g.CommitAll(changelogMergeMessage(ctx.Version, branch), false); err != nil {
+		return fmt.Errorf("failed to commit changelog: %w", err)
+	}
+	if err := g.Push(target); err != nil {
+		return err
+	}
+
+	ctx.Log("  Added the %s changelog entry to %s", ctx.Version, target)
+	return nil
+}
+
+// returnToOriginalBranch checks out the branch the release started from.
+func returnToOriginalBranch(ctx *Context) {
+	original := ctx.Data["original_branch"]
+	if original == "" {
+		return
+	}
+	g := ctx.git()
+	if current, err := g.CurrentBranch(); err == nil && current == original {
+		return
+	}
+	if err := g.Checkout(original); err != nil {
+		ctx.Log("  Warning: %v", err)
+		return
+	}
+	ctx.Log("  Checked out %s", original)
+}
+
+// previewMergeChangelog shows the CHANGELOG.json change on the default branch.
+func previewMergeChangelog(ctx *Context) ([]actions.Proposal, error) {
+	target, err := mergeTarget(ctx)
+	if err != nil || target == "" {
+		return nil, err
+	}
+
+	entry, err := releaseEntry(ctx)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+
+	old, err := ctx.git().ShowFile(target, changelog.FileName)
+	if err != nil {
+		return nil, err
+	}
+	cl, err := changelog.Parse([]byte(old))
+	if err != nil {
+		return nil, err
+	}
+	err = cl.InsertRelease(entry)
+	if errors.Is(err, changelog.ErrReleaseExists) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	updated, err := cl.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	message := changelogMergeMessage(ctx.Version, ctx.Data["release_branch"])
+	return []actions.Proposal{{
+		Description: fmt.Sprintf("Add the %s changelog entry to %s", ctx.Version, target),
+		FilePath:    changelog.FileName,
+		OldContent:  old,
+		NewContent:  string(updated),
+		Metadata: map[string]string{
+			"branch":  target,
+			"message": message,
+		},
+	}}, nil
+}
@@ -0,0 +1,289 @@
+package workflow
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/agent-team-release/pkg/changelog"
+)
+
+// gitRun runs a git command in dir and returns its trimmed output.
+func gitRun(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v: %s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// commitFile writes a file and commits it.
+func commitFile(t *testing.T, dir, name, content, message string) string {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, dir, "add", name)
+	gitRun(t, dir, "commit", "-m", message)
+	return gitRun(t, dir, "rev-parse", "HEAD")
+}
+
+const branchChangelog = `{
+  "project": "demo",
+  "releases": [
+    {
+      "version": "v1.0.0",
+      "date": "2026-01-01"
+    }
+  ]
+}
+`
+
+// initBranchRepo creates a repository with a bare remote and a v1.0.0
+// release, returning it and its default branch.
+func initBranchRepo(t *testing.T) (dir, mainBranch string) {
+	dir = initTestRepo(t)
+	remote := t.TempDir()
+	gitRun(t, remote, "init", "--bare")
+	gitRun(t, dir, "remote", "add", "origin", remote)
+
+	mainBranch = gitRun(t, dir, "rev-parse", "--abbrev-ref", "HEAD")
+	commitFile(t, dir, changelog.FileName, branchChangelog, "docs: changelog")
+	commitFile(t, dir, "app.txt", "v1\n", "feat: app")
+	gitRun(t, dir, "tag", "-a", "v1.0.0", "-m", "Release v1.0.0")
+	gitRun(t, dir, "push", "-u", "origin", mainBranch, "--tags")
+	return dir, mainBranch
+}
+
+func runBranchRelease(t *testing.T, dir, version, base string, picks ...string) *WorkflowResult {
+	t.Helper()
+	ctx := NewContext(dir, version)
+	ctx.SkipChecks = true
+	ctx.SkipCI = true
+	if err := SetBranchRelease(ctx, base, picks); err != nil {
+		t.Fatal(err)
+	}
+	return NewRunner().Run(ReleaseBranchWorkflow(version), ctx)
+}
+
+func TestReleaseBranchName(t *testing.T) {
+	if got, err := ReleaseBranchName("v1.4.3"); err != nil || got != "release/1.4" {
+		t.Errorf("ReleaseBranchName(v1.4.3) = %q, %v", got, err)
+	}
+	if _, err := ReleaseBranchName("next"); err == nil {
+		t.Error("ReleaseBranchName(next) should fail")
+	}
+}
+
+func TestDefaultBase(t *testing.T) {
+	dir, mainBranch := initBranchRepo(t)
+	gitRun(t, dir, "tag", "v1.0.1")
+	gitRun(t, dir, "tag", "v1.1.0")
+
+	tests := map[string]string{
+		"v1.0.2": "v1.0.1",
+		"v1.0.1": "v1.0.0",
+		"v1.2.0": mainBranch,
+	}
+	for version, want := range tests {
+		got, err := defaultBase(NewContext(dir, version))
+		if err != nil || got != want {
+			t.Errorf("defaultBase(%s) = %q, %v, want %q", version, got, err, want)
+		}
+	}
+}
+
+func TestReleaseBranchWorkflow_ReuseBranch(t *testing.T) {
+	dir, mainBranch := initBranchRepo(t)
+
+	// An existing release line with the v1.0.1 changelog entry
+	gitRun(t, dir, "checkout", "-b", "release/1.0", "v1.0.0")
+	entry := strings.Replace(branchChangelog, `"releases": [
+`, `"releases": [
+    {
+      "version": "v1.0.1",
+      "date": "2026-02-01"
+    },
+`, 1)
+	commitFile(t, dir, changelog.FileName, entry, "docs: v1.0.1 changelog")
+	gitRun(t, dir, "push", "-u", "origin", "release/1.0")
+	gitRun(t, dir, "checkout", mainBranch)
+
+	fix := commitFile(t, dir, "fix.txt", "fixed\n", "fix: crash")
+	commitFile(t, dir, "app.txt", "v2\n", "feat: next")
+	gitRun(t, dir, "push", "origin", mainBranch)
+
+	result := runBranchRelease(t, dir, "v1.0.1", "", fix)
+	if !result.Success {
+		t.Fatalf("release failed: %v\n%s", result.Error, result.Output)
+	}
+
+	// The tag is on the release branch, with the fix but not later work
+	if got := gitRun(t, dir, "rev-parse", "v1.0.1^{commit}"); got != gitRun(t, dir, "rev-parse", "release/1.0") {
+		t.Errorf("tag v1.0.1 is not on release/1.0")
+	}
+	if got := gitRun(t, dir, "show", "v1.0.1:fix.txt"); got != "fixed" {
+		t.Errorf("fix.txt at v1.0.1 = %q", got)
+	}
+	if got := gitRun(t, dir, "show", "v1.0.1:app.txt"); got != "v1" {
+		t.Errorf("app.txt at v1.0.1 = %q, want v1", got)
+	}
+	if !strings.Contains(gitRun(t, dir, "ls-remote", "--tags", "origin"), "refs/tags/v1.0.1") {
+		t.Error("tag v1.0.1 not pushed")
+	}
+
+	// The changelog entry is on the default branch, locally and on the remote
+	if got := gitRun(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); got != mainBranch {
+		t.Errorf("current branch = %s, want %s", got, mainBranch)
+	}
+	data := gitRun(t, dir, "show", "origin/"+mainBranch+":"+changelog.FileName)
+	if versions := changelogVersions(t, data); strings.Join(versions, ",") != "v1.0.1,v1.0.0" {
+		t.Errorf("default branch changelog versions = %v", versions)
+	}
+	if got := gitRun(t, dir, "log", "-1", "--format=%s", mainBranch); got != "docs(changelog): add v1.0.1 from release/1.0" {
+		t.Errorf("default branch head = %q", got)
+	}
+}
+
+func TestReleaseBranchWorkflow_NewBranch(t *testing.T) {
+	dir, mainBranch := initBranchRepo(t)
+	fix := commitFile(t, dir, "fix.txt", "fixed\n", "fix: crash")
+	commitFile(t, dir, "app.txt", "v2\n", "feat: next")
+
+	result := runBranchRelease(t, dir, "v1.0.1", "", fix)
+	if !result.Success {
+		t.Fatalf("release failed: %v\n%s", result.Error, result.Output)
+	}
+
+	// Branched from v1.0.0 with only the fix picked and the release commit
+	// for the regenerated CHANGELOG.md, and pushed with an upstream
+	if got := gitRun(t, dir, "log", "--format=%s", "v1.0.0..release/1.0"); got != "chore(release): v1.0.1\nfix: crash" {
+		t.Errorf("release/1.0 commits since v1.0.0 = %q", got)
+	}
+	if got := gitRun(t, dir, "rev-parse", "--abbrev-ref", "release/1.0@{upstream}"); got != "origin/release/1.0" {
+		t.Errorf("release/1.0 upstream = %q", got)
+	}
+	if got := gitRun(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); got != mainBranch {
+		t.Errorf("current branch = %s, want %s", got, mainBranch)
+	}
+}
+
+func TestReleaseBranchWorkflow_RollbackOnConflict(t *testing.T) {
+	dir, mainBranch := initBranchRepo(t)
+
+	commitFile(t, dir, "app.txt", "v2\n", "feat: v2")
+	conflicting := commitFile(t, dir, "app.txt", "v3\n", "feat: v3")
+
+	result := runBranchRelease(t, dir, "v1.0.1", "v1.0.0", conflicting)
+	if result.Success {
+		t.Fatal("expected the conflicting cherry-pick to fail the release")
+	}
+	if len(result.Rollback) == 0 {
+		t.Fatal("expected a rollback")
+	}
+
+	if got := gitRun(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); got != mainBranch {
+		t.Errorf("current branch = %s, want %s", got, mainBranch)
+	}
+	if out := gitRun(t, dir, "branch", "--list", "release/1.0"); out != "" {
+		t.Errorf("release branch not deleted: %q", out)
+	}
+	if out := gitRun(t, dir, "status", "--porcelain"); out != "" {
+		t.Errorf("working tree not clean: %q", out)
+	}
+}
+
+// pushedReleaseWorkflow commits and pushes a change to app.txt, then tags,
+// after the given leading steps.
+func pushedReleaseWorkflow(lead ...Step) *Workflow {
+	edit := Step{Name: "Edit app", Type: StepTypeFunc, Required: true, Func: func(ctx *Context) error {
+		return os.WriteFile(filepath.Join(ctx.Dir, "app.txt"), []byte("release\n"), 0644)
+	}}
+	if len(lead) > 0 {
+		edit.DependsOn = []string{lead[len(lead)-1].Name}
+	}
+	steps := append(lead, edit,
+		builtin(StepCommit, "Edit app"),
+		builtin(StepPush, "Create release commit"),
+		builtin(StepTag, "Push to remote"),
+	)
+	return &Workflow{Name: "Release", Steps: steps}
+}
+
+func TestRollback_PushedCommitWithFailingTag(t *testing.T) {
+	dir, mainBranch := initBranchRepo(t)
+	// The tag step fails since the tag already exists locally
+	gitRun(t, dir, "tag", "v1.1.0")
+
+	result := NewRunner().Run(pushedReleaseWorkflow(), NewContext(dir, "v1.1.0"))
+	if result.Success {
+		t.Fatal("expected the tag step to fail")
+	}
+
+	// The release commit stays pushed, reverted on top locally and remotely
+	if local, remote := gitRun(t, dir, "rev-parse", "HEAD"), gitRun(t, dir, "rev-parse", "origin/"+mainBranch); local != remote {
+		t.Errorf("HEAD = %s, origin/%s = %s", local, mainBranch, remote)
+	}
+	if got := gitRun(t, dir, "log", "-2", "--format=%s"); got != "Revert \"chore(release): v1.1.0\"\nchore(release): v1.1.0" {
+		t.Errorf("history = %q", got)
+	}
+	if got := gitRun(t, dir, "show", "HEAD:app.txt"); got != "v1" {
+		t.Errorf("app.txt = %q, want v1", got)
+	}
+	if out := gitRun(t, dir, "status", "--porcelain"); out != "" {
+		t.Errorf("working tree not clean: %q", out)
+	}
+}
+
+func TestRollback_PushedCherryPicksWithFailingTag(t *testing.T) {
+	dir, mainBranch := initBranchRepo(t)
+	gitRun(t, dir, "checkout", "-b", "release/1.0", "v1.0.0")
+	gitRun(t, dir, "push", "-u", "origin", "release/1.0")
+	gitRun(t, dir, "checkout", mainBranch)
+	fix := commitFile(t, dir, "fix.txt", "fixed\n", "fix: crash")
+	gitRun(t, dir, "tag", "v1.0.1")
+
+	ctx := NewContext(dir, "v1.0.1")
+	if err := SetBranchRelease(ctx, "", []string{fix}); err != nil {
+		t.Fatal(err)
+	}
+	wf := pushedReleaseWorkflow(builtin(StepReleaseBranch), builtin(StepCherryPick, "Prepare release branch"))
+	result := NewRunner().Run(wf, ctx)
+	if result.Success {
+		t.Fatal("expected the tag step to fail")
+	}
+
+	// The pushed cherry-pick and the reverted release commit stay on the branch
+	if local, remote := gitRun(t, dir, "rev-parse", "release/1.0"), gitRun(t, dir, "rev-parse", "origin/release/1.0"); local != remote {
+		t.Errorf("release/1.0 = %s, origin/release/1.0 = %s", local, remote)
+	}
+	if got := gitRun(t, dir, "show", "release/1.0:fix.txt"); got != "fixed" {
+		t.Errorf("fix.txt on release/1.0 = %q", got)
+	}
+	if got := gitRun(t, dir, "show", "release/1.0:app.txt"); got != "v1" {
+		t.Errorf("app.txt on release/1.0 = %q, want v1", got)
+	}
+	if got := gitRun(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); got != mainBranch {
+		t.Errorf("current branch = %s, want %s", got, mainBranch)
+	}
+}
+
+// changelogVersions parses a CHANGELOG.json document and returns the
+// versions of its release entries, in order.
+func changelogVersions(t *testing.T, data string) []string {
+	t.Helper()
+	cl, err := changelog.Parse([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var versions []string
+	for _, r := range cl.Releases {
+		versions = append(versions, r.Version)
+	}
+	return versions
+}
@@ -0,0 +1,310 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/agentplexus/agent-team-release/pkg/actions"
+	"github.com/agentplexus/agent-team-release/pkg/changelog"
+	"github.com/agentplexus/agent-team-release/pkg/git"
+	"github.com/agentplexus/agent-team-release/pkg/semver"
+)
+
+// Release candidate promotion step references.
+const (
+	StepValidatePromotion = "validate-promotion"
+	StepCandidateCI       = "candidate-ci"
+	StepPromoteChangelog  = "promote-changelog"
+)
+
+func init() {
+	RegisterStep(StepValidatePromotion, Step{
+		Name:        "Validate promotion",
+		Description: "Check the candidate exists and is the commit being released",
+		Type:        StepTypeFunc,
+		Required:    true,
+		Func:        validatePromotion,
+	})
+	RegisterStep(StepCandidateCI, Step{
+		Name:        "Check candidate CI",
+		Description: "Ensure CI passed on the candidate's commit",
+		Type:        StepTypeFunc,
+		Required:    true,
+		Func:        checkCandidateCI,
+	})
+	RegisterStep(StepPromoteChangelog, Step{
+		Name:        "Fold candidate changelogs",
+		Description: "Replace the candidates' CHANGELOG.json entries with the release entry",
+		Type:        StepTypeFunc,
+		Required:    false,
+		Func:        promoteChangelog,
+		Preview:     previewPromoteChangelog,
+	})
+}
+
+// NextCandidate returns the next release candidate for release, e.g.
+// v1.2.0-rc.3 when v1.2.0-rc.2 is the latest. With an empty release, it
+// continues the newest candidate series that hasn't been released yet.
+// With a module, only the module's tags are considered (see ModuleTag).
+func NextCandidate(dir, module, release string) (string, error) {
+	allTags, err := git.New(dir).AllTags()
+	if err != nil {
+		return "", err
+	}
+	tags := moduleVersions(allTags, module)
+
+	var target semver.Version
+	if release == "" {
+		found := false
+		for _, tag := range tags {
+			v, err := semver.Parse(tag)
+			if _, ok := v.Candidate(); err != nil || !ok || containsVersion(tags, v.Release().String()) {
+				continue
+			}
+			if !found || v.Compare(target) > 0 {
+				target, found = v, true
+			}
+		}
+		if !found {
+			return "", errors.New("no unreleased candidate series; specify the version to release")
+		}
+	} else if target, err = semver.Parse(release); err != nil {
+		return "", err
+	}
+
+	if final := target.Release().String(); containsVersion(tags, final) {
+		return "", fmt.Errorf("%s is already released", final)
+	}
+	return semver.NextCandidate(tags, target).String(), nil
+}
+
+// containsVersion reports whether tags include version.
+func containsVersion(tags []string, version string) bool {
+	for _, tag := range tags {
+		if sameVersion(tag, version) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameVersion reports whether two version strings name the same version,
+// ignoring a leading "v".
+func sameVersion(a, b string) bool {
+	return strings.TrimPrefix(a, "v") == strings.TrimPrefix(b, "v")
+}
+
+// SetPromotion prepares ctx to promote a release candidate such as
+// v1.2.0-rc.3: ctx.Version becomes the release (v1.2.0), and the candidate
+// is recorded so it is checkpointed and restored on resume.
+func SetPromotion(ctx *Context, candidate string) error {
+	v, err := semver.Parse(candidate)
+	if err != nil {
+		return err
+	}
+	if _, ok := v.Candidate(); !ok {
+		return fmt.Errorf("%s is not a release candidate (vX.Y.Z-rc.N)", candidate)
+	}
+	ctx.Version = v.Release().String()
+	ctx.Data["promote_from"] = candidate
+	return nil
+}
+
+// IsPromotion reports whether a checkpoint belongs to a promotion workflow.
+func IsPromotion(state *State) bool {
+	return state.Data["promote_from"] != ""
+}
+
+// PromoteWorkflow creates a workflow promoting a release candidate to
+// version: the candidate's commit is tagged as version once its CI has
+// passed, and the candidates' changelog entries are folded into the
+// release entry, committed and pushed.
+func PromoteWorkflow(version string) *Workflow {
+	return &Workflow{
+		Name:        "Promote " + version,
+		Description: "Promote a release candidate to " + version,
+		Steps: []Step{
+			builtin(StepValidatePromotion),
+			builtin(StepCheckWorkingDir),
+			builtin(StepCandidateCI, "Validate promotion"),
+			builtin(StepTag, "Validate promotion", "Check working directory", "Check candidate CI"),
+			builtin(StepPromoteChangelog, "Create tag"),
+			builtin(StepCommit, "Fold candidate changelogs"),
+			builtin(StepPush, "Create release commit"),
+		},
+	}
+}
+
+// validatePromotion checks that the candidate exists, the release doesn't,
+// and HEAD is the candidate's commit on a branch.
+func validatePromotion(ctx *Context) error {
+	g := ctx.git()
+	candidate := ctx.Data["promote_from"]
+	if candidate == "" {
+		return errors.New("no release candidate to promote")
+	}
+
+	tags, err := g.AllTags()
+	if err != nil {
+		return err
+	}
+	if containsVersion(tags, ctx.Version) {
+		return fmt.Errorf("tag %s already exists", ctx.Version)
+	}
+
+	commit, err := g.ResolveCommit(candidate)
+	if err != nil {
+		return fmt.Errorf("release candidate %s not found: %w", candidate, err)
+	}
+	head, err := g.CurrentCommit()
+	if err != nil {
+		return err
+	}
+	if head != commit {
+		return fmt.Errorf("HEAD (%s) differs from %s (%s); check out the candidate's commit to promote it",
+			shortSHA(head), candidate, shortSHA(commit))
+	}
+	if branch, err := g.CurrentBranch(); err != nil || branch == "HEAD" {
+		return errors.New("HEAD is detached; check out the branch the candidate was released from")
+	}
+	ctx.Data["candidate_commit"] = commit
+
+	if latest := latestCandidate(tags, ctx.Version); latest != "" && !sameVersion(latest, candidate) {
+		ctx.Log("  Warning: %s is newer than %s", latest, candidate)
+	}
+
+	ctx.Log("  Promoting %s (%s) to %s", candidate, shortSHA(commit), ctx.Version)
+	return nil
+}
+
+// latestCandidate returns the highest release candidate tag of version.
+func latestCandidate(tags []string, version string) string {
+	target, err := semver.Parse(version)
+	if err != nil {
+		return ""
+	}
+	latest := ""
+	for _, tag := range tags {
+		v, err := semver.Parse(tag)
+		if _, ok := v.Candidate(); err != nil || !ok || v.Release() != target.Release() {
+			continue
+		}
+		if latest == "" || semver.Compare(tag, latest) > 0 {
+			latest = tag
+		}
+	}
+	return latest
+}
+
+// checkCandidateCI refuses the promotion unless CI passed on the
+// candidate's commit.
+func checkCandidateCI(ctx *Context) error {
+	candidate := ctx.Data["promote_from"]
+	if ctx.SkipCI {
+		ctx.Log("  Skipping CI check on %s (--skip-ci)", candidate)
+		return nil
+	}
+	if !commandExists("gh") {
+		return fmt.Errorf("gh CLI not found; cannot verify CI on %s (use --skip-ci to promote anyway)", candidate)
+	}
+
+	status, err := ctx.git().GetCIStatus(ctx.Data["candidate_commit"])
+	if err != nil {
+		return err
+	}
+	if status.State != "success" {
+		for _, s := range status.Statuses {
+			if s.State != "success" {
+				ctx.Log("    ✗ %s: %s", s.Context, s.State)
+			}
+		}
+		return fmt.Errorf("CI on %s is %s, not green", candidate, status.State)
+	}
+
+	ctx.Log("  CI passed on %s", candidate)
+	return nil
+}
+
+// promotedChangelog returns CHANGELOG.json's content, and the changelog
+// with the candidates' entries folded into the release entry, and the
+// folded versions. It returns a nil changelog if the file doesn't exist.
+func promotedChangelog(ctx *Context) (old []byte, cl *changelog.Changelog, folded []string, err error) {
+	old, err = os.ReadFile(filepath.Join(ctx.Dir, changelog.FileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	cl, err = changelog.Parse(old)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	folded, err = cl.FoldPrereleases(ctx.Version, time.Now().Format(changelog.DateFormat))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return old, cl, folded, nil
+}
+
+// promoteChangelog folds the candidates' CHANGELOG.json entries into the
+// release entry and regenerates CHANGELOG.md.
+func promoteChangelog(ctx *Context) error {
+	_, cl, folded, err := promotedChangelog(ctx)
+	if errors.Is(err, changelog.ErrReleaseExists) {
+		ctx.Log("  %s already has a %s entry", changelog.FileName, ctx.Version)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if cl == nil {
+		ctx.Log("  No %s found, skipping", changelog.FileName)
+		return nil
+	}
+	if len(folded) == 0 {
+		ctx.Log("  No release candidate entries in %s", changelog.FileName)
+		return nil
+	}
+
+	if ctx.DryRun {
+		ctx.Log("  [Dry run] Would fold %s into %s", strings.Join(folded, ", "), ctx.Version)
+		return nil
+	}
+
+	if err := cl.Save(filepath.Join(ctx.Dir, changelog.FileName)); err != nil {
+		return err
+	}
+	if err := (&actions.ChangelogAction{}).Generate(ctx.Dir); err != nil {
+		ctx.Log("  Warning: failed to regenerate CHANGELOG.md: %v", err)
+	}
+
+	ctx.Log("  Folded %s into %s", strings.Join(folded, ", "), ctx.Version)
+	return nil
+}
+
+// previewPromoteChangelog shows the CHANGELOG.json change.
+func previewPromoteChangelog(ctx *Context) ([]actions.Proposal, error) {
+	old, cl, folded, err := promotedChangelog(ctx)
+	if errors.Is(err, changelog.ErrReleaseExists) {
+		return nil, nil
+	}
+	if err != nil || len(folded) == 0 {
+		return nil, err
+	}
+	updated, err := cl.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	return []actions.Proposal{{
+		Description: fmt.Sprintf("Fold %s into %s", strings.Join(folded, ", "), ctx.Version),
+		FilePath:    changelog.FileName,
+		OldContent:  string(old),
+		NewContent:  string(updated),
+	}}, nil
+}
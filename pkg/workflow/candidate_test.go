@@ -0,0 +1,149 @@
+package workflow
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/agent-team-release/pkg/changelog"
+)
+
+const candidateChangelog = `{
+  "project": "demo",
+  "releases": [
+    {
+      "version": "v1.1.0-rc.2",
+      "date": "2026-02-10",
+      "fixed": [
+        { "description": "Fix from rc.2", "commit": "bbb2222" }
+      ]
+    },
+    {
+      "version": "v1.1.0-rc.1",
+      "date": "2026-02-01",
+      "added": [
+        { "description": "Feature from rc.1", "commit": "aaa1111" }
+      ]
+    },
+    {
+      "version": "v1.0.0",
+      "date": "2026-01-01"
+    }
+  ]
+}
+`
+
+// initCandidateRepo creates a repository with a v1.0.0 release and two
+// v1.1.0 candidates, the second at HEAD.
+func initCandidateRepo(t *testing.T) (dir, mainBranch string) {
+	dir, mainBranch = initBranchRepo(t)
+	commitFile(t, dir, "app.txt", "v1.1-rc1\n", "feat: next")
+	gitRun(t, dir, "tag", "-a", "v1.1.0-rc.1", "-m", "Release v1.1.0-rc.1")
+	commitFile(t, dir, changelog.FileName, candidateChangelog, "chore(release): v1.1.0-rc.2")
+	gitRun(t, dir, "tag", "-a", "v1.1.0-rc.2", "-m", "Release v1.1.0-rc.2")
+	gitRun(t, dir, "push", "origin", mainBranch, "--tags")
+	return dir, mainBranch
+}
+
+func runPromotion(t *testing.T, dir, candidate string) *WorkflowResult {
+	t.Helper()
+	ctx := NewContext(dir, "")
+	ctx.SkipCI = true
+	if err := SetPromotion(ctx, candidate); err != nil {
+		t.Fatal(err)
+	}
+	return NewRunner().Run(PromoteWorkflow(ctx.Version), ctx)
+}
+
+func TestNextCandidate(t *testing.T) {
+	dir, _ := initCandidateRepo(t)
+
+	for release, want := range map[string]string{
+		"v1.1.0": "v1.1.0-rc.3",
+		"":       "v1.1.0-rc.3",
+		"1.2.0":  "v1.2.0-rc.1",
+	} {
+		if got, err := NextCandidate(dir, "", release); err != nil || got != want {
+			t.Errorf("NextCandidate(%q) = %q, %v, want %q", release, got, err, want)
+		}
+	}
+	if _, err := NextCandidate(dir, "", "v1.0.0"); err == nil {
+		t.Error("NextCandidate(v1.0.0) should fail for a released version")
+	}
+
+	gitRun(t, dir, "tag", "v1.1.0")
+	if _, err := NextCandidate(dir, "", ""); err == nil {
+		t.Error("NextCandidate() should fail without an unreleased series")
+	}
+}
+
+func TestSetPromotion(t *testing.T) {
+	ctx := NewContext(".", "")
+	if err := SetPromotion(ctx, "v1.2.0-rc.3"); err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Version != "v1.2.0" || !IsPromotion(&State{Data: ctx.Data}) {
+		t.Errorf("SetPromotion() version = %s, data = %v", ctx.Version, ctx.Data)
+	}
+
+	for _, candidate := range []string{"v1.2.0", "v1.2.0-beta.1", "next"} {
+		if err := SetPromotion(NewContext(".", ""), candidate); err == nil {
+			t.Errorf("SetPromotion(%s) should fail", candidate)
+		}
+	}
+}
+
+func TestPromoteWorkflow(t *testing.T) {
+	dir, mainBranch := initCandidateRepo(t)
+
+	result := runPromotion(t, dir, "v1.1.0-rc.2")
+	if !result.Success {
+		t.Fatalf("promotion failed: %v\n%s", result.Error, result.Output)
+	}
+
+	// The release is the candidate's commit
+	if got, want := gitRun(t, dir, "rev-parse", "v1.1.0^{commit}"), gitRun(t, dir, "rev-parse", "v1.1.0-rc.2^{commit}"); got != want {
+		t.Errorf("v1.1.0 = %s, want the candidate's commit %s", got, want)
+	}
+	if gitRun(t, dir, "ls-remote", "--tags", "origin", "v1.1.0") == "" {
+		t.Error("tag v1.1.0 not pushed")
+	}
+
+	// The candidates' entries are folded into the release entry and pushed
+	data := gitRun(t, dir, "show", "origin/"+mainBranch+":"+changelog.FileName)
+	if versions := changelogVersions(t, data); strings.Join(versions, ",") != "v1.1.0,v1.0.0" {
+		t.Errorf("changelog versions = %v", versions)
+	}
+	cl, err := changelog.Parse([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	release := cl.Release("v1.1.0")
+	var descriptions []string
+	for _, c := range release.Categories() {
+		for _, e := range release.Entries(c) {
+			descriptions = append(descriptions, e.Description)
+		}
+	}
+	for _, want := range []string{"Feature from rc.1", "Fix from rc.2"} {
+		if !slices.Contains(descriptions, want) {
+			t.Errorf("release entry is missing %q: %v", want, descriptions)
+		}
+	}
+}
+
+func TestPromoteWorkflow_CommitDiffers(t *testing.T) {
+	dir, _ := initCandidateRepo(t)
+	commitFile(t, dir, "app.txt", "untested\n", "feat: after the candidate")
+
+	result := runPromotion(t, dir, "v1.1.0-rc.2")
+	if result.Success {
+		t.Fatal("expected the promotion to be refused")
+	}
+	if !strings.Contains(result.Output, "differs from v1.1.0-rc.2") {
+		t.Errorf("output does not explain the refusal:\n%s", result.Output)
+	}
+	if out := gitRun(t, dir, "tag", "--list", "v1.1.0"); out != "" {
+		t.Errorf("tag created: %q", out)
+	}
+}
@@ -0,0 +1,232 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/agentplexus/agent-team-release/pkg/config"
+	"github.com/agentplexus/agent-team-release/pkg/proc"
+)
+
+// FromConfig builds a workflow from a custom workflow definition in .releaseagent.yaml.
+// Steps reference built-in implementations via "uses" or run shell commands via "run".
+func FromConfig(name string, wc config.WorkflowConfig) (*Workflow, error) {
+	if len(wc.Steps) == 0 {
+		return nil, fmt.Errorf("workflow %q has no steps", name)
+	}
+
+	w := &Workflow{
+		Name:        name,
+		Description: wc.Description,
+	}
+
+	for i, sc := range wc.Steps {
+		step, err := stepFromConfig(sc)
+		if err != nil {
+			return nil, fmt.Errorf("workflow %q step %d: %w", name, i+1, err)
+		}
+		w.Steps = append(w.Steps, step)
+	}
+
+	// Validate dependencies up front so configuration errors surface before running
+	if _, err := buildGraph(w.Steps); err != nil {
+		return nil, fmt.Errorf("workflow %q: %w", name, err)
+	}
+
+	return w, nil
+}
+
+// stepFromConfig converts a single step definition.
+func stepFromConfig(sc config.StepConfig) (Step, error) {
+	switch {
+	case sc.Uses != "" && sc.Run != "":
+		return Step{}, errors.New("only one of uses or run may be set")
+	case sc.Uses != "":
+		step, ok := LookupStep(sc.Uses)
+		if !ok {
+			return Step{}, fmt.Errorf("unknown step %q (available: %s)", sc.Uses, strings.Join(StepRefs(), ", "))
+		}
+		if sc.Name != "" {
+			step.Name = sc.Name
+		}
+		if sc.Required != nil {
+			step.Required = *sc.Required
+		}
+		if sc.Timeout != "" {
+			d, err := time.ParseDuration(sc.Timeout)
+			if err != nil {
+				return Step{}, fmt.Errorf("invalid timeout %q: %w", sc.Timeout, err)
+			}
+			step.Timeout = d
+		}
+		if sc.Irreversible {
+			step.Irreversible = true
+		}
+		if sc.Retry != nil {
+			policy, err := retryFromConfig(*sc.Retry)
+			if err != nil {
+				return Step{}, err
+			}
+			// Keep the built-in notion of which errors are worth retrying
+			if step.Retry != nil {
+				policy.Retryable = step.Retry.Retryable
+			}
+			step.Retry = policy
+		}
+		step.DependsOn = sc.DependsOn
+		return step, nil
+	case sc.Run != "":
+		return CommandStep(sc)
+	default:
+		return Step{}, errors.New("one of uses or run is required")
+	}
+}
+
+// CommandStep creates a step that runs a shell command.
+// The command receives ATRELEASE_VERSION, ATRELEASE_DIR and ATRELEASE_DRY_RUN
+// in its environment in addition to the configured variables.
+func CommandStep(sc config.StepConfig) (Step, error) {
+	var timeout time.Duration
+	if sc.Timeout != "" {
+		d, err := time.ParseDuration(sc.Timeout)
+		if err != nil {
+			return Step{}, fmt.Errorf("invalid timeout %q: %w", sc.Timeout, err)
+		}
+		timeout = d
+	}
+
+	name := sc.Name
+	if name == "" {
+		name = sc.Run
+	}
+
+	required := true
+	if sc.Required != nil {
+		required = *sc.Required
+	}
+
+	var retry *RetryPolicy
+	if sc.Retry != nil {
+		policy, err := retryFromConfig(*sc.Retry)
+		if err != nil {
+			return Step{}, err
+		}
+		retry = policy
+	}
+
+	return Step{
+		Name:         name,
+		Description:  "Run: " + sc.Run,
+		Type:         StepTypeFunc,
+		Required:     required,
+		DependsOn:    sc.DependsOn,
+		Irreversible: sc.Irreversible,
+		Retry:        retry,
+		Func: func(ctx *Context) error {
+			return runShell(ctx, sc, timeout)
+		},
+	}, nil
+}
+
+// retryFromConfig converts a retry definition into a policy.
+func retryFromConfig(rc config.RetryConfig) (*RetryPolicy, error) {
+	if rc.Attempts < 1 {
+		return nil, fmt.Errorf("invalid retry attempts %d: must be at least 1", rc.Attempts)
+	}
+	if rc.Jitter < 0 || rc.Jitter > 1 {
+		return nil, fmt.Errorf("invalid retry jitter %v: must be between 0 and 1", rc.Jitter)
+	}
+
+	policy := &RetryPolicy{
+		MaxAttempts:  rc.Attempts,
+		InitialDelay: time.Second,
+		Jitter:       rc.Jitter,
+	}
+	if rc.Delay != "" {
+		d, err := time.ParseDuration(rc.Delay)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retry delay %q: %w", rc.Delay, err)
+		}
+		policy.InitialDelay = d
+	}
+	if rc.MaxDelay != "" {
+		d, err := time.ParseDuration(rc.MaxDelay)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retry max_delay %q: %w", rc.MaxDelay, err)
+		}
+		policy.MaxDelay = d
+	}
+	return policy, nil
+}
+
+// runShell executes a command step's shell command.
+func runShell(ctx *Context, sc config.StepConfig, timeout time.Duration) error {
+	if ctx.DryRun {
+		ctx.Log("  [Dry run] Would run: %s", sc.Run)
+		return nil
+	}
+
+	dir := ctx.Dir
+	if sc.Dir != "" {
+		dir = filepath.Join(ctx.Dir, sc.Dir)
+	}
+
+	env := []string{
+		"ATRELEASE_VERSION=" + ctx.Version,
+		"ATRELEASE_DIR=" + ctx.Dir,
+		fmt.Sprintf("ATRELEASE_DRY_RUN=%t", ctx.DryRun),
+	}
+	for k, v := range sc.Env {
+		env = append(env, k+"="+v)
+	}
+
+	return execShell(ctx, sc.Run, dir, env, nil, timeout)
+}
+
+// execShell runs a command with sh -c, appending env to the process
+// environment and feeding stdin if given. Output is logged indented when
+// verbose or when the command fails.
+func execShell(ctx *Context, command, dir string, env []string, stdin []byte, timeout time.Duration) error {
+	runCtx := ctx.context()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(runCtx, timeout)
+		defer cancel()
+	}
+
+	cmd := proc.Command(runCtx, "sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	err := cmd.Run()
+	output := strings.TrimSpace(out.String())
+	if output != "" && (ctx.Verbose || err != nil) {
+		for _, line := range strings.Split(output, "\n") {
+			ctx.Log("    %s", line)
+		}
+	}
+
+	if err := ctx.context().Err(); err != nil {
+		return fmt.Errorf("command interrupted: %w", err)
+	}
+	if runCtx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("command timed out after %s", timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("command failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/agent-team-release/pkg/config"
+)
+
+func TestFromConfig(t *testing.T) {
+	notRequired := false
+	wc := config.WorkflowConfig{
+		Description: "Custom release",
+		Steps: []config.StepConfig{
+			{Uses: StepValidateVersion},
+			{Uses: StepChangelog, Name: "Changelog", Required: &notRequired},
+			{Name: "Smoke", Run: "true", DependsOn: []string{"Validate version"}},
+		},
+	}
+
+	wf, err := FromConfig("custom", wc)
+	if err != nil {
+		t.Fatalf("FromConfig() error: %v", err)
+	}
+	if len(wf.Steps) != 3 {
+		t.Fatalf("Steps = %d, want 3", len(wf.Steps))
+	}
+	if wf.Steps[0].Name != "Validate version" || !wf.Steps[0].Required {
+		t.Errorf("built-in step not resolved: %+v", wf.Steps[0])
+	}
+	if wf.Steps[1].Name != "Changelog" || wf.Steps[1].Required {
+		t.Errorf("overrides not applied: %+v", wf.Steps[1])
+	}
+	if !wf.Steps[2].Required {
+		t.Error("command steps should be required by default")
+	}
+}
+
+func TestFromConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		wc   config.WorkflowConfig
+		want string
+	}{
+		{"no steps", config.WorkflowConfig{}, "no steps"},
+		{"unknown builtin", config.WorkflowConfig{Steps: []config.StepConfig{{Uses: "nope"}}}, "unknown step"},
+		{"uses and run", config.WorkflowConfig{Steps: []config.StepConfig{{Uses: StepTag, Run: "true"}}}, "only one of"},
+		{"neither", config.WorkflowConfig{Steps: []config.StepConfig{{Name: "empty"}}}, "required"},
+		{"bad timeout", config.WorkflowConfig{Steps: []config.StepConfig{{Run: "true", Timeout: "soon"}}}, "invalid timeout"},
+		{"bad retry attempts", config.WorkflowConfig{Steps: []config.StepConfig{{Run: "true", Retry: &config.RetryConfig{}}}}, "invalid retry attempts"},
+		{"bad retry delay", config.WorkflowConfig{Steps: []config.StepConfig{{Uses: StepPush, Retry: &config.RetryConfig{Attempts: 2, Delay: "soon"}}}}, "invalid retry delay"},
+		{"bad retry jitter", config.WorkflowConfig{Steps: []config.StepConfig{{Run: "true", Retry: &config.RetryConfig{Attempts: 2, Jitter: 2}}}}, "invalid retry jitter"},
+		{"bad dependency", config.WorkflowConfig{Steps: []config.StepConfig{{Run: "true", DependsOn: []string{"x"}}}}, "unknown step"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromConfig("wf", tt.wc)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("FromConfig() error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandStep(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	step, err := CommandStep(config.StepConfig{
+		Name: "Write file",
+		Run:  `echo "$ATRELEASE_VERSION $GREETING" > out.txt`,
+		Dir:  "sub",
+		Env:  map[string]string{"GREETING": "hello"},
+	})
+	if err != nil {
+		t.Fatalf("CommandStep() error: %v", err)
+	}
+
+	if err := step.Func(NewContext(dir, "v1.2.3")); err != nil {
+		t.Fatalf("step failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "sub", "out.txt"))
+	if err != nil {
+		t.Fatalf("output file not written: %v", err)
+	}
+	if strings.TrimSpace(string(data)) != "v1.2.3 hello" {
+		t.Errorf("output = %q, want %q", data, "v1.2.3 hello")
+	}
+}
+
+func TestCommandStep_Timeout(t *testing.T) {
+	step, err := CommandStep(config.StepConfig{Run: "sleep 5", Timeout: "50ms"})
+	if err != nil {
+		t.Fatalf("CommandStep() error: %v", err)
+	}
+
+	err = step.Func(NewContext(t.TempDir(), "v1.0.0"))
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+}
+
+func TestCommandStep_DryRun(t *testing.T) {
+	step, _ := CommandStep(config.StepConfig{Run: "exit 1"})
+
+	ctx := NewContext(t.TempDir(), "v1.0.0")
+	ctx.DryRun = true
+	if err := step.Func(ctx); err != nil {
+		t.Errorf("dry run should not execute the command: %v", err)
+	}
+}
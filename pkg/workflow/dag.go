@@ -0,0 +1,313 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultMaxWorkers is the default number of steps run concurrently.
+const DefaultMaxWorkers = 4
+
+// graph holds the dependency structure of a workflow's steps.
+type graph struct {
+	deps       [][]int // deps[i] lists the steps that step i depends on
+	dependents [][]int // dependents[i] lists the steps that depend on step i
+}
+
+// buildGraph resolves step dependencies into a graph.
+// If no step declares DependsOn, each step depends on the previous one so the
+// workflow runs sequentially in definition order.
+func buildGraph(steps []Step) (*graph, error) {
+	n := len(steps)
+	g := &graph{
+		deps:       make([][]int, n),
+		dependents: make([][]int, n),
+	}
+
+	hasDeps := false
+	for _, s := range steps {
+		if len(s.DependsOn) > 0 {
+			hasDeps = true
+			break
+		}
+	}
+
+	if !hasDeps {
+		for i := 1; i < n; i++ {
+			g.addEdge(i-1, i)
+		}
+		return g, nil
+	}
+
+	index := make(map[string]int, n)
+	for i, s := range steps {
+		if _, dup := index[s.Name]; dup {
+			return nil, fmt.Errorf("duplicate step name %q", s.Name)
+		}
+		index[s.Name] = i
+	}
+
+	for i, s := range steps {
+		for _, dep := range s.DependsOn {
+			j, ok := index[dep]
+			if !ok {
+				return nil, fmt.Errorf("step %q depends on unknown step %q", s.Name, dep)
+			}
+			if j == i {
+				return nil, fmt.Errorf("step %q depends on itself", s.Name)
+			}
+			g.addEdge(j, i)
+		}
+	}
+
+	if cycle := g.findCycle(); cycle != nil {
+		names := make([]string, len(cycle))
+		for i, idx := range cycle {
+			names[i] = steps[idx].Name
+		}
+		return nil, fmt.Errorf("dependency cycle: %s", strings.Join(names, " → "))
+	}
+
+	return g, nil
+}
+
+// addEdge records that step to depends on step from.
+func (g *graph) addEdge(from, to int) {
+	g.deps[to] = append(g.deps[to], from)
+	g.dependents[from] = append(g.dependents[from], to)
+}
+
+// findCycle returns the steps forming a dependency cycle, or nil if there is none.
+func (g *graph) findCycle() []int {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	color := make([]int, len(g.deps))
+	var stack []int
+
+	var visit func(i int) []int
+	visit = func(i int) []int {
+		color[i] = visiting
+		stack = append(stack, i)
+		for _, next := range g.dependents[i] {
+			switch color[next] {
+			case visiting:
+				// Cycle found: slice the stack from the first occurrence of next
+				for k, idx := range stack {
+					if idx == next {
+						cycle := append([]int{}, stack[k:]...)
+						return append(cycle, next)
+					}
+				}
+			case unvisited:
+				if cycle := visit(next); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		color[i] = visited
+		return nil
+	}
+
+	for i := range g.deps {
+		if color[i] == unvisited {
+			if cycle := visit(i); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// stepOutcome is sent by a worker when a step finishes.
+type stepOutcome struct {
+	index       int
+	result      StepResult
+	ctx         *Context
+	baseData    map[string]string
+	baseVersion string
+}
+
+// execution tracks the progress of a workflow run through its graph.
+type execution struct {
+	results   []*StepResult
+	outputs   []string
+	flushed   int     // Number of leading steps whose output was written
+	executed  []*Step // Steps that ran (or were resumed), in completion order
+	failed    *Step   // First required step that failed
+	cancelled bool    // The context was cancelled; no further steps start
+}
+
+// execute runs the workflow's steps, starting each step once its dependencies
+// have finished and running up to MaxWorkers steps concurrently.
+// Step output is written to ctx grouped per step, in definition order.
+func (r *Runner) execute(w *Workflow, g *graph, ctx *Context, state *State) *execution {
+	n := len(w.Steps)
+	ex := &execution{
+		results: make([]*StepResult, n),
+		outputs: make([]string, n),
+	}
+
+	limit := r.MaxWorkers
+	if limit <= 0 {
+		limit = n
+	}
+
+	remaining := make([]int, n)
+	var ready []int
+	for i := range w.Steps {
+		remaining[i] = len(g.deps[i])
+		if remaining[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	// Unfinished steps per registry ref, so a step can tell what is still to run
+	unfinished := make(map[string]int)
+	for _, step := range w.Steps {
+		if step.Ref != "" {
+			unfinished[step.Ref]++
+		}
+	}
+
+	done := make(chan stepOutcome, n)
+	running := 0
+
+	finish := func(out stepOutcome) {
+		i := out.index
+		step := &w.Steps[i]
+		res := out.result
+		ex.results[i] = &res
+		if step.Ref != "" {
+			unfinished[step.Ref]--
+		}
+		ctx.events.emit(finishedEvent(res, "", false))
+
+		if out.ctx != nil {
+			ctx.merge(out.ctx, out.baseData, out.baseVersion)
+			ex.outputs[i] = out.ctx.Output.String()
+			r.checkpoint(state, res, ctx)
+		}
+		if !res.Skipped {
+			ex.executed = append(ex.executed, step)
+		}
+
+		if res.Cancelled {
+			ex.cancelled = true
+		} else if !res.Success && !res.Skipped {
+			// Aborting at an approval gate stops the workflow even for optional steps
+			if step.Required || errors.Is(res.Error, ErrAborted) {
+				if ex.failed == nil {
+					ex.failed = step
+				}
+			} else {
+				ex.outputs[i] += fmt.Sprintf("⚠ Step %s failed but is not required, continuing...\n", step.Name)
+			}
+		}
+
+		for _, next := range g.dependents[i] {
+			remaining[next]--
+			if remaining[next] == 0 {
+				ready = append(ready, next)
+			}
+		}
+		sort.Ints(ready)
+
+		ex.flush(ctx, false)
+	}
+
+	for {
+		if ctx.context().Err() != nil {
+			ex.cancelled = true
+		}
+		for ex.failed == nil && !ex.cancelled && len(ready) > 0 && running < limit {
+			i := ready[0]
+			ready = ready[1:]
+			step := w.Steps[i]
+
+			if r.Resume != nil && r.Resume.IsStepDone(step.Name) {
+				ex.outputs[i] = fmt.Sprintf("→ %s [already completed]\n", step.Name)
+				finish(stepOutcome{
+					index:  i,
+					result: StepResult{Name: step.Name, Phase: step.Phase, Success: true, Resumed: true},
+				})
+				continue
+			}
+
+			child := ctx.fork()
+			child.step = step.Name
+			child.pending = pendingRefs(unfinished, step.Ref)
+			out := stepOutcome{
+				index:       i,
+				ctx:         child,
+				baseData:    copyData(child.Data),
+				baseVersion: child.Version,
+			}
+			running++
+			ctx.events.emit(Event{Type: EventStepStarted, Step: step.Name, Phase: step.Phase, Status: "running"})
+			go func() {
+				out.result = r.runStepWithHooks(&step, child)
+				done <- out
+			}()
+		}
+
+		if running == 0 {
+			break
+		}
+
+		finish(<-done)
+		running--
+	}
+
+	ex.flush(ctx, true)
+	return ex
+}
+
+// flush writes finished step output to ctx in definition order.
+// Unless all is set, it stops at the first step that has not finished.
+func (ex *execution) flush(ctx *Context, all bool) {
+	for ex.flushed < len(ex.results) {
+		if ex.results[ex.flushed] == nil && !all {
+			return
+		}
+		ctx.Output.WriteString(ex.outputs[ex.flushed])
+		ex.flushed++
+	}
+}
+
+// stepResults returns the results of steps that ran, in definition order.
+func (ex *execution) stepResults() []StepResult {
+	var results []StepResult
+	for _, res := range ex.results {
+		if res != nil {
+			results = append(results, *res)
+		}
+	}
+	return results
+}
+
+// pendingRefs returns the refs with unfinished steps, other than self.
+func pendingRefs(unfinished map[string]int, self string) map[string]bool {
+	pending := make(map[string]bool)
+	for ref, n := range unfinished {
+		if n > 1 || (n == 1 && ref != self) {
+			pending[ref] = true
+		}
+	}
+	return pending
+}
+
+// copyData returns a shallow copy of a data map.
+func copyData(data map[string]string) map[string]string {
+	cp := make(map[string]string, len(data))
+	for k, v := range data {
+		cp[k] = v
+	}
+	return cp
+}
@@ -0,0 +1,148 @@
+package workflow
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// EventType identifies a workflow progress event.
+type EventType string
+
+const (
+	// EventWorkflowStarted is emitted before the first step runs.
+	EventWorkflowStarted EventType = "workflow_started"
+	// EventWorkflowFinished is emitted after the workflow and any rollback end.
+	EventWorkflowFinished EventType = "workflow_finished"
+	// EventStepStarted is emitted when a step or sub-step starts.
+	EventStepStarted EventType = "step_started"
+	// EventStepFinished is emitted when a step or sub-step finishes.
+	EventStepFinished EventType = "step_finished"
+	// EventLog is emitted for each line a step logs.
+	EventLog EventType = "log"
+)
+
+// Event is a progress update emitted while a workflow runs.
+type Event struct {
+	Type       EventType `json:"type" toon:"type"`
+	Workflow   string    `json:"workflow,omitempty" toon:"workflow,omitempty"`
+	Step       string    `json:"step,omitempty" toon:"step,omitempty"`
+	Parent     string    `json:"parent,omitempty" toon:"parent,omitempty"` // Composite step of a sub-step
+	Phase      string    `json:"phase,omitempty" toon:"phase,omitempty"`
+	Status     string    `json:"status,omitempty" toon:"status,omitempty"` // "running", "completed", "failed", "skipped", "cancelled", "resumed"
+	Message    string    `json:"message,omitempty" toon:"message,omitempty"`
+	Duration   string    `json:"duration,omitempty" toon:"duration,omitempty"`
+	TotalSteps int       `json:"total_steps,omitempty" toon:"total_steps,omitempty"`
+	Rollback   bool      `json:"rollback,omitempty" toon:"rollback,omitempty"` // Event belongs to a compensating undo
+	Timestamp  string    `json:"timestamp" toon:"timestamp"`
+}
+
+// EventSink receives workflow progress events.
+// The runner never calls Emit concurrently.
+type EventSink interface {
+	Emit(Event)
+}
+
+// EventSinkFunc adapts a function to an EventSink.
+type EventSinkFunc func(Event)
+
+// Emit calls f(e).
+func (f EventSinkFunc) Emit(e Event) {
+	f(e)
+}
+
+// MessageWriter writes a structured message, such as output.JSONWriter
+// or output.TOONWriter.
+type MessageWriter interface {
+	Write(msg interface{}) error
+}
+
+// NewWriterSink returns an EventSink that writes each event as a message.
+// Write errors are ignored so a closed output never fails a release.
+func NewWriterSink(w MessageWriter) EventSink {
+	return EventSinkFunc(func(e Event) {
+		_ = w.Write(e)
+	})
+}
+
+// emitter serializes events from concurrently running steps.
+type emitter struct {
+	mu   sync.Mutex
+	sink EventSink
+}
+
+// emit timestamps and delivers an event. A nil emitter discards events.
+func (em *emitter) emit(e Event) {
+	if em == nil {
+		return
+	}
+	e.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	em.sink.Emit(e)
+}
+
+// logLines emits a log event for each non-empty line of msg.
+func (em *emitter) logLines(step string, rollback bool, msg string) {
+	if em == nil {
+		return
+	}
+	for _, line := range strings.Split(msg, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			em.emit(Event{Type: EventLog, Step: step, Rollback: rollback, Message: line})
+		}
+	}
+}
+
+// stepStatus describes a step result for events.
+func stepStatus(res StepResult) string {
+	switch {
+	case res.Resumed:
+		return "resumed"
+	case res.Cancelled:
+		return "cancelled"
+	case res.Skipped:
+		return "skipped"
+	case res.Success:
+		return "completed"
+	default:
+		return "failed"
+	}
+}
+
+// finishedEvent builds the step_finished event for a result.
+func finishedEvent(res StepResult, parent string, rollback bool) Event {
+	e := Event{
+		Type:     EventStepFinished,
+		Step:     res.Name,
+		Parent:   parent,
+		Phase:    res.Phase,
+		Status:   stepStatus(res),
+		Duration: res.Duration.Round(time.Millisecond).String(),
+		Rollback: rollback,
+	}
+	if res.Error != nil {
+		e.Message = res.Error.Error()
+	}
+	return e
+}
+
+// workflowFinishedEvent builds the workflow_finished event for a result.
+func workflowFinishedEvent(wr *WorkflowResult) Event {
+	e := Event{
+		Type:     EventWorkflowFinished,
+		Workflow: wr.Name,
+		Status:   "completed",
+		Duration: wr.Duration.Round(time.Millisecond).String(),
+	}
+	switch {
+	case wr.Cancelled:
+		e.Status = "cancelled"
+	case !wr.Success:
+		e.Status = "failed"
+	}
+	if wr.Error != nil {
+		e.Message = wr.Error.Error()
+	}
+	return e
+}
@@ -0,0 +1,112 @@
+package workflow
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRunnerRun_Events(t *testing.T) {
+	var events []Event
+	runner := NewRunner()
+	runner.Events = EventSinkFunc(func(e Event) { events = append(events, e) })
+
+	wf := &Workflow{
+		Name: "Events",
+		Steps: []Step{
+			{Name: "Build", Type: StepTypeFunc, Required: true, Func: func(ctx *Context) error {
+				ctx.Log("  compiling\n  linking")
+				return nil
+			}},
+			{Name: "Group", Type: StepTypeComposite, Required: true, SubSteps: []Step{
+				{Name: "Inner", Type: StepTypeFunc, Required: true, Func: func(ctx *Context) error { return nil }},
+			}},
+			{Name: "Publish", Type: StepTypeFunc, Required: false, Func: func(ctx *Context) error {
+				return errors.New("registry down")
+			}},
+		},
+	}
+
+	result := runner.Run(wf, NewContext("/tmp", "v1.0.0"))
+	if !result.Success {
+		t.Fatalf("Workflow should succeed: %s", result.Output)
+	}
+
+	var got []string
+	for _, e := range events {
+		desc := string(e.Type)
+		if e.Step != "" {
+			desc += " " + e.Step
+		}
+		if e.Parent != "" {
+			desc += " <" + e.Parent
+		}
+		if e.Status != "" {
+			desc += " " + e.Status
+		}
+		if e.Type == EventLog {
+			desc += " " + e.Message
+		}
+		if e.Timestamp == "" {
+			t.Errorf("event %s has no timestamp", desc)
+		}
+		got = append(got, desc)
+	}
+
+	want := []string{
+		"workflow_started running",
+		"step_started Build running",
+		"log Build compiling",
+		"log Build linking",
+		"step_finished Build completed",
+		"step_started Group running",
+		"step_started Inner <Group running",
+		"step_finished Inner <Group completed",
+		"step_finished Group completed",
+		"step_started Publish running",
+		"step_finished Publish failed",
+		"workflow_finished completed",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("events:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if events[0].TotalSteps != 3 {
+		t.Errorf("TotalSteps = %d, want 3", events[0].TotalSteps)
+	}
+}
+
+func TestRunnerRun_EventsRollback(t *testing.T) {
+	var events []Event
+	runner := NewRunner()
+	runner.Events = EventSinkFunc(func(e Event) { events = append(events, e) })
+
+	wf := &Workflow{
+		Name: "Rollback",
+		Steps: []Step{
+			{Name: "Commit", Type: StepTypeFunc, Required: true,
+				Func: func(ctx *Context) error { return nil },
+				Undo: func(ctx *Context) error {
+					ctx.Log("  reset")
+					return nil
+				}},
+			{Name: "Tag", Type: StepTypeFunc, Required: true, Func: func(ctx *Context) error { return errors.New("tag failed") }},
+		},
+	}
+
+	runner.Run(wf, NewContext("/tmp", "v1.0.0"))
+
+	last := events[len(events)-1]
+	if last.Type != EventWorkflowFinished || last.Status != "failed" || !strings.Contains(last.Message, "Tag") {
+		t.Errorf("last event = %+v, want failed workflow_finished naming Tag", last)
+	}
+
+	var undoLog bool
+	for _, e := range events {
+		if e.Type == EventLog && e.Message == "reset" {
+			undoLog = e.Rollback && e.Step == "Commit"
+		}
+	}
+	if !undoLog {
+		t.Error("undo log should be emitted as a rollback event for Commit")
+	}
+}
@@ -0,0 +1,187 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/agentplexus/agent-team-release/pkg/config"
+)
+
+// Hook points around a step.
+const (
+	HookBefore    = "before"
+	HookAfter     = "after"
+	HookOnFailure = "on_failure"
+)
+
+// HookPayload is the JSON document written to a hook's stdin.
+type HookPayload struct {
+	Hook    string            `json:"hook"`
+	Step    string            `json:"step"`
+	StepRef string            `json:"step_ref,omitempty"`
+	Status  string            `json:"status"` // "pending", "success", "failed"
+	Error   string            `json:"error,omitempty"`
+	Version string            `json:"version"`
+	Commit  string            `json:"commit,omitempty"`
+	Tag     string            `json:"tag,omitempty"` // the release's tag, e.g. "sdk/go/v1.2.0" for a module
+	DryRun  bool              `json:"dry_run"`
+	Data    map[string]string `json:"data,omitempty"`
+}
+
+// runStepWithHooks runs a top-level step surrounded by its configured hooks.
+func (r *Runner) runStepWithHooks(step *Step, ctx *Context) StepResult {
+	hooks := r.hooksFor(step)
+	if len(hooks.Before) == 0 && len(hooks.After) == 0 && len(hooks.OnFailure) == 0 {
+		return r.runStep(step, ctx)
+	}
+
+	start := time.Now()
+
+	if err := r.runHooks(HookBefore, hooks.Before, step, ctx, nil); err != nil {
+		ctx.Log("→ %s [blocked: %v]\n", step.Name, err)
+		result := StepResult{
+			Name:      step.Name,
+			Phase:     step.Phase,
+			Cancelled: ctx.context().Err() != nil,
+			Error:     err,
+			Output:    err.Error(),
+			Duration:  time.Since(start),
+		}
+		if !result.Cancelled {
+			_ = r.runHooks(HookOnFailure, hooks.OnFailure, step, ctx, &result)
+		}
+		return result
+	}
+
+	result := r.runStep(step, ctx)
+	if result.Cancelled {
+		// Hooks would be killed immediately; leave them for the resumed run
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	if result.Success && !result.Skipped {
+		if err := r.runHooks(HookAfter, hooks.After, step, ctx, &result); err != nil {
+			result.Success = false
+			result.Error = err
+			result.Output = err.Error()
+		}
+	}
+	if !result.Success && !result.Skipped {
+		_ = r.runHooks(HookOnFailure, hooks.OnFailure, step, ctx, &result)
+	}
+
+	result.Duration = time.Since(start)
+	return result
+}
+
+// hooksFor collects the hooks matching a step: wildcard hooks first, then
+// hooks keyed by the step reference, then hooks keyed by the step name.
+func (r *Runner) hooksFor(step *Step) config.HookSet {
+	var set config.HookSet
+	keys := []string{"*", step.Ref}
+	if step.Name != step.Ref {
+		keys = append(keys, step.Name)
+	}
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		h, ok := r.Hooks[key]
+		if !ok {
+			continue
+		}
+		set.Before = append(set.Before, h.Before...)
+		set.After = append(set.After, h.After...)
+		set.OnFailure = append(set.OnFailure, h.OnFailure...)
+	}
+	return set
+}
+
+// runHooks runs the hooks for one hook point. It returns the first error from
+// a blocking hook; on_failure hooks never block.
+func (r *Runner) runHooks(point string, hooks []config.HookConfig, step *Step, ctx *Context, result *StepResult) error {
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	payload := hookPayload(point, step, ctx, result)
+	stdin, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encoding hook payload: %w", err)
+	}
+
+	env := []string{
+		"ATRELEASE_HOOK=" + payload.Hook,
+		"ATRELEASE_STEP=" + payload.Step,
+		"ATRELEASE_STEP_REF=" + payload.StepRef,
+		"ATRELEASE_STEP_STATUS=" + payload.Status,
+		"ATRELEASE_STEP_ERROR=" + payload.Error,
+		"ATRELEASE_VERSION=" + payload.Version,
+		"ATRELEASE_COMMIT=" + payload.Commit,
+		"ATRELEASE_TAG=" + payload.Tag,
+		"ATRELEASE_DIR=" + ctx.Dir,
+		fmt.Sprintf("ATRELEASE_DRY_RUN=%t", ctx.DryRun),
+	}
+
+	for _, h := range hooks {
+		if ctx.DryRun {
+			ctx.Log("  [Dry run] Would run %s hook: %s", point, h.Run)
+			continue
+		}
+
+		var timeout time.Duration
+		if h.Timeout != "" {
+			d, err := time.ParseDuration(h.Timeout)
+			if err != nil {
+				return fmt.Errorf("%s hook %q: invalid timeout %q", point, h.Run, h.Timeout)
+			}
+			timeout = d
+		}
+
+		hookEnv := env
+		for k, v := range h.Env {
+			hookEnv = append(hookEnv, k+"="+v)
+		}
+
+		ctx.Log("  ↳ %s hook: %s", point, h.Run)
+		if err := execShell(ctx, h.Run, ctx.Dir, hookEnv, stdin, timeout); err != nil {
+			if point != HookOnFailure && h.Blocking() {
+				return fmt.Errorf("%s hook %q failed: %w", point, h.Run, err)
+			}
+			ctx.Log("  Warning: %s hook %q failed: %v", point, h.Run, err)
+		}
+	}
+
+	return nil
+}
+
+// hookPayload describes the step and release state for a hook.
+func hookPayload(point string, step *Step, ctx *Context, result *StepResult) HookPayload {
+	p := HookPayload{
+		Hook:    point,
+		Step:    step.Name,
+		StepRef: step.Ref,
+		Status:  "pending",
+		Version: ctx.Version,
+		DryRun:  ctx.DryRun,
+		Data:    ctx.Data,
+	}
+	if ctx.Version != "" {
+		p.Tag = ReleaseTag(ctx)
+	}
+	if result != nil {
+		p.Status = "success"
+		if !result.Success {
+			p.Status = "failed"
+		}
+		if result.Error != nil {
+			p.Error = result.Error.Error()
+		}
+	}
+	if commit, err := ctx.git().CurrentCommit(); err == nil {
+		p.Commit = commit
+	}
+	return p
+}
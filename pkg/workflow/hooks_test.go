@@ -0,0 +1,151 @@
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/agent-team-release/pkg/config"
+)
+
+func hookWorkflow(stepErr error) *Workflow {
+	return &Workflow{
+		Name: "Hooked",
+		Steps: []Step{
+			{
+				Name:     "Push to remote",
+				Ref:      StepPush,
+				Type:     StepTypeFunc,
+				Required: true,
+				Func:     func(ctx *Context) error { return stepErr },
+			},
+		},
+	}
+}
+
+func TestRunnerHooks_BeforeAndAfter(t *testing.T) {
+	dir := t.TempDir()
+
+	runner := NewRunner()
+	runner.Hooks = map[string]config.HookSet{
+		"*": {
+			Before: []config.HookConfig{{Run: `echo "before $ATRELEASE_STEP" >> hooks.log`}},
+		},
+		StepPush: {
+			After: []config.HookConfig{{Run: `cat > payload.json; echo "after $ATRELEASE_STEP_STATUS $ATRELEASE_VERSION" >> hooks.log`}},
+		},
+	}
+
+	result := runner.Run(hookWorkflow(nil), NewContext(dir, "v1.0.0"))
+	if !result.Success {
+		t.Fatalf("workflow should succeed: %s", result.Output)
+	}
+
+	log, err := os.ReadFile(filepath.Join(dir, "hooks.log"))
+	if err != nil {
+		t.Fatalf("hooks did not run: %v", err)
+	}
+	want := "before Push to remote\nafter success v1.0.0\n"
+	if string(log) != want {
+		t.Errorf("hooks.log = %q, want %q", log, want)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "payload.json"))
+	if err != nil {
+		t.Fatalf("payload not written: %v", err)
+	}
+	var payload HookPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if payload.Hook != HookAfter || payload.StepRef != StepPush || payload.Status != "success" {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestRunnerHooks_BlockingBefore(t *testing.T) {
+	stepRan := false
+	wf := hookWorkflow(nil)
+	wf.Steps[0].Func = func(ctx *Context) error {
+		stepRan = true
+		return nil
+	}
+
+	runner := NewRunner()
+	runner.Hooks = map[string]config.HookSet{
+		"Push to remote": {Before: []config.HookConfig{{Run: "exit 1"}}},
+	}
+
+	result := runner.Run(wf, NewContext(t.TempDir(), "v1.0.0"))
+	if result.Success {
+		t.Error("blocking hook failure should fail the workflow")
+	}
+	if stepRan {
+		t.Error("step should not run when a blocking before hook fails")
+	}
+}
+
+func TestRunnerHooks_WarnOnly(t *testing.T) {
+	runner := NewRunner()
+	runner.Hooks = map[string]config.HookSet{
+		StepPush: {After: []config.HookConfig{{Run: "exit 1", OnError: "warn"}}},
+	}
+
+	result := runner.Run(hookWorkflow(nil), NewContext(t.TempDir(), "v1.0.0"))
+	if !result.Success {
+		t.Error("warn-only hook failure should not fail the workflow")
+	}
+	if !strings.Contains(result.Output, "Warning: after hook") {
+		t.Errorf("output should contain a warning, got: %s", result.Output)
+	}
+}
+
+func TestRunnerHooks_OnFailure(t *testing.T) {
+	dir := t.TempDir()
+
+	runner := NewRunner()
+	runner.Hooks = map[string]config.HookSet{
+		StepPush: {OnFailure: []config.HookConfig{{Run: `echo "$ATRELEASE_STEP_ERROR" > failure.txt`}}},
+	}
+
+	result := runner.Run(hookWorkflow(errors.New("remote rejected")), NewContext(dir, "v1.0.0"))
+	if result.Success {
+		t.Fatal("workflow should fail")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "failure.txt"))
+	if err != nil {
+		t.Fatalf("on_failure hook did not run: %v", err)
+	}
+	if strings.TrimSpace(string(data)) != "remote rejected" {
+		t.Errorf("failure.txt = %q", data)
+	}
+}
+
+func TestRunnerHooks_ModuleTag(t *testing.T) {
+	dir := t.TempDir()
+
+	runner := NewRunner()
+	runner.Hooks = map[string]config.HookSet{
+		StepPush: {Before: []config.HookConfig{{Run: `echo "$ATRELEASE_TAG" > tag.txt`}}},
+	}
+
+	// The tag is known before the tag step creates it
+	ctx := NewContext(dir, "v1.0.0")
+	ctx.Data["module"] = "sdk/go"
+	result := runner.Run(hookWorkflow(nil), ctx)
+	if !result.Success {
+		t.Fatalf("workflow should succeed: %s", result.Output)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "tag.txt"))
+	if err != nil {
+		t.Fatalf("before hook did not run: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "sdk/go/v1.0.0" {
+		t.Errorf("ATRELEASE_TAG = %q, want sdk/go/v1.0.0", got)
+	}
+}
@@ -0,0 +1,319 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/agentplexus/agent-team-release/pkg/actions"
+	"github.com/agentplexus/agent-team-release/pkg/changelog"
+	"github.com/agentplexus/agent-team-release/pkg/semver"
+)
+
+// Hotfix step references.
+const (
+	StepHotfixBranch    = "hotfix-branch"
+	StepHotfixChangelog = "hotfix-changelog"
+	StepHotfixCleanup   = "hotfix-cleanup"
+)
+
+func init() {
+	RegisterStep(StepHotfixBranch, Step{
+		Name:        "Prepare hotfix branch",
+		Description: "Create a temporary branch at the tag being fixed",
+		Type:        StepTypeFunc,
+		Required:    true,
+		Func:        prepareHotfixBranch,
+		Undo:        undoHotfixBranch,
+	})
+	RegisterStep(StepHotfixChangelog, Step{
+		Name:        "Add hotfix changelog entry",
+		Description: "Add the hotfix's fixes to CHANGELOG.json",
+		Type:        StepTypeFunc,
+		Required:    false,
+		Func:        addHotfixChangelog,
+		Preview:     previewHotfixChangelog,
+	})
+	RegisterStep(StepHotfixCleanup, Step{
+		Name:        "Clean up hotfix branch",
+		Description: "Return to the original branch and delete the temporary branch",
+		Type:        StepTypeFunc,
+		Required:    false,
+		Func:        cleanupHotfixBranch,
+	})
+}
+
+// HotfixBranchName returns the temporary branch a hotfix is prepared on.
+func HotfixBranchName(version string) string {
+	return "hotfix/" + version
+}
+
+// SetHotfix records the inputs of a hotfix workflow in ctx, so they are
+// checkpointed and restored on resume. from is the tag being fixed (empty =
+// the latest earlier tag of the version's release line); picks are the
+// commits to cherry-pick onto it, in order.
+func SetHotfix(ctx *Context, from string, picks []string) error {
+	target, err := semver.Parse(ctx.Version)
+	if err != nil {
+		return err
+	}
+
+	if from == "" {
+		if from, err = previousLineTag(ctx); err != nil {
+			return err
+		}
+		if from == "" {
+			return fmt.Errorf("no earlier %d.%d tag to fix; use --from", target.Major, target.Minor)
+		}
+	}
+	base, err := semver.Parse(from)
+	if err != nil {
+		return fmt.Errorf("invalid --from tag: %w", err)
+	}
+	if base.Compare(target) >= 0 {
+		return fmt.Errorf("hotfix %s must be newer than %s", ctx.Version, from)
+	}
+
+	ctx.Data["hotfix_branch"] = HotfixBranchName(ctx.Version)
+	ctx.Data["hotfix_from"] = from
+	ctx.Data["cherry_picks"] = strings.Join(picks, " ")
+	return nil
+}
+
+// IsHotfix reports whether a checkpoint belongs to a hotfix workflow.
+func IsHotfix(state *State) bool {
+	return state.Data["hotfix_branch"] != ""
+}
+
+// HotfixWorkflow creates a workflow releasing a patch version from an older
+// tag: the fixes are cherry-picked onto a temporary branch at the tag,
+// validated, recorded in CHANGELOG.json and tagged. Only the tag is pushed;
+// no branch on the remote is changed.
+func HotfixWorkflow(version string) *Workflow {
+	return &Workflow{
+		Name:        "Hotfix " + version,
+		Description: "Release hotfix " + version + " from an earlier tag",
+		Steps: []Step{
+			builtin(StepValidateVersion),
+			builtin(StepCheckWorkingDir),
+			builtin(StepHotfixBranch, "Validate version", "Check working directory"),
+			builtin(StepCherryPick, "Prepare hotfix branch"),
+			builtin(StepBump, "Cherry-pick commits"),
+			builtin(StepValidate, "Bump versions"),
+			builtin(StepPMValidation, "Bump versions"),
+			builtin(StepDocsValidation, "Bump versions"),
+			builtin(StepSecurityValidation, "Bump versions"),
+			builtin(StepReleaseValidation, "Bump versions"),
+			builtin(StepHotfixChangelog, "Run validation checks", "PM validation", "Documentation validation",
+				"Security validation", "Release validation"),
+			builtin(StepCommit, "Add hotfix changelog entry"),
+			builtin(StepTag, "Create release commit"),
+			builtin(StepHotfixCleanup, "Create tag"),
+		},
+	}
+}
+
+// prepareHotfixBranch creates the hotfix branch at the tag being fixed and
+// checks it out.
+func prepareHotfixBranch(ctx *Context) error {
+	g := ctx.git()
+	branch := ctx.Data["hotfix_branch"]
+	from := ctx.Data["hotfix_from"]
+	if branch == "" || from == "" {
+		return errors.New("hotfix branch and base tag not set")
+	}
+
+	current, err := g.CurrentBranch()
+	if err != nil {
+		return err
+	}
+	if ctx.Data["original_branch"] == "" {
+		ctx.Data["original_branch"] = current
+	}
+	if current == branch {
+		ctx.Log("  Already on %s", branch)
+		return nil
+	}
+
+	if _, err := g.ResolveCommit(from); err != nil {
+		return fmt.Errorf("invalid base tag: %w", err)
+	}
+	exists, err := g.BranchExists(branch)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("branch %s already exists; delete it to start the hotfix over", branch)
+	}
+
+	if ctx.DryRun {
+		ctx.Data["planned_branch"] = branch
+		ctx.Log("  [Dry run] Would create %s from %s", branch, from)
+		return nil
+	}
+
+	if err := g.CreateBranch(branch, from); err != nil {
+		return err
+	}
+	ctx.Data["hotfix_branch_created"] = "true"
+	if err := g.Checkout(branch); err != nil {
+		return err
+	}
+	ctx.Log("  Created %s from %s", branch, from)
+	return nil
+}
+
+// undoHotfixBranch returns to the original branch and deletes the hotfix branch.
+func undoHotfixBranch(ctx *Context) error {
+	returnToOriginalBranch(ctx)
+	return deleteHotfixBranch(ctx)
+}
+
+// deleteHotfixBranch deletes the hotfix branch if this run created it.
+func deleteHotfixBranch(ctx *Context) error {
+	if ctx.Data["hotfix_branch_created"] == "" {
+		return nil
+	}
+	branch := ctx.Data["hotfix_branch"]
+	if err := ctx.git().DeleteBranch(branch); err != nil {
+		return err
+	}
+	delete(ctx.Data, "hotfix_branch_created")
+	ctx.Log("  Deleted local branch %s", branch)
+	return nil
+}
+
+// cleanupHotfixBranch returns to the original branch and deletes the
+// hotfix branch. The tag keeps the hotfix commits reachable.
+func cleanupHotfixBranch(ctx *Context) error {
+	if ctx.DryRun {
+		ctx.Log("  [Dry run] Would delete %s and return to %s", ctx.Data["hotfix_branch"], ctx.Data["original_branch"])
+		return nil
+	}
+	returnToOriginalBranch(ctx)
+	return deleteHotfixBranch(ctx)
+}
+
+// conventionalPrefix matches a conventional commit type and scope, e.g. "fix(api)!: ".
+var conventionalPrefix = regexp.MustCompile(`^\w+(\([^)]*\))?!?:\s*`)
+
+// hotfixChangelog returns the hotfix's CHANGELOG.json content, and the
+// changelog with its entry added, or nil if the file doesn't exist. The
+// entry lists the picked commits as fixes.
+func hotfixChangelog(ctx *Context) (old []byte, cl *changelog.Changelog, err error) {
+	old, err = hotfixChangelogSource(ctx)
+	if old == nil || err != nil {
+		return nil, nil, err
+	}
+
+	cl, err = changelog.Parse(old)
+	if err != nil {
+		return nil, nil, err
+	}
+	release, err := cl.AddRelease(ctx.Version, time.Now().Format(changelog.DateFormat))
+	if err != nil {
+		return nil, nil, err
+	}
+	g := ctx.git()
+	for _, ref := range strings.Fields(ctx.Data["cherry_picks"]) {
+		sha, err := g.ResolveCommit(ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		short, subject, _ := strings.Cut(describeCommit(ctx, sha), " ")
+		if err := release.Add(changelog.Fixed, changelog.Entry{
+			Description: changelogDescription(subject),
+			Commit:      short,
+		}); err != nil {
+			return nil, nil, err
+		}
+	}
+	return old, cl, nil
+}
+
+// changelogDescription turns a commit subject into a changelog description
+// by dropping its conventional commit prefix and capitalizing it.
+func changelogDescription(subject string) string {
+	desc := conventionalPrefix.ReplaceAllString(subject, "")
+	r, size := utf8.DecodeRuneInString(desc)
+	return string(unicode.ToUpper(r)) + desc[size:]
+}
+
+// addHotfixChangelog adds the hotfix's entry to CHANGELOG.json in version
+// order and regenerates CHANGELOG.md.
+func addHotfixChangelog(ctx *Context) error {
+	_, cl, err := hotfixChangelog(ctx)
+	if errors.Is(err, changelog.ErrReleaseExists) {
+		ctx.Log("  %s already has a %s entry", changelog.FileName, ctx.Version)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if cl == nil {
+		ctx.Log("  No %s found, skipping", changelog.FileName)
+		return nil
+	}
+
+	if ctx.DryRun {
+		ctx.Log("  [Dry run] Would add a %s entry to %s", ctx.Version, changelog.FileName)
+		return nil
+	}
+
+	if err := cl.Save(filepath.Join(ctx.Dir, changelog.FileName)); err != nil {
+		return err
+	}
+	if err := (&actions.ChangelogAction{}).Generate(ctx.Dir); err != nil {
+		ctx.Log("  Warning: failed to regenerate CHANGELOG.md: %v", err)
+	}
+
+	ctx.Log("  Added a %s entry to %s", ctx.Version, changelog.FileName)
+	return nil
+}
+
+// hotfixChangelogSource returns CHANGELOG.json as it is on the hotfix
+// branch, or nil if there is none. A dry run doesn't create the branch, so
+// the file is read from the tag being fixed instead.
+func hotfixChangelogSource(ctx *Context) ([]byte, error) {
+	if ctx.DryRun && ctx.Data["hotfix_branch_created"] == "" {
+		data, err := ctx.git().ShowFile(ctx.Data["hotfix_from"], changelog.FileName)
+		if err != nil {
+			return nil, nil
+		}
+		return []byte(data), nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(ctx.Dir, changelog.FileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// previewHotfixChangelog shows the CHANGELOG.json change.
+func previewHotfixChangelog(ctx *Context) ([]actions.Proposal, error) {
+	old, cl, err := hotfixChangelog(ctx)
+	if errors.Is(err, changelog.ErrReleaseExists) {
+		return nil, nil
+	}
+	if err != nil || cl == nil {
+		return nil, err
+	}
+	updated, err := cl.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	return []actions.Proposal{{
+		Description: fmt.Sprintf("Add the %s entry to %s", ctx.Version, changelog.FileName),
+		FilePath:    changelog.FileName,
+		OldContent:  string(old),
+		NewContent:  string(updated),
+	}}, nil
+}
@@ -0,0 +1,168 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/agent-team-release/pkg/changelog"
+)
+
+func runHotfix(t *testing.T, dir, version, from string, picks ...string) *WorkflowResult {
+	t.Helper()
+	ctx := NewContext(dir, version)
+	ctx.SkipChecks = true
+	if err := SetHotfix(ctx, from, picks); err != nil {
+		t.Fatal(err)
+	}
+	return NewRunner().Run(HotfixWorkflow(version), ctx)
+}
+
+func TestSetHotfix(t *testing.T) {
+	dir, _ := initBranchRepo(t)
+	gitRun(t, dir, "tag", "v1.1.0")
+
+	ctx := NewContext(dir, "v1.0.1")
+	if err := SetHotfix(ctx, "", []string{"abc1234"}); err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Data["hotfix_from"] != "v1.0.0" || ctx.Data["hotfix_branch"] != "hotfix/v1.0.1" {
+		t.Errorf("SetHotfix() data = %v", ctx.Data)
+	}
+	if !IsHotfix(&State{Data: ctx.Data}) {
+		t.Error("IsHotfix() = false")
+	}
+
+	for version, from := range map[string]string{
+		"v1.0.0": "v1.0.0", // not newer than the tag
+		"v2.0.1": "",       // no earlier 2.0 tag
+		"v1.0.2": "main",   // not a version
+	} {
+		if err := SetHotfix(NewContext(dir, version), from, nil); err == nil {
+			t.Errorf("SetHotfix(%s, from %q) should fail", version, from)
+		}
+	}
+}
+
+func TestChangelogDescription(t *testing.T) {
+	tests := map[string]string{
+		"fix(cli): crash on empty input": "Crash on empty input",
+		"fix!: drop legacy flag":         "Drop legacy flag",
+		"handle nil config":              "Handle nil config",
+	}
+	for subject, want := range tests {
+		if got := changelogDescription(subject); got != want {
+			t.Errorf("changelogDescription(%q) = %q, want %q", subject, got, want)
+		}
+	}
+}
+
+func TestHotfixWorkflow(t *testing.T) {
+	dir, mainBranch := initBranchRepo(t)
+	fix := commitFile(t, dir, "fix.txt", "fixed\n", "fix(cli): crash on empty input")
+	commitFile(t, dir, "app.txt", "v2\n", "feat: next")
+	gitRun(t, dir, "tag", "-a", "v1.1.0", "-m", "Release v1.1.0")
+	gitRun(t, dir, "push", "origin", mainBranch, "--tags")
+
+	mainHead := gitRun(t, dir, "rev-parse", mainBranch)
+	remoteHeads := gitRun(t, dir, "ls-remote", "--heads", "origin")
+
+	result := runHotfix(t, dir, "v1.0.1", "v1.0.0", fix)
+	if !result.Success {
+		t.Fatalf("hotfix failed: %v\n%s", result.Error, result.Output)
+	}
+
+	// The tag descends from v1.0.0 with the fix but not later work
+	gitRun(t, dir, "merge-base", "--is-ancestor", "v1.0.0", "v1.0.1")
+	if got := gitRun(t, dir, "log", "--format=%s", "v1.0.0..v1.0.1"); got != "chore(release): v1.0.1\nfix(cli): crash on empty input" {
+		t.Errorf("commits since v1.0.0 = %q", got)
+	}
+	if got := gitRun(t, dir, "show", "v1.0.1:app.txt"); got != "v1" {
+		t.Errorf("app.txt at v1.0.1 = %q, want v1", got)
+	}
+	if !strings.Contains(gitRun(t, dir, "ls-remote", "--tags", "origin"), "refs/tags/v1.0.1") {
+		t.Error("tag v1.0.1 not pushed")
+	}
+
+	// The changelog entry lists the fix
+	data := gitRun(t, dir, "show", "v1.0.1:"+changelog.FileName)
+	if versions := changelogVersions(t, data); strings.Join(versions, ",") != "v1.0.1,v1.0.0" {
+		t.Errorf("changelog versions = %v", versions)
+	}
+	cl, err := changelog.Parse([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixed := cl.Release("v1.0.1").Entries(changelog.Fixed)
+	if len(fixed) != 1 || fixed[0].Description != "Crash on empty input" || !strings.HasPrefix(fix, fixed[0].Commit) {
+		t.Errorf("changelog fixes = %+v", fixed)
+	}
+
+	// No branch changed, locally or on the remote
+	if got := gitRun(t, dir, "rev-parse", mainBranch); got != mainHead {
+		t.Errorf("%s moved to %s", mainBranch, got)
+	}
+	if got := gitRun(t, dir, "ls-remote", "--heads", "origin"); got != remoteHeads {
+		t.Errorf("remote branches changed:\n%s\nwant\n%s", got, remoteHeads)
+	}
+	if got := gitRun(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); got != mainBranch {
+		t.Errorf("current branch = %s, want %s", got, mainBranch)
+	}
+	if out := gitRun(t, dir, "branch", "--list", "hotfix/*"); out != "" {
+		t.Errorf("hotfix branch not deleted: %q", out)
+	}
+}
+
+func TestHotfixWorkflow_DryRun(t *testing.T) {
+	dir, mainBranch := initBranchRepo(t)
+	fix := commitFile(t, dir, "fix.txt", "fixed\n", "fix: crash")
+
+	ctx := NewContext(dir, "v1.0.1")
+	ctx.SkipChecks = true
+	if err := SetHotfix(ctx, "", []string{fix}); err != nil {
+		t.Fatal(err)
+	}
+	runner := NewRunner()
+	runner.DryRun = true
+	result := runner.Run(HotfixWorkflow("v1.0.1"), ctx)
+	if !result.Success {
+		t.Fatalf("dry run failed: %v\n%s", result.Error, result.Output)
+	}
+
+	found := false
+	for _, item := range result.Plan() {
+		if item.Step == "Add hotfix changelog entry" {
+			found = strings.Contains(item.Diff, `+      "version": "v1.0.1"`)
+		}
+	}
+	if !found {
+		t.Errorf("plan has no changelog diff: %+v", result.Plan())
+	}
+
+	if out := gitRun(t, dir, "branch", "--list", "hotfix/*"); out != "" {
+		t.Errorf("dry run created a branch: %q", out)
+	}
+	if got := gitRun(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); got != mainBranch {
+		t.Errorf("current branch = %s, want %s", got, mainBranch)
+	}
+}
+
+func TestHotfixWorkflow_RollbackOnConflict(t *testing.T) {
+	dir, mainBranch := initBranchRepo(t)
+	commitFile(t, dir, "app.txt", "v2\n", "feat: v2")
+	conflicting := commitFile(t, dir, "app.txt", "v3\n", "fix: v3")
+
+	result := runHotfix(t, dir, "v1.0.1", "v1.0.0", conflicting)
+	if result.Success {
+		t.Fatal("expected the conflicting cherry-pick to fail the hotfix")
+	}
+
+	if got := gitRun(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); got != mainBranch {
+		t.Errorf("current branch = %s, want %s", got, mainBranch)
+	}
+	if out := gitRun(t, dir, "branch", "--list", "hotfix/*"); out != "" {
+		t.Errorf("hotfix branch not deleted: %q", out)
+	}
+	if out := gitRun(t, dir, "tag", "--list", "v1.0.1"); out != "" {
+		t.Errorf("tag created: %q", out)
+	}
+}
@@ -0,0 +1,338 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/agentplexus/agent-team-release/pkg/actions"
+	"github.com/agentplexus/agent-team-release/pkg/detect"
+	"github.com/agentplexus/agent-team-release/pkg/git"
+	"github.com/agentplexus/agent-team-release/pkg/gomod"
+	"github.com/agentplexus/agent-team-release/pkg/semver"
+)
+
+// majorDirRegex matches a major version subdirectory such as "v2", which
+// Go leaves out of a module's tag prefix.
+var majorDirRegex = regexp.MustCompile(`^v([2-9]|[1-9]\d+)$`)
+
+// Module is a Go module in a repository.
+type Module struct {
+	Dir  string `json:"dir" toon:"dir"`   // Directory relative to the repository root ("." for the root module)
+	Path string `json:"path" toon:"path"` // Module path declared in go.mod
+}
+
+// ModuleChange describes the changes to a module since its last release.
+type ModuleChange struct {
+	Dir     string `json:"dir" toon:"dir"`
+	Path    string `json:"path" toon:"path"`
+	LastTag string `json:"last_tag,omitempty" toon:"last_tag,omitempty"` // Latest tag reachable from HEAD
+	Commits int    `json:"commits" toon:"commits"`                       // Commits touching the module since LastTag
+	Changed bool   `json:"changed" toon:"changed"`                       // Untagged, or has commits since LastTag
+}
+
+// Summary describes the change, e.g. "sdk/go: 3 commit(s) since sdk/go/v1.2.2".
+func (m ModuleChange) Summary() string {
+	if m.LastTag == "" {
+		return fmt.Sprintf("%s: never released (%d commit(s))", m.Dir, m.Commits)
+	}
+	if m.Commits == 0 {
+		return fmt.Sprintf("%s: unchanged since %s", m.Dir, m.LastTag)
+	}
+	return fmt.Sprintf("%s: %d commit(s) since %s", m.Dir, m.Commits, m.LastTag)
+}
+
+// ModuleTag returns the tag releasing version of the Go module in the
+// repository directory module, following Go's convention for modules in
+// subdirectories: "sdk/go/v1.2.3" for sdk/go, and the version alone for the
+// root module. A major version subdirectory such as sdk/go/v2 shares the
+// tags of sdk/go.
+func ModuleTag(module, version string) string {
+	if prefix := tagPrefix(module); prefix != "" {
+		return prefix + "/" + version
+	}
+	return version
+}
+
+// tagPrefix returns the tag prefix of the module in the repository
+// directory module, or "" for the root module.
+func tagPrefix(module string) string {
+	module = path.Clean(filepath.ToSlash(module))
+	if module == "." {
+		return ""
+	}
+	dir, last := path.Split(module)
+	if majorDirRegex.MatchString(last) {
+		module = strings.TrimSuffix(dir, "/")
+	}
+	return module
+}
+
+// moduleVersions returns the versions tagged for the module in the
+// repository directory module: tags with its prefix, with the prefix
+// removed, or tags without a prefix for the root module.
+func moduleVersions(tags []string, module string) []string {
+	prefix := tagPrefix(module)
+	var versions []string
+	for _, tag := range tags {
+		dir, version := path.Split(tag)
+		if strings.TrimSuffix(dir, "/") == prefix {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+// SetModule records in ctx that the release is of the Go module in the
+// repository directory module rather than the whole repository, so it is
+// checkpointed and restored on resume. "." and "" mean the root module.
+func SetModule(ctx *Context, module string) error {
+	module = path.Clean(filepath.ToSlash(module))
+	if module == "." {
+		return nil
+	}
+	if path.IsAbs(module) || module == ".." || strings.HasPrefix(module, "../") {
+		return fmt.Errorf("module %s is outside the repository", module)
+	}
+	if _, err := os.Stat(filepath.Join(ctx.Dir, filepath.FromSlash(module), gomod.FileName)); err != nil {
+		return fmt.Errorf("no Go module in %s: %w", module, err)
+	}
+	ctx.Data["module"] = module
+	return nil
+}
+
+// ReleaseModule returns the repository directory of the module ctx
+// releases, or "" when it releases the whole repository.
+func ReleaseModule(ctx *Context) string {
+	return ctx.Data["module"]
+}
+
+// ReleaseTag returns the tag ctx releases: the version, prefixed with the
+// module directory for a module release.
+func ReleaseTag(ctx *Context) string {
+	return ModuleTag(ReleaseModule(ctx), ctx.Version)
+}
+
+// moduleDir returns the directory changelogs, READMEs and validation are
+// scoped to: the module's directory, or the repository.
+func moduleDir(ctx *Context) string {
+	if module := ReleaseModule(ctx); module != "" {
+		return filepath.Join(ctx.Dir, filepath.FromSlash(module))
+	}
+	return ctx.Dir
+}
+
+// inModule makes the file paths of proposals computed in moduleDir
+// relative to the repository.
+func inModule(ctx *Context, proposals []actions.Proposal) []actions.Proposal {
+	module := ReleaseModule(ctx)
+	if module == "" {
+		return proposals
+	}
+	for i := range proposals {
+		if proposals[i].FilePath != "" {
+			proposals[i].FilePath = path.Join(module, proposals[i].FilePath)
+		}
+	}
+	return proposals
+}
+
+// previousTag returns the latest release tag reachable from HEAD, of the
+// module for a module release. The tags of nested modules never count as
+// the root module's.
+func previousTag(ctx *Context) (string, error) {
+	return ctx.git().LatestTagMatching(ModuleTag(ReleaseModule(ctx), "v[0-9]*"))
+}
+
+// validateModuleVersion checks that a module release's version suits the
+// module: Go requires a /vN module path suffix for major versions from 2.
+func validateModuleVersion(ctx *Context, module string) error {
+	v, err := semver.Parse(ctx.Version)
+	if err != nil {
+		return err
+	}
+	modFile, err := gomod.ReadFile(filepath.Join(moduleDir(ctx), gomod.FileName))
+	if err != nil {
+		return err
+	}
+	modPath := modFile.Module
+	if err := checkMajorVersion(modPath, v); err != nil {
+		return err
+	}
+
+	ctx.Log("  Module: %s (%s)", module, modPath)
+	if prev, err := previousTag(ctx); err == nil {
+		change, err := moduleChange(ctx.git(), Module{Dir: module, Path: modPath}, nil)
+		if err == nil && change.Commits == 0 {
+			ctx.Log("  Warning: no commits touch %s since %s", module, prev)
+		}
+		ctx.Log("  Previous: %s", prev)
+	}
+	return nil
+}
+
+// checkMajorVersion checks that a module path's major version suffix
+// matches the version: example.com/m/v2 for v2.x.y, and none for v0 and v1.
+func checkMajorVersion(modPath string, v semver.Version) error {
+	suffix := 0
+	if i := strings.LastIndex(modPath, "/v"); i >= 0 {
+		if n, err := strconv.Atoi(modPath[i+2:]); err == nil && n >= 2 {
+			suffix = n
+		}
+	}
+
+	want := 0
+	if v.Major >= 2 {
+		want = v.Major
+	}
+	switch {
+	case suffix == want:
+		return nil
+	case want == 0:
+		return fmt.Errorf("module %s has major version suffix /v%d but %s has none", modPath, suffix, v)
+	default:
+		return fmt.Errorf("module %s needs the major version suffix /v%d for %s", modPath, want, v)
+	}
+}
+
+// Modules returns the Go modules in the repository at dir, ordered by directory.
+func Modules(dir string) ([]Module, error) {
+	detections, err := detect.Detect(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect modules: %w", err)
+	}
+
+	var modules []Module
+	for _, d := range detect.GetByLanguage(detections, detect.Go) {
+		rel, err := filepath.Rel(dir, d.Path)
+		if err != nil {
+			return nil, err
+		}
+		modFile, err := gomod.ReadFile(filepath.Join(d.Path, gomod.FileName))
+		if err != nil {
+			return nil, err
+		}
+		modules = append(modules, Module{Dir: filepath.ToSlash(rel), Path: modFile.Module})
+	}
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Dir < modules[j].Dir })
+	return modules, nil
+}
+
+// ChangedModules returns the Go modules in the repository at dir with the
+// commits touching each since its latest tag. Commits in a nested module
+// count only towards the nested module.
+func ChangedModules(dir string) ([]ModuleChange, error) {
+	modules, err := Modules(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	g := git.New(dir)
+	changes := make([]ModuleChange, 0, len(modules))
+	for _, m := range modules {
+		change, err := moduleChange(g, m, modules)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// moduleChange counts the commits touching m since its latest tag,
+// excluding the directories of modules nested in it.
+func moduleChange(g *git.Git, m Module, modules []Module) (ModuleChange, error) {
+	change := ModuleChange{Dir: m.Dir, Path: m.Path}
+	if tag, err := g.LatestTagMatching(ModuleTag(m.Dir, "v[0-9]*")); err == nil {
+		change.LastTag = tag
+	}
+
+	revRange := "HEAD"
+	if change.LastTag != "" {
+		revRange = change.LastTag + "..HEAD"
+	}
+	n, err := g.CountCommits(revRange, modulePaths(m, modules)...)
+	if err != nil {
+		return change, fmt.Errorf("failed to count commits in %s: %w", m.Dir, err)
+	}
+	change.Commits = n
+	change.Changed = change.LastTag == "" || n > 0
+	return change, nil
+}
+
+// modulePaths returns pathspecs, relative to the repository root, matching
+// the files of m but not those of the modules nested in it.
+func modulePaths(m Module, modules []Module) []string {
+	paths := []string{m.Dir}
+	for _, other := range modules {
+		if other.Dir != m.Dir && (m.Dir == "." || strings.HasPrefix(other.Dir, m.Dir+"/")) {
+			paths = append(paths, ":(exclude)"+other.Dir)
+		}
+	}
+	return paths
+}
+
+// releasePaths returns pathspecs, usable from moduleDir, limiting commits to
+// those touching the released module. A whole-repository release leaves out
+// the Go modules in subdirectories, which are released with their own tags;
+// it returns nil if there are none.
+func releasePaths(ctx *Context) ([]string, error) {
+	module := ReleaseModule(ctx)
+	if module == "" {
+		module = "."
+	}
+	modules, err := Modules(ctx.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if module == "." && !slices.ContainsFunc(modules, func(m Module) bool { return m.Dir != "." }) {
+		return nil, nil
+	}
+
+	// Anchored at the repository root, since git resolves them from moduleDir
+	var paths []string
+	for _, p := range modulePaths(Module{Dir: module}, modules) {
+		if rest, ok := strings.CutPrefix(p, ":(exclude)"); ok {
+			paths = append(paths, ":(top,exclude)"+rest)
+		} else {
+			paths = append(paths, ":(top)"+strings.TrimPrefix(p, ".")) // ":(top)." matches nothing
+		}
+	}
+	return paths, nil
+}
+
+// previewModules lists, in a repository with several Go modules, which
+// modules changed since their last tag.
+func previewModules(ctx *Context) ([]actions.Proposal, error) {
+	changes, err := ChangedModules(ctx.Dir)
+	if err != nil || len(changes) < 2 {
+		return nil, err
+	}
+
+	var changed, unchanged []string
+	for _, c := range changes {
+		if c.Changed {
+			changed = append(changed, c.Summary())
+		} else {
+			unchanged = append(unchanged, c.Summary())
+		}
+	}
+
+	metadata := map[string]string{"release": ReleaseTag(ctx)}
+	if len(changed) > 0 {
+		metadata["changed"] = strings.Join(changed, "\n")
+	}
+	if len(unchanged) > 0 {
+		metadata["unchanged"] = strings.Join(unchanged, "\n")
+	}
+	return []actions.Proposal{{
+		Description: fmt.Sprintf("%d of %d Go modules changed since their last tag", len(changed), len(changes)),
+		Metadata:    metadata,
+	}}, nil
+}
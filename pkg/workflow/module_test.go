@@ -0,0 +1,321 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/agent-team-release/pkg/semver"
+)
+
+// initModuleRepo creates a repository with a root module tagged v1.0.0, an
+// sdk/go module tagged sdk/go/v0.1.0 and changed since, and an untagged
+// tools module.
+func initModuleRepo(t *testing.T) string {
+	dir, mainBranch := initBranchRepo(t)
+	for _, m := range []struct{ dir, path string }{
+		{".", "example.com/repo"},
+		{"sdk/go", "example.com/repo/sdk/go"},
+		{"tools", "example.com/repo/tools"},
+	} {
+		if err := os.MkdirAll(filepath.Join(dir, m.dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		goMod := "module " + m.path + "\n\ngo 1.24\n"
+		if err := os.WriteFile(filepath.Join(dir, m.dir, "go.mod"), []byte(goMod), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gitRun(t, dir, "add", "-A")
+	gitRun(t, dir, "commit", "-m", "build: add modules")
+	gitRun(t, dir, "tag", "-f", "-a", "v1.0.0", "-m", "Release v1.0.0")
+	gitRun(t, dir, "tag", "-a", "sdk/go/v0.1.0", "-m", "Release sdk/go/v0.1.0")
+	commitFile(t, dir, "sdk/go/sdk.go", "package sdk\n", "feat(sdk): client")
+	gitRun(t, dir, "push", "-f", "origin", mainBranch, "--tags")
+	return dir
+}
+
+func TestModuleTag(t *testing.T) {
+	tests := []struct {
+		module, want string
+	}{
+		{"", "v1.2.3"},
+		{".", "v1.2.3"},
+		{"sdk/go", "sdk/go/v1.2.3"},
+		{"./sdk/go/", "sdk/go/v1.2.3"},
+		{"sdk/go/v2", "sdk/go/v1.2.3"},
+		{"v2", "v1.2.3"},
+		{"tools/v1", "tools/v1/v1.2.3"},
+	}
+	for _, tt := range tests {
+		if got := ModuleTag(tt.module, "v1.2.3"); got != tt.want {
+			t.Errorf("ModuleTag(%q) = %q, want %q", tt.module, got, tt.want)
+		}
+	}
+}
+
+func TestModuleVersions(t *testing.T) {
+	tags := []string{"v1.0.0", "sdk/go/v0.2.0", "sdk/go/v0.1.0", "sdk/v9.0.0", "tools/v0.1.0"}
+	if got := strings.Join(moduleVersions(tags, "sdk/go"), ","); got != "v0.2.0,v0.1.0" {
+		t.Errorf("moduleVersions(sdk/go) = %s", got)
+	}
+	if got := strings.Join(moduleVersions(tags, ""), ","); got != "v1.0.0" {
+		t.Errorf("moduleVersions(root) = %s", got)
+	}
+}
+
+func TestCheckMajorVersion(t *testing.T) {
+	tests := []struct {
+		path, version string
+		ok            bool
+	}{
+		{"example.com/m", "v1.4.0", true},
+		{"example.com/m", "v0.1.0", true},
+		{"example.com/m/v2", "v2.0.1", true},
+		{"example.com/m", "v2.0.0", false},
+		{"example.com/m/v2", "v1.9.0", false},
+		{"example.com/m/v2", "v3.0.0", false},
+		{"example.com/vendor", "v1.0.0", true},
+	}
+	for _, tt := range tests {
+		v, err := semver.Parse(tt.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := checkMajorVersion(tt.path, v); (err == nil) != tt.ok {
+			t.Errorf("checkMajorVersion(%s, %s) = %v, want ok=%v", tt.path, tt.version, err, tt.ok)
+		}
+	}
+}
+
+func TestChangedModules(t *testing.T) {
+	dir := initModuleRepo(t)
+
+	changes, err := ChangedModules(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ModuleChange{
+		{Dir: ".", Path: "example.com/repo", LastTag: "v1.0.0", Commits: 0, Changed: false},
+		{Dir: "sdk/go", Path: "example.com/repo/sdk/go", LastTag: "sdk/go/v0.1.0", Commits: 1, Changed: true},
+		{Dir: "tools", Path: "example.com/repo/tools", Commits: 1, Changed: true},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("ChangedModules() = %+v", changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("module %d = %+v, want %+v", i, changes[i], want[i])
+		}
+	}
+}
+
+func TestSetModule(t *testing.T) {
+	dir := initModuleRepo(t)
+
+	ctx := NewContext(dir, "v0.2.0")
+	if err := SetModule(ctx, "./sdk/go"); err != nil {
+		t.Fatal(err)
+	}
+	if ReleaseModule(ctx) != "sdk/go" || ReleaseTag(ctx) != "sdk/go/v0.2.0" {
+		t.Errorf("module = %q, tag = %q", ReleaseModule(ctx), ReleaseTag(ctx))
+	}
+
+	root := NewContext(dir, "v1.1.0")
+	if err := SetModule(root, "."); err != nil || ReleaseTag(root) != "v1.1.0" {
+		t.Errorf("SetModule(.) = %v, tag %q", err, ReleaseTag(root))
+	}
+
+	for _, module := range []string{"docs", "../other"} {
+		if err := SetModule(NewContext(dir, "v1.0.0"), module); err == nil {
+			t.Errorf("SetModule(%s) should fail", module)
+		}
+	}
+}
+
+func TestModuleRelease(t *testing.T) {
+	dir := initModuleRepo(t)
+
+	ctx := NewContext(dir, "v0.2.0")
+	ctx.SkipChecks = true
+	ctx.SkipCI = true
+	if err := SetModule(ctx, "sdk/go"); err != nil {
+		t.Fatal(err)
+	}
+
+	result := NewRunner().Run(ReleaseWorkflow(ctx.Version), ctx)
+	if !result.Success {
+		t.Fatalf("release failed: %v\n%s", result.Error, result.Output)
+	}
+
+	remoteTags := gitRun(t, dir, "ls-remote", "--tags", "origin")
+	if !strings.Contains(remoteTags, "refs/tags/sdk/go/v0.2.0") {
+		t.Errorf("tag sdk/go/v0.2.0 not pushed:\n%s", remoteTags)
+	}
+	if strings.Contains(remoteTags, "refs/tags/v0.2.0") {
+		t.Error("root tag v0.2.0 should not be created")
+	}
+	if !strings.Contains(result.Output, "Previous: sdk/go/v0.1.0") {
+		t.Errorf("output missing previous module tag:\n%s", result.Output)
+	}
+
+	// The module's tags are separate from the root module's
+	if next, err := NextCandidate(dir, "sdk/go", "v0.3.0"); err != nil || next != "v0.3.0-rc.1" {
+		t.Errorf("NextCandidate(sdk/go) = %q, %v", next, err)
+	}
+	if _, err := NextCandidate(dir, "sdk/go", "v0.2.0"); err == nil {
+		t.Error("NextCandidate(sdk/go, v0.2.0) should fail for a released version")
+	}
+}
+
+func TestModuleRelease_MajorVersion(t *testing.T) {
+	dir := initModuleRepo(t)
+
+	ctx := NewContext(dir, "v2.0.0")
+	ctx.SkipChecks = true
+	ctx.SkipCI = true
+	if err := SetModule(ctx, "sdk/go"); err != nil {
+		t.Fatal(err)
+	}
+
+	result := NewRunner().Run(ReleaseWorkflow(ctx.Version), ctx)
+	if result.Success || !strings.Contains(result.Output, "major version suffix /v2") {
+		t.Errorf("release of v2 without /v2 suffix should fail:\n%s", result.Output)
+	}
+}
+
+func TestModuleRelease_DryRunPlan(t *testing.T) {
+	dir := initModuleRepo(t)
+
+	ctx := NewContext(dir, "v0.2.0")
+	ctx.SkipChecks = true
+	ctx.SkipCI = true
+	if err := SetModule(ctx, "sdk/go"); err != nil {
+		t.Fatal(err)
+	}
+	runner := NewRunner()
+	runner.DryRun = true
+
+	result := runner.Run(ReleaseWorkflow(ctx.Version), ctx)
+	if !result.Success {
+		t.Fatalf("dry run failed: %v\n%s", result.Error, result.Output)
+	}
+
+	plan := result.PlanSummary()
+	for _, want := range []string{
+		"2 of 3 Go modules changed since their last tag",
+		"sdk/go: 1 commit(s) since sdk/go/v0.1.0",
+		"tools: never released",
+		".: unchanged since v1.0.0",
+		"Create tag sdk/go/v0.2.0",
+	} {
+		if !strings.Contains(plan, want) {
+			t.Errorf("plan missing %q:\n%s", want, plan)
+		}
+	}
+}
+
+func TestModuleRelease_BumpsVersions(t *testing.T) {
+	dir := initModuleRepo(t)
+	commitFile(t, dir, "version.go", "package repo\n\nconst Version = \"v1.0.0\"\n", "feat: version")
+	commitFile(t, dir, "sdk/go/version.go", "package sdk\n\nconst Version = \"v0.1.0\"\n", "feat(sdk): version")
+
+	ctx := NewContext(dir, "v0.2.0")
+	ctx.SkipChecks = true
+	ctx.SkipCI = true
+	if err := SetModule(ctx, "sdk/go"); err != nil {
+		t.Fatal(err)
+	}
+
+	result := NewRunner().Run(ReleaseWorkflow(ctx.Version), ctx)
+	if !result.Success {
+		t.Fatalf("release failed: %v\n%s", result.Error, result.Output)
+	}
+
+	// The bump is part of the release commit, and only touches the module
+	if files := gitRun(t, dir, "show", "--name-only", "--format=", "sdk/go/v0.2.0^{commit}"); files != "sdk/go/version.go" {
+		t.Errorf("release commit files = %q", files)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "sdk/go/version.go")); !strings.Contains(string(data), `"v0.2.0"`) {
+		t.Errorf("sdk/go/version.go not bumped:\n%s", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "version.go")); !strings.Contains(string(data), `"v1.0.0"`) {
+		t.Errorf("root version.go changed:\n%s", data)
+	}
+}
+
+func TestModuleRelease_ChangelogScopedToModule(t *testing.T) {
+	dir := initModuleRepo(t)
+	if err := os.MkdirAll(filepath.Join(dir, "sdk/go/v2"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sdk/go/v2/go.mod"), []byte("module example.com/repo/sdk/go/v2\n\ngo 1.24\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, dir, "add", "-A")
+	gitRun(t, dir, "-c", "user.name=Nested Author", "-c", "user.email=nested@example.com", "commit", "-m", "feat(sdk/v2): nested module")
+	commitFile(t, dir, "sdk/go/CHANGELOG.json", branchChangelog, "docs(sdk): changelog")
+	if err := os.WriteFile(filepath.Join(dir, "tools/tool.go"), []byte("package tools\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, dir, "add", "-A")
+	gitRun(t, dir, "-c", "user.name=Tools Author", "-c", "user.email=tools@example.com", "commit", "-m", "feat(tools): unrelated tool")
+	gitRun(t, dir, "push")
+
+	ctx := NewContext(dir, "v0.2.0")
+	ctx.SkipChecks = true
+	ctx.SkipCI = true
+	if err := SetModule(ctx, "sdk/go"); err != nil {
+		t.Fatal(err)
+	}
+	result := NewRunner().Run(ReleaseWorkflow(ctx.Version), ctx)
+	if !result.Success {
+		t.Fatalf("release failed: %v\n%s", result.Error, result.Output)
+	}
+
+	// Only the module's own commits and authors are in its entry
+	data := gitRun(t, dir, "show", "sdk/go/v0.2.0:sdk/go/CHANGELOG.json")
+	for _, want := range []string{`"version": "v0.2.0"`, "Client", "Test User"} {
+		if !strings.Contains(data, want) {
+			t.Errorf("sdk/go/CHANGELOG.json missing %q:\n%s", want, data)
+		}
+	}
+	for _, unwanted := range []string{"Unrelated tool", "Tools Author", "Nested module", "Nested Author"} {
+		if strings.Contains(data, unwanted) {
+			t.Errorf("sdk/go/CHANGELOG.json credits another module with %q:\n%s", unwanted, data)
+		}
+	}
+}
+
+func TestRootRelease_ChangelogSinceRootTag(t *testing.T) {
+	dir := initModuleRepo(t)
+	commitFile(t, dir, "root.go", "package repo\n", "fix: first root fix")
+	gitRun(t, dir, "tag", "-a", "sdk/go/v0.2.0", "-m", "Release sdk/go/v0.2.0")
+	commitFile(t, dir, "root.go", "package repo\n\nconst x = 1\n", "fix: second root fix")
+	commitFile(t, dir, "tools/tool.go", "package tools\n", "feat(tools): unrelated tool")
+	gitRun(t, dir, "push", "--tags")
+
+	ctx := NewContext(dir, "v1.1.0")
+	ctx.SkipChecks = true
+	ctx.SkipCI = true
+	result := NewRunner().Run(ReleaseWorkflow(ctx.Version), ctx)
+	if !result.Success {
+		t.Fatalf("release failed: %v\n%s", result.Error, result.Output)
+	}
+
+	// Both root fixes since v1.0.0, not the module tag between them, and
+	// no commits of the nested modules
+	data := gitRun(t, dir, "show", "v1.1.0:CHANGELOG.json")
+	for _, want := range []string{`"version": "v1.1.0"`, "First root fix", "Second root fix"} {
+		if !strings.Contains(data, want) {
+			t.Errorf("CHANGELOG.json missing %q:\n%s", want, data)
+		}
+	}
+	for _, unwanted := range []string{"Client", "Unrelated tool"} {
+		if strings.Contains(data, unwanted) {
+			t.Errorf("CHANGELOG.json includes a nested module's commit %q:\n%s", unwanted, data)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package workflow
+
+import (
+	"fmt"
+
+	"github.com/agentplexus/agent-team-release/pkg/git"
+	"github.com/agentplexus/agent-team-release/pkg/semver"
+)
+
+// firstVersion is recommended for a repository without release tags.
+const firstVersion = "v0.1.0"
+
+// Recommendation is the next version recommended from the conventional
+// commits since the latest release tag.
+type Recommendation struct {
+	Current string         `json:"current,omitempty" toon:"current,omitempty"` // Latest release tag ("" if none)
+	Next    string         `json:"next,omitempty" toon:"next,omitempty"`       // Recommended version ("" if nothing to release)
+	Bump    string         `json:"bump" toon:"bump"`                           // "major", "minor", "patch" or "none"
+	Reason  string         `json:"reason" toon:"reason"`
+	Total   int            `json:"total" toon:"total"`                     // Commits since the latest tag
+	Commits []CommitBump   `json:"commits" toon:"commits"`                 // Commits driving the bump
+	Types   map[string]int `json:"types,omitempty" toon:"types,omitempty"` // Commits by conventional type
+}
+
+// CommitBump is a commit and the version bump it calls for.
+type CommitBump struct {
+	Hash     string `json:"hash" toon:"hash"`
+	Type     string `json:"type,omitempty" toon:"type,omitempty"`
+	Scope    string `json:"scope,omitempty" toon:"scope,omitempty"`
+	Breaking bool   `json:"breaking,omitempty" toon:"breaking,omitempty"`
+	Subject  string `json:"subject" toon:"subject"`
+	Bump     string `json:"bump" toon:"bump"`
+}
+
+// commitBump returns the bump a conventional commit calls for: breaking
+// changes are major, features minor, and fixes and performance improvements
+// patches. Other commits call for no bump on their own.
+func commitBump(c git.Conventional) semver.Bump {
+	switch {
+	case c.Breaking:
+		return semver.BumpMajor
+	case c.Type == "feat":
+		return semver.BumpMinor
+	case c.Type == "fix" || c.Type == "perf":
+		return semver.BumpPatch
+	default:
+		return semver.BumpNone
+	}
+}
+
+// NextVersion recommends the next version from the commits since the
+// latest release tag (see semver.Version.Next for the 0.x rules). Commits
+// that are neither breaking, features nor fixes still call for a patch
+// release. Without release tags, the first release is v0.1.0.
+func NextVersion(dir string) (*Recommendation, error) {
+	g := git.New(dir)
+	tags, err := g.AllTags()
+	if err != nil {
+		return nil, err
+	}
+	if len(moduleVersions(tags, "")) == 0 {
+		total, err := g.CountCommits("HEAD")
+		if err != nil {
+			return nil, err
+		}
+		return &Recommendation{
+			Next:    firstVersion,
+			Bump:    semver.BumpMinor.String(),
+			Reason:  "no release tags yet; first release",
+			Total:   total,
+			Commits: []CommitBump{},
+		}, nil
+	}
+
+	current, err := g.LatestTagMatching("v[0-9]*")
+	if err != nil {
+		return nil, err
+	}
+	v, err := semver.Parse(current)
+	if err != nil {
+		return nil, fmt.Errorf("latest tag: %w", err)
+	}
+
+	commits, err := g.Commits(current, "HEAD", git.LogOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &Recommendation{Current: current, Types: make(map[string]int)}
+	bump := semver.BumpNone
+	var all []CommitBump
+	for _, c := range commits {
+		b := commitBump(c.Conventional)
+		bump = max(bump, b)
+
+		typ := c.Type
+		if typ == "" {
+			typ = "other"
+		}
+		rec.Types[typ]++
+		all = append(all, CommitBump{
+			Hash:     c.Hash,
+			Type:     c.Type,
+			Scope:    c.Scope,
+			Breaking: c.Breaking,
+			Subject:  c.Subject,
+			Bump:     b.String(),
+		})
+	}
+	rec.Total = len(all)
+
+	if rec.Total == 0 {
+		rec.Bump = semver.BumpNone.String()
+		rec.Reason = "no commits since " + current + "; nothing to release"
+		rec.Commits = []CommitBump{}
+		return rec, nil
+	}
+	if bump == semver.BumpNone {
+		bump = semver.BumpPatch
+	}
+
+	// The commits driving the bump are those calling for it; if none do,
+	// the other commits together call for a patch
+	rec.Commits = []CommitBump{}
+	for _, c := range all {
+		if c.Bump == bump.String() {
+			rec.Commits = append(rec.Commits, c)
+		}
+	}
+	rec.Bump = bump.String()
+	rec.Next = v.Next(bump).String()
+	rec.Reason = bumpReason(v, bump, len(rec.Commits))
+	if len(rec.Commits) == 0 {
+		rec.Commits = all
+		rec.Reason = fmt.Sprintf("no features or fixes; %d other commit(s)", len(all))
+	}
+	return rec, nil
+}
+
+// bumpReason explains a bump from the version v driven by n commits.
+func bumpReason(v semver.Version, bump semver.Bump, n int) string {
+	var reason string
+	switch bump {
+	case semver.BumpMajor:
+		reason = fmt.Sprintf("%d breaking change(s)", n)
+		if v.Major == 0 {
+			reason += "; while the major version is 0, breaking changes bump the minor version"
+		}
+	case semver.BumpMinor:
+		reason = fmt.Sprintf("%d new feature(s)", n)
+	default:
+		if n == 1 {
+			reason = "1 fix"
+		} else {
+			reason = fmt.Sprintf("%d fixes", n)
+		}
+	}
+	if v.Prerelease != "" && v.Next(bump) == v.Release() {
+		reason += fmt.Sprintf("; %s already includes them, so its release is next", v)
+	}
+	return reason
+}
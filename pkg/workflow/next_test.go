@@ -0,0 +1,61 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNextVersion(t *testing.T) {
+	dir := initTestRepo(t)
+
+	rec, err := NextVersion(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Next != "v0.1.0" || rec.Current != "" {
+		t.Errorf("untagged: %+v", rec)
+	}
+
+	gitRun(t, dir, "tag", "-a", "v0.8.0", "-m", "Release v0.8.0")
+	gitRun(t, dir, "tag", "-a", "sdk/go/v3.0.0", "-m", "Release sdk/go/v3.0.0")
+	if rec, err = NextVersion(dir); err != nil || rec.Next != "" || rec.Bump != "none" {
+		t.Errorf("no commits: %+v, %v", rec, err)
+	}
+
+	commitFile(t, dir, "a.txt", "a", "docs: guide")
+	if rec, _ = NextVersion(dir); rec.Next != "v0.8.1" || !strings.Contains(rec.Reason, "no features or fixes") {
+		t.Errorf("docs only: %+v", rec)
+	}
+
+	commitFile(t, dir, "b.txt", "b", "fix(cli): crash")
+	commitFile(t, dir, "c.txt", "c", "feat: streaming")
+	commitFile(t, dir, "d.txt", "d", "feat(api): batch")
+	rec, _ = NextVersion(dir)
+	if rec.Next != "v0.9.0" || rec.Bump != "minor" || rec.Reason != "2 new feature(s)" || rec.Total != 4 {
+		t.Errorf("features: %+v", rec)
+	}
+	if len(rec.Commits) != 2 || rec.Commits[0].Subject != "feat(api): batch" || rec.Commits[0].Scope != "api" {
+		t.Errorf("driving commits = %+v", rec.Commits)
+	}
+	if rec.Types["feat"] != 2 || rec.Types["fix"] != 1 || rec.Types["docs"] != 1 {
+		t.Errorf("types = %v", rec.Types)
+	}
+
+	commitFile(t, dir, "e.txt", "e", "refactor: config\n\nBREAKING CHANGE: config moved")
+	rec, _ = NextVersion(dir)
+	if rec.Next != "v0.9.0" || rec.Bump != "major" || !strings.Contains(rec.Reason, "while the major version is 0") {
+		t.Errorf("0.x breaking: %+v", rec)
+	}
+
+	gitRun(t, dir, "tag", "-a", "v1.0.0", "-m", "Release v1.0.0")
+	commitFile(t, dir, "f.txt", "f", "feat!: drop v1 API")
+	if rec, _ = NextVersion(dir); rec.Next != "v2.0.0" || rec.Reason != "1 breaking change(s)" {
+		t.Errorf("1.x breaking: %+v", rec)
+	}
+
+	gitRun(t, dir, "tag", "-a", "v2.0.0-rc.1", "-m", "Release v2.0.0-rc.1")
+	commitFile(t, dir, "g.txt", "g", "fix: typo")
+	if rec, _ = NextVersion(dir); rec.Next != "v2.0.0" || !strings.Contains(rec.Reason, "its release is next") {
+		t.Errorf("after candidate: %+v", rec)
+	}
+}
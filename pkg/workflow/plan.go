@@ -0,0 +1,182 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/agentplexus/agent-team-release/pkg/actions"
+)
+
+// plannedFilePrefix marks Data keys recording files a dry run would change,
+// so the commit preview can list them. One key per file keeps concurrent
+// steps from overwriting each other's entries.
+const plannedFilePrefix = "planned_file:"
+
+// PlanItem is one change a dry run would make.
+type PlanItem struct {
+	Step        string            `json:"step" toon:"step"`
+	Description string            `json:"description" toon:"description"`
+	FilePath    string            `json:"file_path,omitempty" toon:"file_path,omitempty"`
+	Diff        string            `json:"diff,omitempty" toon:"diff,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty" toon:"metadata,omitempty"`
+}
+
+// planStep records a step's preview in its result during a dry run.
+// Preview failures are logged, not fatal, since the plan is advisory.
+func planStep(step *Step, ctx *Context, result *StepResult) {
+	proposals, err := step.Preview(ctx)
+	if err != nil {
+		ctx.Log("  Plan unavailable: %v", err)
+		return
+	}
+	for _, p := range proposals {
+		if p.FilePath != "" && p.OldContent != p.NewContent {
+			ctx.Data[plannedFilePrefix+p.FilePath] = "true"
+		}
+	}
+	result.Plan = append(result.Plan, proposals...)
+}
+
+// Plan returns the changes the workflow's steps would make, in step order.
+// It is populated by dry runs.
+func (wr *WorkflowResult) Plan() []PlanItem {
+	var items []PlanItem
+	var collect func(steps []StepResult)
+	collect = func(steps []StepResult) {
+		for _, step := range steps {
+			for _, p := range step.Plan {
+				items = append(items, PlanItem{
+					Step:        step.Name,
+					Description: p.Description,
+					FilePath:    p.FilePath,
+					Diff:        p.Diff(),
+					Metadata:    p.Metadata,
+				})
+			}
+			collect(step.SubSteps)
+		}
+	}
+	collect(wr.Steps)
+	return items
+}
+
+// PlanSummary returns the dry-run plan as text, or "" if there is none.
+func (wr *WorkflowResult) PlanSummary() string {
+	items := wr.Plan()
+	if len(items) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("=== Release Plan ===\n")
+	for _, item := range items {
+		sb.WriteString(fmt.Sprintf("\n[%s] %s\n", item.Step, item.Description))
+
+		keys := make([]string, 0, len(item.Metadata))
+		for k := range item.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			v := item.Metadata[k]
+			if strings.Contains(v, "\n") {
+				sb.WriteString(fmt.Sprintf("  %s:\n", k))
+				for _, line := range strings.Split(strings.TrimRight(v, "\n"), "\n") {
+					sb.WriteString(fmt.Sprintf("    %s\n", line))
+				}
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("  %s: %s\n", k, v))
+		}
+
+		if item.Diff != "" {
+			sb.WriteString(item.Diff)
+		}
+	}
+	return sb.String()
+}
+
+// plannedFiles returns the files recorded by earlier dry-run previews.
+func plannedFiles(ctx *Context) []string {
+	var files []string
+	for k := range ctx.Data {
+		if strings.HasPrefix(k, plannedFilePrefix) {
+			files = append(files, strings.TrimPrefix(k, plannedFilePrefix))
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// previewChangelog renders the changelog the changelog step would write.
+func previewChangelog(ctx *Context) ([]actions.Proposal, error) {
+	action := &actions.ChangelogAction{}
+	since, _ := previousTag(ctx)
+	paths, err := releasePaths(ctx)
+	if err != nil {
+		return nil, err
+	}
+	proposals, err := action.Propose(moduleDir(ctx), actions.Options{
+		Since:   since,
+		Paths:   paths,
+		Version: ctx.Version,
+		Context: ctx.Ctx,
+	})
+	return inModule(ctx, proposals), err
+}
+
+// previewRoadmap renders the roadmap the roadmap step would write.
+func previewRoadmap(ctx *Context) ([]actions.Proposal, error) {
+	action := &actions.RoadmapAction{}
+	return action.Propose(ctx.Dir, actions.Options{Version: ctx.Version, Context: ctx.Ctx})
+}
+
+// previewReadme computes the README changes the readme step would make.
+func previewReadme(ctx *Context) ([]actions.Proposal, error) {
+	action := &actions.ReadmeAction{}
+	proposals, err := action.Propose(moduleDir(ctx), actions.Options{Version: ctx.Version, Context: ctx.Ctx})
+	return inModule(ctx, proposals), err
+}
+
+// previewBump computes the version field changes the bump step would make.
+func previewBump(ctx *Context) ([]actions.Proposal, error) {
+	action := &actions.BumpAction{}
+	proposals, err := action.Propose(moduleDir(ctx), actions.Options{Version: ctx.Version, Context: ctx.Ctx})
+	return inModule(ctx, proposals), err
+}
+
+// previewCommit describes the release commit: its message and the files it
+// would include, counting files that earlier steps plan to change.
+func previewCommit(ctx *Context) ([]actions.Proposal, error) {
+	status, err := ctx.git().Status()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	for _, group := range [][]string{status.Staged, status.Modified, status.Untracked, plannedFiles(ctx)} {
+		for _, f := range group {
+			if !seen[f] {
+				seen[f] = true
+				files = append(files, f)
+			}
+		}
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+	sort.Strings(files)
+
+	message := releaseCommitMessage(ReleaseTag(ctx))
+	ctx.Data["planned_commit"] = message
+
+	return []actions.Proposal{{
+		Description: fmt.Sprintf("Commit %d file(s): %s", len(files), message),
+		Metadata: map[string]string{
+			"message": message,
+			"files":   strings.Join(files, "\n"),
+		},
+	}}, nil
+}
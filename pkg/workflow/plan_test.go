@@ -0,0 +1,120 @@
+package workflow
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/agent-team-release/pkg/actions"
+)
+
+func TestRunnerRun_DryRunPlan(t *testing.T) {
+	ran := false
+	wf := &Workflow{
+		Name: "Plan",
+		Steps: []Step{
+			{Name: "Changelog", Type: StepTypeFunc, Func: func(ctx *Context) error {
+				ran = true
+				return nil
+			}, Preview: func(ctx *Context) ([]actions.Proposal, error) {
+				return []actions.Proposal{{
+					Description: "Update changelog",
+					FilePath:    "CHANGELOG.md",
+					OldContent:  "# Changelog\n",
+					NewContent:  "# Changelog\n\n## v1.0.0\n",
+				}}, nil
+			}},
+			{Name: "Broken", Type: StepTypeFunc, Func: func(ctx *Context) error { return nil },
+				Preview: func(ctx *Context) ([]actions.Proposal, error) {
+					return nil, errors.New("tool missing")
+				}},
+			{Name: "Commit", Type: StepTypeFunc, Func: func(ctx *Context) error { return nil },
+				Preview: func(ctx *Context) ([]actions.Proposal, error) {
+					files := strings.Join(plannedFiles(ctx), ",")
+					return []actions.Proposal{{Description: "Commit", Metadata: map[string]string{"files": files}}}, nil
+				}},
+		},
+	}
+
+	runner := NewRunner()
+	runner.DryRun = true
+	result := runner.Run(wf, NewContext("/tmp", "v1.0.0"))
+
+	if !result.Success {
+		t.Fatalf("Workflow should succeed even if a preview fails: %s", result.Output)
+	}
+	if !ran {
+		t.Error("step functions should still run in dry-run mode")
+	}
+	if !strings.Contains(result.Output, "Plan unavailable: tool missing") {
+		t.Error("preview failure should be logged")
+	}
+
+	plan := result.Plan()
+	if len(plan) != 2 {
+		t.Fatalf("expected 2 plan items, got %d: %+v", len(plan), plan)
+	}
+	if plan[0].Step != "Changelog" || !strings.Contains(plan[0].Diff, "+## v1.0.0") {
+		t.Errorf("first item should carry the changelog diff: %+v", plan[0])
+	}
+	if plan[1].Metadata["files"] != "CHANGELOG.md" {
+		t.Errorf("commit preview should see planned files, got %q", plan[1].Metadata["files"])
+	}
+
+	summary := result.PlanSummary()
+	if !strings.Contains(summary, "=== Release Plan ===") || !strings.Contains(summary, "--- a/CHANGELOG.md") {
+		t.Errorf("unexpected plan summary:\n%s", summary)
+	}
+	if len(result.ToJSON().Plan) != 2 {
+		t.Error("JSON result should include the plan")
+	}
+}
+
+func TestRunnerRun_NoPlanWithoutDryRun(t *testing.T) {
+	previewed := false
+	wf := &Workflow{
+		Name: "Plan",
+		Steps: []Step{
+			{Name: "Step", Type: StepTypeFunc, Func: func(ctx *Context) error { return nil },
+				Preview: func(ctx *Context) ([]actions.Proposal, error) {
+					previewed = true
+					return nil, nil
+				}},
+		},
+	}
+
+	result := NewRunner().Run(wf, NewContext("/tmp", "v1.0.0"))
+	if previewed || result.PlanSummary() != "" {
+		t.Error("previews should only run in dry-run mode")
+	}
+}
+
+func TestPreviewCommit(t *testing.T) {
+	dir := initTestRepo(t)
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := NewContext(dir, "v1.2.0")
+	ctx.Data[plannedFilePrefix+"CHANGELOG.md"] = "true"
+
+	proposals, err := previewCommit(ctx)
+	if err != nil {
+		t.Fatalf("previewCommit() error: %v", err)
+	}
+	if len(proposals) != 1 {
+		t.Fatalf("expected 1 proposal, got %d", len(proposals))
+	}
+	meta := proposals[0].Metadata
+	if meta["message"] != "chore(release): v1.2.0" {
+		t.Errorf("message = %q", meta["message"])
+	}
+	if meta["files"] != "CHANGELOG.md\nnotes.txt" {
+		t.Errorf("files = %q", meta["files"])
+	}
+	if ctx.Data["planned_commit"] != "chore(release): v1.2.0" {
+		t.Error("commit preview should record the planned commit for the push preview")
+	}
+}
@@ -0,0 +1,52 @@
+package workflow
+
+import (
+	"sort"
+	"sync"
+)
+
+// registry holds step implementations that can be referenced by name.
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Step)
+)
+
+// RegisterStep registers a step implementation under a reference name,
+// replacing any existing registration.
+func RegisterStep(ref string, step Step) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	step.Ref = ref
+	registry[ref] = step
+}
+
+// LookupStep returns a copy of the step registered under ref.
+func LookupStep(ref string) (Step, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	step, ok := registry[ref]
+	return step, ok
+}
+
+// StepRefs returns the names of all registered steps, sorted.
+func StepRefs() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	refs := make([]string, 0, len(registry))
+	for ref := range registry {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	return refs
+}
+
+// builtin returns the registered step for ref with the given dependencies.
+// It panics if ref is not registered, since built-in workflows are static.
+func builtin(ref string, dependsOn ...string) Step {
+	step, ok := LookupStep(ref)
+	if !ok {
+		panic("workflow: unknown step " + ref)
+	}
+	step.DependsOn = append([]string(nil), dependsOn...)
+	return step
+}
@@ -3,84 +3,148 @@ package workflow
 import (
 	"fmt"
 	"os/exec"
+	"strings"
 	"time"
 
 	"github.com/agentplexus/agent-team-release/pkg/actions"
+	"github.com/agentplexus/agent-team-release/pkg/changelog"
 	"github.com/agentplexus/agent-team-release/pkg/checks"
+	"github.com/agentplexus/agent-team-release/pkg/config"
 	"github.com/agentplexus/agent-team-release/pkg/detect"
-	"github.com/agentplexus/agent-team-release/pkg/git"
+	"github.com/agentplexus/agent-team-release/pkg/interactive"
 	"github.com/agentplexus/assistantkit/requirements"
 )
 
+// Built-in step references, usable from custom workflows and team specs.
+const (
+	StepValidateVersion = "validate-version"
+	StepCheckWorkingDir = "check-working-directory"
+	StepValidate        = "validate"
+	StepChangelog       = "changelog"
+	StepRoadmap         = "roadmap"
+	StepReadme          = "readme"
+	StepBump            = "bump"
+	StepCommit          = "commit"
+	StepPush            = "push"
+	StepWaitCI          = "wait-ci"
+	StepTag             = "tag"
+)
+
+func init() {
+	RegisterStep(StepValidateVersion, Step{
+		Name:        "Validate version",
+		Description: "Check version format and ensure it doesn't exist",
+		Type:        StepTypeFunc,
+		Required:    true,
+		Func:        validateVersion,
+		Preview:     previewModules,
+	})
+	RegisterStep(StepCheckWorkingDir, Step{
+		Name:        "Check working directory",
+		Description: "Ensure no uncommitted changes",
+		Type:        StepTypeFunc,
+		Required:    true,
+		Func:        checkWorkingDirectory,
+	})
+	RegisterStep(StepValidate, Step{
+		Name:        "Run validation checks",
+		Description: "Run build, test, lint, format checks",
+		Type:        StepTypeFunc,
+		Required:    true,
+		Func:        runValidationChecks,
+	})
+	RegisterStep(StepChangelog, Step{
+		Name:        "Generate changelog",
+		Description: "Update CHANGELOG.md with new entries",
+		Type:        StepTypeFunc,
+		Required:    false,
+		Func:        generateChangelog,
+		Preview:     previewChangelog,
+	})
+	RegisterStep(StepRoadmap, Step{
+		Name:        "Update roadmap",
+		Description: "Regenerate ROADMAP.md",
+		Type:        StepTypeFunc,
+		Required:    false,
+		Func:        updateRoadmap,
+		Preview:     previewRoadmap,
+	})
+	RegisterStep(StepReadme, Step{
+		Name:        "Update README",
+		Description: "Update version references and badges in README.md",
+		Type:        StepTypeFunc,
+		Required:    false,
+		Func:        updateReadme,
+		Preview:     previewReadme,
+	})
+	RegisterStep(StepBump, Step{
+		Name:        "Bump versions",
+		Description: "Set the version in package.json, Cargo.toml, pyproject.toml and version.go",
+		Type:        StepTypeFunc,
+		Required:    true,
+		Func:        bumpVersions,
+		Preview:     previewBump,
+	})
+	RegisterStep(StepCommit, Step{
+		Name:        "Create release commit",
+		Description: "Commit all changes with release message",
+		Type:        StepTypeFunc,
+		Required:    true,
+		Func:        createReleaseCommit,
+		Undo:        undoReleaseCommit,
+		Preview:     previewCommit,
+	})
+	RegisterStep(StepPush, Step{
+		Name:         "Push to remote",
+		Description:  "Push commits to origin",
+		Type:         StepTypeFunc,
+		Required:     true,
+		Func:         pushToRemote,
+		Undo:         undoPush,
+		Retry:        pushRetry,
+		Irreversible: true,
+		Preview:      previewPush,
+	})
+	RegisterStep(StepWaitCI, Step{
+		Name:        "Wait for CI",
+		Description: "Wait for CI checks to pass",
+		Type:        StepTypeFunc,
+		Required:    false,
+		Func:        waitForCI,
+		Retry:       ciRetry,
+	})
+	RegisterStep(StepTag, Step{
+		Name:         "Create tag",
+		Description:  "Create and push release tag",
+		Type:         StepTypeFunc,
+		Required:     true,
+		Func:         createTag,
+		Undo:         undoTag,
+		Irreversible: true,
+		Preview:      previewTag,
+	})
+}
+
 // ReleaseWorkflow creates a workflow for releasing a new version.
+// Validation runs on the bumped tree; changelog generation and roadmap and
+// README updates write separate files and run concurrently once it passes,
+// so no check sees a half-written file.
 func ReleaseWorkflow(version string) *Workflow {
 	return &Workflow{
 		Name:        "Release " + version,
 		Description: "Prepare and create release " + version,
 		Steps: []Step{
-			{
-				Name:        "Validate version",
-				Description: "Check version format and ensure it doesn't exist",
-				Type:        StepTypeFunc,
-				Required:    true,
-				Func:        validateVersion,
-			},
-			{
-				Name:        "Check working directory",
-				Description: "Ensure no uncommitted changes",
-				Type:        StepTypeFunc,
-				Required:    true,
-				Func:        checkWorkingDirectory,
-			},
-			{
-				Name:        "Run validation checks",
-				Description: "Run build, test, lint, format checks",
-				Type:        StepTypeFunc,
-				Required:    true,
-				Func:        runValidationChecks,
-			},
-			{
-				Name:        "Generate changelog",
-				Description: "Update CHANGELOG.md with new entries",
-				Type:        StepTypeFunc,
-				Required:    false,
-				Func:        generateChangelog,
-			},
-			{
-				Name:        "Update roadmap",
-				Description: "Regenerate ROADMAP.md",
-				Type:        StepTypeFunc,
-				Required:    false,
-				Func:        updateRoadmap,
-			},
-			{
-				Name:        "Create release commit",
-				Description: "Commit all changes with release message",
-				Type:        StepTypeFunc,
-				Required:    true,
-				Func:        createReleaseCommit,
-			},
-			{
-				Name:        "Push to remote",
-				Description: "Push commits to origin",
-				Type:        StepTypeFunc,
-				Required:    true,
-				Func:        pushToRemote,
-			},
-			{
-				Name:        "Wait for CI",
-				Description: "Wait for CI checks to pass",
-				Type:        StepTypeFunc,
-				Required:    false,
-				Func:        waitForCI,
-			},
-			{
-				Name:        "Create tag",
-				Description: "Create and push release tag",
-				Type:        StepTypeFunc,
-				Required:    true,
-				Func:        createTag,
-			},
+			builtin(StepValidateVersion),
+			builtin(StepCheckWorkingDir),
+			builtin(StepBump, "Validate version", "Check working directory"),
+			builtin(StepValidate, "Bump versions"),
+			builtin(StepChangelog, "Run validation checks"),
+			builtin(StepRoadmap, "Run validation checks"),
+			builtin(StepReadme, "Run validation checks"),
+			builtin(StepCommit, "Generate changelog", "Update roadmap", "Update README"),
+			builtin(StepPush, "Create release commit"),
+			builtin(StepWaitCI, "Push to remote"),
+			builtin(StepTag, "Wait for CI"),
 		},
 	}
 }
@@ -97,23 +161,31 @@ func validateVersion(ctx *Context) error {
 	}
 
 	// Check if tag already exists
-	g := git.New(ctx.Dir)
+	g := ctx.git()
+	releaseTag := ReleaseTag(ctx)
 	tags, err := g.AllTags()
 	if err == nil {
 		for _, tag := range tags {
-			if tag == ctx.Version {
-				return fmt.Errorf("tag %s already exists", ctx.Version)
+			if tag == releaseTag {
+				return fmt.Errorf("tag %s already exists", releaseTag)
 			}
 		}
 	}
 
+	if module := ReleaseModule(ctx); module != "" {
+		if err := validateModuleVersion(ctx, module); err != nil {
+			return err
+		}
+		ctx.Log("  Tag: %s", releaseTag)
+	}
+
 	ctx.Log("  Version: %s", ctx.Version)
 	return nil
 }
 
 // checkWorkingDirectory ensures there are no uncommitted changes.
 func checkWorkingDirectory(ctx *Context) error {
-	g := git.New(ctx.Dir)
+	g := ctx.git()
 
 	dirty, err := g.IsDirty()
 	if err != nil {
@@ -151,7 +223,8 @@ func runValidationChecks(ctx *Context) error {
 	}
 
 	// Detect languages to see if there's anything to check
-	detections, err := detect.Detect(ctx.Dir)
+	dir := moduleDir(ctx)
+	detections, err := detect.Detect(dir)
 	if err != nil {
 		return fmt.Errorf("failed to detect languages: %w", err)
 	}
@@ -172,7 +245,7 @@ func runValidationChecks(ctx *Context) error {
 	}
 
 	// Run releasekit validate (it auto-detects languages)
-	results, err := checks.RunReleasekit(ctx.Dir, opts)
+	results, err := checks.RunReleasekitContext(ctx.context(), dir, opts)
 	if err != nil {
 		return fmt.Errorf("releasekit failed: %w", err)
 	}
@@ -196,20 +269,32 @@ func runValidationChecks(ctx *Context) error {
 
 // generateChangelog updates the changelog.
 func generateChangelog(ctx *Context) error {
-	action := &actions.ChangelogAction{}
+	action := &actions.ChangelogAction{
+		Classify: func(d *changelog.Draft) error {
+			return ctx.ask(func(p interactive.Prompter) error {
+				return interactive.ClassifyChanges(p, d)
+			})
+		},
+	}
 
 	// Get latest tag for since
-	g := git.New(ctx.Dir)
-	since, _ := g.LatestTag()
+	since, _ := previousTag(ctx)
+	paths, err := releasePaths(ctx)
+	if err != nil {
+		return err
+	}
 
 	opts := actions.Options{
-		Since:   since,
-		Version: ctx.Version,
-		DryRun:  ctx.DryRun,
-		Verbose: ctx.Verbose,
+		Since:       since,
+		Paths:       paths,
+		Version:     ctx.Version,
+		DryRun:      ctx.DryRun,
+		Verbose:     ctx.Verbose,
+		Interactive: ctx.Interactive,
+		Context:     ctx.Ctx,
 	}
 
-	result := action.Run(ctx.Dir, opts)
+	result := action.Run(moduleDir(ctx), opts)
 	if !result.Success {
 		if result.Error != nil {
 			ctx.Log("  Warning: %v", result.Error)
@@ -228,7 +313,9 @@ func updateRoadmap(ctx *Context) error {
 
 	opts := actions.Options{
 		DryRun:  ctx.DryRun,
+		Version: ctx.Version,
 		Verbose: ctx.Verbose,
+		Context: ctx.Ctx,
 	}
 
 	result := action.Run(ctx.Dir, opts)
@@ -244,9 +331,60 @@ func updateRoadmap(ctx *Context) error {
 	return nil
 }
 
+// updateReadme updates version references and badges in README.md.
+func updateReadme(ctx *Context) error {
+	action := &actions.ReadmeAction{}
+
+	cfg, err := config.Load(ctx.Dir)
+	if err != nil {
+		ctx.Log("  Warning: error loading config: %v", err)
+	}
+
+	opts := actions.Options{
+		Version: ctx.Version,
+		DryRun:  ctx.DryRun,
+		Verbose: ctx.Verbose,
+		Config:  &cfg,
+		Context: ctx.Ctx,
+	}
+
+	result := action.Run(moduleDir(ctx), opts)
+	if !result.Success {
+		if result.Error != nil {
+			ctx.Log("  Warning: %v", result.Error)
+		}
+		// Don't fail the workflow for README issues
+		return nil
+	}
+
+	ctx.Log("  README checked")
+	return nil
+}
+
+// bumpVersions sets the version fields of the module's manifests to the
+// release version.
+func bumpVersions(ctx *Context) error {
+	action := &actions.BumpAction{}
+
+	result := action.Run(moduleDir(ctx), actions.Options{
+		Version: ctx.Version,
+		DryRun:  ctx.DryRun,
+		Verbose: ctx.Verbose,
+		Context: ctx.Ctx,
+	})
+	if !result.Success {
+		return fmt.Errorf("failed to bump versions: %w", result.Error)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(result.Output), "\n") {
+		ctx.Log("  %s", line)
+	}
+	return nil
+}
+
 // createReleaseCommit commits all changes with a release message.
 func createReleaseCommit(ctx *Context) error {
-	g := git.New(ctx.Dir)
+	g := ctx.git()
 
 	// Check if there are changes to commit
 	dirty, err := g.IsDirty()
@@ -254,28 +392,74 @@ func createReleaseCommit(ctx *Context) error {
 		return err
 	}
 
-	if !dirty {
+	// In a dry run, earlier steps only planned their file changes
+	if !dirty && !(ctx.DryRun && len(plannedFiles(ctx)) > 0) {
 		ctx.Log("  No changes to commit")
 		return nil
 	}
 
 	if ctx.DryRun {
-		ctx.Log("  [Dry run] Would create commit: chore(release): %s", ctx.Version)
+		ctx.Log("  [Dry run] Would create commit: %s", releaseCommitMessage(ReleaseTag(ctx)))
 		return nil
 	}
 
-	message := fmt.Sprintf("chore(release): %s", ctx.Version)
+	parent, err := g.CurrentCommit()
+	if err != nil {
+		return err
+	}
+
+	message := releaseCommitMessage(ReleaseTag(ctx))
 	if err := g.CommitAll(message, false); err != nil {
 		return fmt.Errorf("failed to create commit: %w", err)
 	}
 
+	sha, err := g.CurrentCommit()
+	if err != nil {
+		return err
+	}
+	ctx.Data["release_commit_parent"] = parent
+	ctx.Data["release_commit"] = sha
+	delete(ctx.Data, "release_commit_reverted")
+
 	ctx.Log("  Created commit: %s", message)
 	return nil
 }
 
+// releaseCommitMessage returns the message of the release commit.
+func releaseCommitMessage(version string) string {
+	return fmt.Sprintf("chore(release): %s", version)
+}
+
+// undoReleaseCommit resets the local release commit if it was never pushed.
+// Once pushed, undoPush reverts it instead.
+func undoReleaseCommit(ctx *Context) error {
+	sha := ctx.Data["release_commit"]
+	if reverted := ctx.Data["release_commit_reverted"]; sha == "" && reverted != "" {
+		ctx.Log("  Release commit %s was pushed and reverted", shortSHA(reverted))
+		return nil
+	}
+	if sha == "" {
+		ctx.Log("  No release commit to undo")
+		return nil
+	}
+	if ctx.Data["pushed_commit"] != "" {
+		ctx.Log("  Release commit was pushed; reverted on remote instead")
+		return nil
+	}
+
+	g := ctx.git()
+	if err := g.ResetSoft(ctx.Data["release_commit_parent"]); err != nil {
+		return err
+	}
+	delete(ctx.Data, "release_commit")
+
+	ctx.Log("  Reset local release commit %s (changes kept staged)", shortSHA(sha))
+	return nil
+}
+
 // pushToRemote pushes commits to the remote.
 func pushToRemote(ctx *Context) error {
-	g := git.New(ctx.Dir)
+	g := ctx.git()
 
 	if ctx.DryRun {
 		ctx.Log("  [Dry run] Would push to origin")
@@ -288,19 +472,51 @@ func pushToRemote(ctx *Context) error {
 		return err
 	}
 
-	if status.Ahead == 0 {
+	// A new branch, such as a fresh release branch, has no upstream yet
+	if status.RemoteBranch == "" {
+		if err := g.PushWithUpstream(); err != nil {
+			return err
+		}
+	} else if status.Ahead == 0 {
 		ctx.Log("  Already up to date with remote")
 		return nil
+	} else if err := g.Push(); err != nil {
+		return fmt.Errorf("failed to push: %w", err)
 	}
 
-	if err := g.Push(); err != nil {
-		return fmt.Errorf("failed to push: %w", err)
+	if head, err := g.CurrentCommit(); err == nil {
+		ctx.Data["pushed_commit"] = head
 	}
 
 	ctx.Log("  Pushed to origin")
 	return nil
 }
 
+// undoPush reverts a pushed release commit with a new revert commit.
+// Other pushed commits are left alone since they predate the release.
+// pushed_commit is kept so earlier undos don't rewrite the pushed history.
+func undoPush(ctx *Context) error {
+	sha := ctx.Data["release_commit"]
+	if ctx.Data["pushed_commit"] == "" || sha == "" {
+		ctx.Log("  No pushed release commit to revert")
+		return nil
+	}
+
+	g := ctx.git()
+	if err := g.Revert(sha); err != nil {
+		return err
+	}
+	if err := g.Push(); err != nil {
+		return fmt.Errorf("failed to push revert: %w", err)
+	}
+	ctx.Data["release_commit_reverted"] = sha
+	delete(ctx.Data, "release_commit")
+	delete(ctx.Data, "release_commit_parent")
+
+	ctx.Log("  Reverted release commit %s on remote", shortSHA(sha))
+	return nil
+}
+
 // waitForCI waits for CI checks to pass.
 func waitForCI(ctx *Context) error {
 	if ctx.SkipCI {
@@ -308,7 +524,7 @@ func waitForCI(ctx *Context) error {
 		return nil
 	}
 
-	g := git.New(ctx.Dir)
+	g := ctx.git()
 
 	// Check if gh CLI is available
 	if !commandExists("gh") {
@@ -334,29 +550,59 @@ func waitForCI(ctx *Context) error {
 
 // createTag creates and pushes the release tag.
 func createTag(ctx *Context) error {
-	g := git.New(ctx.Dir)
+	g := ctx.git()
 
+	tag := ReleaseTag(ctx)
 	if ctx.DryRun {
-		ctx.Log("  [Dry run] Would create tag: %s", ctx.Version)
+		ctx.Log("  [Dry run] Would create tag: %s", tag)
 		return nil
 	}
 
 	// Create the tag
-	message := fmt.Sprintf("Release %s", ctx.Version)
-	if err := g.CreateTag(ctx.Version, message, false); err != nil {
+	message := tagMessage(tag)
+	if err := g.CreateTag(tag, message, false); err != nil {
 		return fmt.Errorf("failed to create tag: %w", err)
 	}
 
-	ctx.Log("  Created tag: %s", ctx.Version)
+	ctx.Data["tag_created"] = tag
+
+	ctx.Log("  Created tag: %s", tag)
 
 	// Push the tag
-	if err := g.PushTag(ctx.Version); err != nil {
-		// Try to clean up the local tag
-		_ = g.DeleteTag(ctx.Version)
+	if err := g.PushTag(tag); err != nil {
 		return fmt.Errorf("failed to push tag: %w", err)
 	}
+	ctx.Data["tag_pushed"] = tag
+
+	ctx.Log("  Pushed tag: %s", tag)
+	return nil
+}
+
+// tagMessage returns the annotation for a release tag.
+func tagMessage(version string) string {
+	return fmt.Sprintf("Release %s", version)
+}
+
+// undoTag deletes the release tag locally and, if it was pushed, on the remote.
+func undoTag(ctx *Context) error {
+	g := ctx.git()
+
+	if tag := ctx.Data["tag_pushed"]; tag != "" {
+		if err := g.DeleteRemoteTag(tag); err != nil {
+			return err
+		}
+		delete(ctx.Data, "tag_pushed")
+		ctx.Log("  Deleted remote tag: %s", tag)
+	}
+
+	if tag := ctx.Data["tag_created"]; tag != "" {
+		if err := g.DeleteTag(tag); err != nil {
+			return fmt.Errorf("failed to delete tag %s: %w", tag, err)
+		}
+		delete(ctx.Data, "tag_created")
+		ctx.Log("  Deleted local tag: %s", tag)
+	}
 
-	ctx.Log("  Pushed tag: %s", ctx.Version)
 	return nil
 }
 
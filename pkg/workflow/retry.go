@@ -0,0 +1,145 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/agentplexus/agent-team-release/pkg/git"
+)
+
+// RetryPolicy controls how a failing step is retried.
+type RetryPolicy struct {
+	MaxAttempts  int                  // Total attempts including the first (<= 1 = no retry)
+	InitialDelay time.Duration        // Wait before the second attempt
+	MaxDelay     time.Duration        // Upper bound on the wait (0 = no bound)
+	Multiplier   float64              // Growth factor between waits (0 = 2)
+	Jitter       float64              // Fraction of each wait randomized, from 0 to 1
+	Retryable    func(err error) bool // Reports whether err is worth retrying (nil = any error)
+}
+
+// StepAttempt records one execution of a step under a retry policy.
+type StepAttempt struct {
+	Number   int
+	Error    error // nil if the attempt succeeded
+	Duration time.Duration
+}
+
+// maxAttempts returns the number of attempts allowed, at least 1.
+func (p *RetryPolicy) maxAttempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// retryable reports whether err may be retried. Cancellation and approval
+// aborts never are.
+func (p *RetryPolicy) retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, ErrAborted) {
+		return false
+	}
+	if p.Retryable == nil {
+		return true
+	}
+	return p.Retryable(err)
+}
+
+// delay returns the wait after the given failed attempt, growing
+// exponentially from InitialDelay and randomized by Jitter.
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+
+	d := float64(p.InitialDelay)
+	for i := 1; i < attempt; i++ {
+		d *= multiplier
+		if p.MaxDelay > 0 && d >= float64(p.MaxDelay) {
+			break
+		}
+	}
+	if p.MaxDelay > 0 && d > float64(p.MaxDelay) {
+		d = float64(p.MaxDelay)
+	}
+
+	if jitter := min(max(p.Jitter, 0), 1); jitter > 0 {
+		// Spread uniformly over [d*(1-jitter), d*(1+jitter)]
+		d *= 1 + jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(d)
+}
+
+// runAttempts runs a step's function, retrying it according to its policy.
+// Attempts are recorded in result when the step has a policy allowing
+// retries. It returns the error of the last attempt.
+func runAttempts(step *Step, ctx *Context, result *StepResult) error {
+	policy := step.Retry
+	attempts := policy.maxAttempts()
+
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		err := callStep(step, ctx)
+		if attempts > 1 {
+			result.Attempts = append(result.Attempts, StepAttempt{
+				Number:   attempt,
+				Error:    err,
+				Duration: time.Since(start),
+			})
+		}
+
+		if err == nil || attempt >= attempts || ctx.context().Err() != nil || !policy.retryable(err) {
+			return err
+		}
+
+		wait := policy.delay(attempt)
+		ctx.Log("  Attempt %d/%d failed: %v; retrying in %s", attempt, attempts, err, wait.Round(time.Millisecond))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.context().Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// callStep runs a step's function once, applying its timeout.
+func callStep(step *Step, ctx *Context) error {
+	parent := ctx.context()
+	if step.Timeout > 0 {
+		restore := ctx.withTimeout(step.Timeout)
+		defer restore()
+	}
+
+	err := step.Func(ctx)
+	if err != nil && parent.Err() == nil && errors.Is(ctx.context().Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("step timed out after %s: %w", step.Timeout, err)
+	}
+	return err
+}
+
+// pushRetry retries pushes that fail on network or server errors.
+var pushRetry = &RetryPolicy{
+	MaxAttempts:  3,
+	InitialDelay: 2 * time.Second,
+	MaxDelay:     30 * time.Second,
+	Jitter:       0.2,
+	Retryable:    git.IsTransient,
+}
+
+// ciRetry retries CI waits that fail while querying status, but not when
+// a check failed or the wait timed out.
+var ciRetry = &RetryPolicy{
+	MaxAttempts:  3,
+	InitialDelay: 5 * time.Second,
+	MaxDelay:     time.Minute,
+	Jitter:       0.2,
+	Retryable: func(err error) bool {
+		return !errors.Is(err, git.ErrCIFailed) && !errors.Is(err, git.ErrCITimeout)
+	},
+}
@@ -0,0 +1,187 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agentplexus/agent-team-release/pkg/config"
+	"github.com/agentplexus/agent-team-release/pkg/git"
+)
+
+func TestRunnerRun_Retry(t *testing.T) {
+	calls := 0
+	wf := &Workflow{
+		Name: "Retry",
+		Steps: []Step{
+			{Name: "Push", Type: StepTypeFunc, Required: true,
+				Retry: &RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond},
+				Func: func(ctx *Context) error {
+					calls++
+					if calls < 3 {
+						return fmt.Errorf("connection reset %d", calls)
+					}
+					return nil
+				}},
+		},
+	}
+
+	result := NewRunner().Run(wf, NewContext("/tmp", "v1.0.0"))
+
+	if !result.Success {
+		t.Fatalf("expected success after retries, got %v", result.Error)
+	}
+	attempts := result.Steps[0].Attempts
+	if len(attempts) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(attempts))
+	}
+	for i, a := range attempts {
+		if a.Number != i+1 {
+			t.Errorf("attempt %d has number %d", i, a.Number)
+		}
+		if (a.Error == nil) != (i == 2) {
+			t.Errorf("attempt %d error = %v", a.Number, a.Error)
+		}
+	}
+
+	summary := result.Summary()
+	for _, want := range []string{"[3 attempts]", "attempt 1 failed", "connection reset 2"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary missing %q:\n%s", want, summary)
+		}
+	}
+
+	js := result.ToJSON().Steps[0].Attempts
+	if len(js) != 3 || js[0].Success || js[0].Error != "connection reset 1" || !js[2].Success {
+		t.Errorf("unexpected JSON attempts: %+v", js)
+	}
+}
+
+func TestRunnerRun_RetryExhausted(t *testing.T) {
+	calls := 0
+	wf := &Workflow{
+		Name: "Retry",
+		Steps: []Step{
+			{Name: "Push", Type: StepTypeFunc, Required: true,
+				Retry: &RetryPolicy{MaxAttempts: 2, InitialDelay: time.Millisecond},
+				Func: func(ctx *Context) error {
+					calls++
+					return errors.New("bad gateway")
+				}},
+		},
+	}
+
+	result := NewRunner().Run(wf, NewContext("/tmp", "v1.0.0"))
+
+	if result.Success {
+		t.Fatal("expected failure after exhausting attempts")
+	}
+	if calls != 2 || len(result.Steps[0].Attempts) != 2 {
+		t.Errorf("expected 2 attempts, got %d calls and %d recorded", calls, len(result.Steps[0].Attempts))
+	}
+}
+
+func TestRunnerRun_RetryNotRetryable(t *testing.T) {
+	calls := 0
+	wf := &Workflow{
+		Name: "Retry",
+		Steps: []Step{
+			{Name: "Push", Type: StepTypeFunc, Required: true,
+				Retry: &RetryPolicy{MaxAttempts: 5, InitialDelay: time.Millisecond, Retryable: git.IsTransient},
+				Func: func(ctx *Context) error {
+					calls++
+					return errors.New("rejected: non-fast-forward")
+				}},
+		},
+	}
+
+	result := NewRunner().Run(wf, NewContext("/tmp", "v1.0.0"))
+
+	if result.Success || calls != 1 {
+		t.Errorf("expected a single failed attempt, got success=%v calls=%d", result.Success, calls)
+	}
+	if len(result.Steps[0].Attempts) != 1 {
+		t.Errorf("expected 1 recorded attempt, got %d", len(result.Steps[0].Attempts))
+	}
+	if strings.Contains(result.Summary(), "attempts]") {
+		t.Errorf("summary should not mention attempts for a single try:\n%s", result.Summary())
+	}
+}
+
+func TestRunnerRun_RetryCancelledDuringBackoff(t *testing.T) {
+	goCtx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	wf := &Workflow{
+		Name: "Retry",
+		Steps: []Step{
+			{Name: "Push", Type: StepTypeFunc, Required: true,
+				Retry: &RetryPolicy{MaxAttempts: 3, InitialDelay: time.Hour},
+				Func: func(ctx *Context) error {
+					calls++
+					time.AfterFunc(10*time.Millisecond, cancel)
+					return errors.New("connection timed out")
+				}},
+		},
+	}
+
+	ctx := NewContext("/tmp", "v1.0.0")
+	ctx.Ctx = goCtx
+	done := make(chan *WorkflowResult)
+	go func() { done <- NewRunner().Run(wf, ctx) }()
+
+	select {
+	case result := <-done:
+		if !result.Cancelled || calls != 1 {
+			t.Errorf("expected cancellation after 1 attempt, got cancelled=%v calls=%d", result.Cancelled, calls)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancellation did not interrupt the backoff wait")
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := &RetryPolicy{InitialDelay: time.Second, MaxDelay: 5 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := p.delay(i + 1); got != w {
+			t.Errorf("delay(%d) = %s, want %s", i+1, got, w)
+		}
+	}
+
+	p = &RetryPolicy{InitialDelay: time.Second, Multiplier: 3, Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		if got := p.delay(2); got < 1500*time.Millisecond || got > 4500*time.Millisecond {
+			t.Fatalf("jittered delay(2) = %s, want within [1.5s, 4.5s]", got)
+		}
+	}
+}
+
+func TestFromConfig_Retry(t *testing.T) {
+	wc := config.WorkflowConfig{
+		Steps: []config.StepConfig{
+			{Uses: StepWaitCI, Retry: &config.RetryConfig{Attempts: 5, Delay: "10s", MaxDelay: "2m", Jitter: 0.1}},
+			{Name: "Publish", Run: "true", Retry: &config.RetryConfig{Attempts: 2}},
+		},
+	}
+
+	w, err := FromConfig("wf", wc)
+	if err != nil {
+		t.Fatalf("FromConfig() error = %v", err)
+	}
+
+	ci := w.Steps[0].Retry
+	if ci == nil || ci.MaxAttempts != 5 || ci.InitialDelay != 10*time.Second || ci.MaxDelay != 2*time.Minute || ci.Jitter != 0.1 {
+		t.Fatalf("unexpected wait-ci policy: %+v", ci)
+	}
+	if ci.Retryable == nil || ci.Retryable(fmt.Errorf("CI failed: %w", git.ErrCIFailed)) {
+		t.Error("configured wait-ci policy should keep the built-in predicate")
+	}
+
+	run := w.Steps[1].Retry
+	if run == nil || run.MaxAttempts != 2 || run.InitialDelay != time.Second || run.Retryable != nil {
+		t.Errorf("unexpected command policy: %+v", run)
+	}
+}
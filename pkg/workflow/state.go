@@ -0,0 +1,170 @@
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/agentplexus/agent-team-release/pkg/git"
+)
+
+// stateDirName is the directory under .git where checkpoint state is stored.
+const stateDirName = "atrelease"
+
+// State is a persisted checkpoint of a workflow run.
+// It is written after every top-level step so an interrupted run can be resumed.
+type State struct {
+	Workflow  string            `json:"workflow"`
+	Version   string            `json:"version"`
+	Team      string            `json:"team,omitempty"`   // team definition the workflow was built from
+	Module    string            `json:"module,omitempty"` // module directory of a module release
+	Commit    string            `json:"commit"`           // HEAD after the last recorded step
+	Completed bool              `json:"completed"`
+	Steps     []StepState       `json:"steps"`
+	Data      map[string]string `json:"data,omitempty"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// StepState is the persisted form of a StepResult.
+type StepState struct {
+	Name     string        `json:"name"`
+	Success  bool          `json:"success"`
+	Skipped  bool          `json:"skipped,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// StatePath returns the checkpoint file path for a version, or a module
+// release tag such as sdk/go/v1.2.3, in the repository at dir.
+// State lives under .git/atrelease/ so it is never committed.
+func StatePath(dir, version string) (string, error) {
+	gitDir, err := git.New(dir).GitDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate .git directory: %w", err)
+	}
+	if i := strings.LastIndex(version, "/") + 1; i < len(version) && version[i] != 'v' {
+		version = version[:i] + "v" + version[i:]
+	}
+	return filepath.Join(gitDir, stateDirName, filepath.FromSlash(version)+".json"), nil
+}
+
+// LoadState reads a checkpoint file.
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading state: %w", err)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing state: %w", err)
+	}
+	if state.Data == nil {
+		state.Data = make(map[string]string)
+	}
+
+	return &state, nil
+}
+
+// UnfinishedState returns the checkpoint at path if it records a workflow
+// that did not complete, or nil if there is none. Starting the workflow
+// afresh would overwrite it.
+func UnfinishedState(path string) (*State, error) {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	state, err := LoadState(path)
+	if err != nil || state.Completed {
+		return nil, err
+	}
+	return state, nil
+}
+
+// Save writes the checkpoint file, creating its directory if needed.
+func (s *State) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating state directory: %w", err)
+	}
+
+	s.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write atomically so a crash mid-write never corrupts the checkpoint
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("writing state: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// Record stores the result of a step, replacing any earlier result with the same name.
+func (s *State) Record(result StepResult) {
+	st := StepState{
+		Name:     result.Name,
+		Success:  result.Success,
+		Skipped:  result.Skipped,
+		Duration: result.Duration,
+	}
+	if result.Error != nil {
+		st.Error = result.Error.Error()
+	}
+
+	for i, existing := range s.Steps {
+		if existing.Name == result.Name {
+			s.Steps[i] = st
+			return
+		}
+	}
+	s.Steps = append(s.Steps, st)
+}
+
+// Forget removes the recorded result of a step.
+func (s *State) Forget(name string) {
+	for i, st := range s.Steps {
+		if st.Name == name {
+			s.Steps = append(s.Steps[:i], s.Steps[i+1:]...)
+			return
+		}
+	}
+}
+
+// IsStepDone returns true if the named step completed successfully in this state.
+func (s *State) IsStepDone(name string) bool {
+	for _, st := range s.Steps {
+		if st.Name == name {
+			return st.Success && !st.Skipped
+		}
+	}
+	return false
+}
+
+// VerifyHead returns an error if HEAD in dir differs from the recorded commit.
+func (s *State) VerifyHead(dir string) error {
+	if s.Commit == "" {
+		return nil
+	}
+	head, err := git.New(dir).CurrentCommit()
+	if err != nil {
+		return fmt.Errorf("failed to read HEAD: %w", err)
+	}
+	if head != s.Commit {
+		return fmt.Errorf("HEAD moved since last checkpoint (expected %s, found %s)", shortSHA(s.Commit), shortSHA(head))
+	}
+	return nil
+}
+
+// shortSHA abbreviates a commit SHA for display.
+func shortSHA(sha string) string {
+	sha = strings.TrimSpace(sha)
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
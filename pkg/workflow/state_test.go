@@ -0,0 +1,250 @@
+package workflow
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// initTestRepo creates a git repository with a single commit.
+func initTestRepo(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	dir := t.TempDir()
+	cmds := [][]string{
+		{"init"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test User"},
+		{"commit", "--allow-empty", "-m", "initial"},
+	}
+	for _, args := range cmds {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func TestStatePath(t *testing.T) {
+	dir := initTestRepo(t)
+
+	path, err := StatePath(dir, "1.2.3")
+	if err != nil {
+		t.Fatalf("StatePath() error: %v", err)
+	}
+	if !strings.HasSuffix(path, filepath.Join(".git", "atrelease", "v1.2.3.json")) {
+		t.Errorf("StatePath() = %s, want suffix .git/atrelease/v1.2.3.json", path)
+	}
+
+	path, err = StatePath(dir, "sdk/go/1.2.3")
+	if err != nil {
+		t.Fatalf("StatePath() error: %v", err)
+	}
+	if !strings.HasSuffix(path, filepath.Join(".git", "atrelease", "sdk", "go", "v1.2.3.json")) {
+		t.Errorf("StatePath() = %s, want suffix .git/atrelease/sdk/go/v1.2.3.json", path)
+	}
+}
+
+func TestStateRecord(t *testing.T) {
+	s := &State{}
+	s.Record(StepResult{Name: "A", Success: false, Error: errors.New("boom")})
+	s.Record(StepResult{Name: "A", Success: true})
+	s.Record(StepResult{Name: "B", Skipped: true})
+
+	if len(s.Steps) != 2 {
+		t.Fatalf("Steps = %d, want 2", len(s.Steps))
+	}
+	if !s.IsStepDone("A") {
+		t.Error("A should be done after successful re-run")
+	}
+	if s.IsStepDone("B") {
+		t.Error("Skipped step should not count as done")
+	}
+	if s.IsStepDone("C") {
+		t.Error("Unknown step should not be done")
+	}
+}
+
+func TestRunnerResume(t *testing.T) {
+	dir := initTestRepo(t)
+	statePath := filepath.Join(dir, ".git", "atrelease", "v1.0.0.json")
+
+	failSecond := true
+	firstRuns := 0
+	wf := &Workflow{
+		Name: "Resumable",
+		Steps: []Step{
+			{
+				Name:     "First",
+				Type:     StepTypeFunc,
+				Required: true,
+				Func: func(ctx *Context) error {
+					firstRuns++
+					ctx.Data["first"] = "done"
+					return nil
+				},
+			},
+			{
+				Name:     "Second",
+				Type:     StepTypeFunc,
+				Required: true,
+				Func: func(ctx *Context) error {
+					if ctx.Data["first"] != "done" {
+						return errors.New("data not restored")
+					}
+					if failSecond {
+						return errors.New("interrupted")
+					}
+					return nil
+				},
+			},
+		},
+	}
+
+	runner := NewRunner()
+	runner.StateFile = statePath
+	result := runner.Run(wf, NewContext(dir, "v1.0.0"))
+	if result.Success {
+		t.Fatal("first run should fail")
+	}
+
+	state, err := LoadState(statePath)
+	if err != nil {
+		t.Fatalf("LoadState() error: %v", err)
+	}
+	if !state.IsStepDone("First") || state.IsStepDone("Second") {
+		t.Fatalf("unexpected checkpoint: %+v", state.Steps)
+	}
+
+	failSecond = false
+	runner = NewRunner()
+	runner.StateFile = statePath
+	runner.Resume = state
+	result = runner.Run(wf, NewContext(dir, "v1.0.0"))
+	if !result.Success {
+		t.Fatalf("resumed run should succeed: %s", result.Output)
+	}
+	if firstRuns != 1 {
+		t.Errorf("First ran %d times, want 1", firstRuns)
+	}
+	if !result.Steps[0].Resumed {
+		t.Error("First should be marked as resumed")
+	}
+
+	state, _ = LoadState(statePath)
+	if !state.Completed {
+		t.Error("state should be marked completed")
+	}
+}
+
+func TestRunnerResume_HeadMoved(t *testing.T) {
+	dir := initTestRepo(t)
+
+	state := &State{
+		Workflow: "Moved",
+		Version:  "v1.0.0",
+		Commit:   "0000000000000000000000000000000000000000",
+		Data:     map[string]string{},
+	}
+
+	runner := NewRunner()
+	runner.Resume = state
+	result := runner.Run(&Workflow{Name: "Moved"}, NewContext(dir, "v1.0.0"))
+
+	if result.Success {
+		t.Error("resume should be refused when HEAD moved")
+	}
+	if !strings.Contains(result.Output, "HEAD moved") {
+		t.Errorf("Output should explain HEAD moved, got: %s", result.Output)
+	}
+}
+
+func TestLoadState_Missing(t *testing.T) {
+	_, err := LoadState(filepath.Join(os.TempDir(), "does-not-exist-state.json"))
+	if err == nil {
+		t.Error("LoadState() should fail for missing file")
+	}
+}
+
+func TestRunnerState_TeamAndModule(t *testing.T) {
+	dir := initTestRepo(t)
+	statePath := filepath.Join(dir, ".git", "atrelease", "sdk", "go", "v1.0.0.json")
+
+	wf := &Workflow{
+		Name: "Module release",
+		Steps: []Step{{
+			Name:     "Interrupted",
+			Type:     StepTypeFunc,
+			Required: true,
+			Func:     func(ctx *Context) error { return errors.New("interrupted") },
+		}},
+	}
+
+	runner := NewRunner()
+	runner.StateFile = statePath
+	runner.Team = "specs/teams/release-team.json"
+	ctx := NewContext(dir, "v1.0.0")
+	ctx.Data["module"] = "sdk/go"
+	runner.Run(wf, ctx)
+
+	state, err := UnfinishedState(statePath)
+	if err != nil {
+		t.Fatalf("UnfinishedState() error: %v", err)
+	}
+	if state == nil {
+		t.Fatal("expected an unfinished checkpoint")
+	}
+	if state.Team != "specs/teams/release-team.json" || state.Module != "sdk/go" {
+		t.Errorf("checkpoint team = %q, module = %q", state.Team, state.Module)
+	}
+
+	// The checkpoint keeps them when resumed
+	runner = NewRunner()
+	runner.StateFile = statePath
+	runner.Resume = state
+	runner.Team = state.Team
+	runner.Run(wf, NewContext(dir, "v1.0.0"))
+	if state, _ := LoadState(statePath); state.Team != "specs/teams/release-team.json" || state.Module != "sdk/go" {
+		t.Errorf("resumed checkpoint team = %q, module = %q", state.Team, state.Module)
+	}
+}
+
+func TestUnfinishedState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "v1.0.0.json")
+
+	if state, err := UnfinishedState(path); state != nil || err != nil {
+		t.Errorf("UnfinishedState(missing) = %v, %v", state, err)
+	}
+
+	s := &State{Workflow: "Release v1.0.0", Version: "v1.0.0"}
+	if err := s.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if state, err := UnfinishedState(path); state == nil || err != nil {
+		t.Errorf("UnfinishedState(unfinished) = %v, %v", state, err)
+	}
+
+	s.Completed = true
+	if err := s.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if state, err := UnfinishedState(path); state != nil || err != nil {
+		t.Errorf("UnfinishedState(completed) = %v, %v", state, err)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UnfinishedState(path); err == nil {
+		t.Error("UnfinishedState() should fail for a corrupt checkpoint")
+	}
+}
@@ -0,0 +1,126 @@
+package workflow
+
+import (
+	"fmt"
+
+	multiagentspec "github.com/agentplexus/multi-agent-spec/sdk/go"
+
+	"github.com/agentplexus/agent-team-release/pkg/report"
+)
+
+// TeamStepMapping maps team spec step names to the registered steps that
+// implement them, in execution order. Spec steps not listed here are looked
+// up directly in the step registry.
+var TeamStepMapping = map[string][]string{
+	"pm-validation":       {StepValidateVersion, StepPMValidation},
+	"qa-validation":       {StepValidate},
+	"docs-validation":     {StepDocsValidation},
+	"security-validation": {StepSecurityValidation},
+	"release-validation":  {StepCheckWorkingDir, StepReleaseValidation},
+	"execute-release":     {StepChangelog, StepRoadmap, StepReadme, StepBump, StepCommit, StepPush, StepWaitCI, StepTag},
+}
+
+// FromTeam builds a release workflow from a multi-agent-spec team definition.
+// Each spec step expands into its mapped steps, which run in order; spec
+// dependencies become dependencies between the expanded steps. Every step in
+// a phase also depends on all steps of the previous phase.
+func FromTeam(team *multiagentspec.Team, version string) (*Workflow, error) {
+	if team.Workflow == nil || len(team.Workflow.Steps) == 0 {
+		return nil, fmt.Errorf("team %q has no workflow steps", team.Name)
+	}
+
+	w := &Workflow{
+		Name:        fmt.Sprintf("Release %s (%s)", version, team.Name),
+		Description: team.Description,
+	}
+
+	// first and last hold the expanded step names for each spec step
+	first := make(map[string]string)
+	last := make(map[string]string)
+	var prevPhase []string
+
+	for _, phase := range report.GetPhases(team) {
+		var thisPhase []string
+
+		for _, specStep := range phase.Steps {
+			refs, ok := TeamStepMapping[specStep.Name]
+			if !ok {
+				if _, registered := LookupStep(specStep.Name); !registered {
+					return nil, fmt.Errorf("no step implementation for team step %q", specStep.Name)
+				}
+				refs = []string{specStep.Name}
+			}
+
+			for i, ref := range refs {
+				step, ok := LookupStep(ref)
+				if !ok {
+					return nil, fmt.Errorf("team step %q maps to unknown step %q", specStep.Name, ref)
+				}
+				step.Name = specStep.Name + ": " + step.Name
+				step.Phase = phase.Name
+
+				if i == 0 {
+					first[specStep.Name] = step.Name
+					// Resolved below once all spec steps are known
+					step.DependsOn = append([]string{}, prevPhase...)
+				} else {
+					step.DependsOn = []string{last[specStep.Name]}
+				}
+				last[specStep.Name] = step.Name
+				w.Steps = append(w.Steps, step)
+			}
+
+			thisPhase = append(thisPhase, specStep.Name)
+		}
+
+		if len(thisPhase) > 0 {
+			prevPhase = thisPhase
+		}
+	}
+
+	// Translate spec-level dependencies (and phase dependencies) to the
+	// expanded step names: a spec step starts after its dependencies' last steps.
+	for i := range w.Steps {
+		step := &w.Steps[i]
+		specName := specStepFor(first, step.Name)
+		if specName == "" {
+			continue
+		}
+
+		deps := step.DependsOn
+		for _, s := range team.Workflow.Steps {
+			if s.Name == specName {
+				deps = append(deps, s.DependsOn...)
+			}
+		}
+
+		step.DependsOn = nil
+		seen := make(map[string]bool)
+		for _, dep := range deps {
+			name, ok := last[dep]
+			if !ok {
+				return nil, fmt.Errorf("team step %q depends on unknown step %q", specName, dep)
+			}
+			if !seen[name] {
+				seen[name] = true
+				step.DependsOn = append(step.DependsOn, name)
+			}
+		}
+	}
+
+	if _, err := buildGraph(w.Steps); err != nil {
+		return nil, fmt.Errorf("team %q: %w", team.Name, err)
+	}
+
+	return w, nil
+}
+
+// specStepFor returns the spec step whose first expanded step is name.
+func specStepFor(first map[string]string, name string) string {
+	for spec, expanded := range first {
+		if expanded == name {
+			return spec
+		}
+	}
+	return ""
+}
@@ -0,0 +1,145 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	multiagentspec "github.com/agentplexus/multi-agent-spec/sdk/go"
+
+	"github.com/agentplexus/agent-team-release/pkg/report"
+)
+
+func TestFromTeam_ReleaseTeamSpec(t *testing.T) {
+	team, err := report.LoadTeamSpecFile("../../specs/teams/release-team.json")
+	if err != nil {
+		t.Fatalf("LoadTeamSpecFile() error: %v", err)
+	}
+
+	wf, err := FromTeam(team, "v1.0.0")
+	if err != nil {
+		t.Fatalf("FromTeam() error: %v", err)
+	}
+
+	steps := make(map[string]Step)
+	for _, s := range wf.Steps {
+		steps[s.Name] = s
+	}
+
+	// Spec dependencies map onto the last expanded step of the dependency
+	qa := steps["qa-validation: Run validation checks"]
+	if len(qa.DependsOn) != 1 || qa.DependsOn[0] != "pm-validation: PM validation" {
+		t.Errorf("qa-validation deps = %v", qa.DependsOn)
+	}
+
+	// Expanded steps run in order
+	pm := steps["pm-validation: PM validation"]
+	if len(pm.DependsOn) != 1 || pm.DependsOn[0] != "pm-validation: Validate version" {
+		t.Errorf("pm-validation deps = %v", pm.DependsOn)
+	}
+
+	// Execution phase steps come after all review steps
+	changelog := steps["execute-release: Generate changelog"]
+	if changelog.Phase != "PHASE 2: EXECUTE" {
+		t.Errorf("changelog phase = %q", changelog.Phase)
+	}
+	if len(changelog.DependsOn) < 5 {
+		t.Errorf("execute-release should depend on every review step, got %v", changelog.DependsOn)
+	}
+
+	tag := steps["execute-release: Create tag"]
+	if tag.Undo == nil {
+		t.Error("mapped steps should keep their undo functions")
+	}
+}
+
+func TestFromTeam_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		team *multiagentspec.Team
+		want string
+	}{
+		{
+			name: "no workflow",
+			team: &multiagentspec.Team{Name: "empty"},
+			want: "no workflow steps",
+		},
+		{
+			name: "unmapped step",
+			team: &multiagentspec.Team{
+				Name:     "custom",
+				Workflow: &multiagentspec.Workflow{Steps: []multiagentspec.Step{{Name: "deploy-rockets"}}},
+			},
+			want: "no step implementation",
+		},
+		{
+			name: "unknown dependency",
+			team: &multiagentspec.Team{
+				Name: "custom",
+				Workflow: &multiagentspec.Workflow{Steps: []multiagentspec.Step{
+					{Name: "qa-validation", DependsOn: []string{"missing"}},
+				}},
+			},
+			want: "unknown step",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromTeam(tt.team, "v1.0.0")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("FromTeam() error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromTeam_RegisteredStepName(t *testing.T) {
+	team := &multiagentspec.Team{
+		Name: "minimal",
+		Workflow: &multiagentspec.Workflow{Steps: []multiagentspec.Step{
+			{Name: StepChangelog},
+			{Name: StepTag, DependsOn: []string{StepChangelog}},
+		}},
+	}
+
+	wf, err := FromTeam(team, "v1.0.0")
+	if err != nil {
+		t.Fatalf("FromTeam() error: %v", err)
+	}
+	if len(wf.Steps) != 2 {
+		t.Fatalf("Steps = %d, want 2", len(wf.Steps))
+	}
+	if wf.Steps[1].DependsOn[0] != wf.Steps[0].Name {
+		t.Errorf("tag should depend on changelog, got %v", wf.Steps[1].DependsOn)
+	}
+}
+
+func TestFromTeam_BumpsManifests(t *testing.T) {
+	dir, _ := initBranchRepo(t)
+	commitFile(t, dir, "package.json", "{\n  \"name\": \"demo\",\n  \"version\": \"1.0.0\"\n}\n", "feat: package")
+	gitRun(t, dir, "push")
+
+	team := &multiagentspec.Team{
+		Name: "release",
+		Workflow: &multiagentspec.Workflow{Steps: []multiagentspec.Step{
+			{Name: "release-validation"},
+			{Name: "execute-release", DependsOn: []string{"release-validation"}},
+		}},
+	}
+	wf, err := FromTeam(team, "v1.1.0")
+	if err != nil {
+		t.Fatalf("FromTeam() error: %v", err)
+	}
+
+	ctx := NewContext(dir, "v1.1.0")
+	ctx.SkipCI = true
+	result := NewRunner().Run(wf, ctx)
+	if !result.Success {
+		t.Fatalf("release failed: %v\n%s", result.Error, result.Output)
+	}
+
+	// Validation ran before the bump, which is part of the release commit
+	if got := gitRun(t, dir, "show", "v1.1.0:package.json"); !strings.Contains(got, `"version": "1.1.0"`) {
+		t.Errorf("package.json at v1.1.0 not bumped:\n%s", got)
+	}
+}
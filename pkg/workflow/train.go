@@ -0,0 +1,32 @@
+package workflow
+
+// TrainWorkflow returns the workflow releasing one repository of a release
+// train. It is the release workflow with the PM, documentation, security
+// and release validation areas checking the bumped tree before the changelog,
+// roadmap and README are written, so a NO-GO repository stops the train
+// before anything is pushed.
+func TrainWorkflow(version string) *Workflow {
+	validated := []string{"Run validation checks", "PM validation", "Documentation validation",
+		"Security validation", "Release validation"}
+	return &Workflow{
+		Name:        "Release " + version,
+		Description: "Prepare and create release " + version + " as part of a release train",
+		Steps: []Step{
+			builtin(StepValidateVersion),
+			builtin(StepCheckWorkingDir),
+			builtin(StepBump, "Validate version", "Check working directory"),
+			builtin(StepValidate, "Bump versions"),
+			builtin(StepPMValidation, "Bump versions"),
+			builtin(StepDocsValidation, "Bump versions"),
+			builtin(StepSecurityValidation, "Bump versions"),
+			builtin(StepReleaseValidation, "Bump versions"),
+			builtin(StepChangelog, validated...),
+			builtin(StepRoadmap, validated...),
+			builtin(StepReadme, validated...),
+			builtin(StepCommit, "Generate changelog", "Update roadmap", "Update README"),
+			builtin(StepPush, "Create release commit"),
+			builtin(StepWaitCI, "Push to remote"),
+			builtin(StepTag, "Wait for CI"),
+		},
+	}
+}
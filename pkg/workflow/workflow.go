@@ -2,9 +2,16 @@
 package workflow
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/agentplexus/agent-team-release/pkg/actions"
+	"github.com/agentplexus/agent-team-release/pkg/config"
+	"github.com/agentplexus/agent-team-release/pkg/git"
+	"github.com/agentplexus/agent-team-release/pkg/interactive"
 )
 
 // StepType defines the type of workflow step.
@@ -21,14 +28,25 @@ const (
 // It receives the context and returns an error if the step fails.
 type StepFunc func(ctx *Context) error
 
+// PreviewFunc describes the changes a step would make without making them.
+type PreviewFunc func(ctx *Context) ([]actions.Proposal, error)
+
 // Step represents a single step in a workflow.
 type Step struct {
-	Name        string   // Step name for display
-	Description string   // Human-readable description
-	Type        StepType // Step type
-	Required    bool     // If true, workflow fails if step fails
-	Func        StepFunc // Function to execute (for StepTypeFunc)
-	SubSteps    []Step   // Sub-steps (for StepTypeComposite)
+	Name         string        // Step name for display
+	Ref          string        // Registry reference for built-in steps (e.g., "push")
+	Description  string        // Human-readable description
+	Type         StepType      // Step type
+	Required     bool          // If true, workflow fails if step fails
+	Func         StepFunc      // Function to execute (for StepTypeFunc)
+	Undo         StepFunc      // Optional compensation run when a later required step fails
+	DependsOn    []string      // Names of steps that must finish first (empty in all steps = sequential)
+	Phase        string        // Optional phase name for display (e.g., from a team spec)
+	Timeout      time.Duration // Fails an attempt if it runs longer (0 = no limit)
+	Retry        *RetryPolicy  // Optional policy for retrying a failed Func
+	Irreversible bool          // Ask the runner's Prompter for approval in interactive mode
+	Preview      PreviewFunc   // Optional description of what the step will do
+	SubSteps     []Step        // Sub-steps (for StepTypeComposite)
 }
 
 // Workflow defines a sequence of steps.
@@ -50,11 +68,19 @@ type Context struct {
 	SkipCI      bool              // Skip CI wait
 	Data        map[string]string // Arbitrary data passed between steps
 	Output      *strings.Builder  // Captured output
+	Ctx         context.Context   // Cancelled on interrupt or step timeout
+
+	events   *emitter        // Progress event emitter (nil = none)
+	runner   *Runner         // Runner executing the workflow, for prompts
+	step     string          // Step being run, for log events
+	rollback bool            // Running a compensating undo
+	pending  map[string]bool // Refs of other steps that had not finished when the step started
 }
 
 // NewContext creates a new workflow context.
 func NewContext(dir string, version string) *Context {
 	return &Context{
+		Ctx:     context.Background(),
 		Dir:     dir,
 		Version: version,
 		Data:    make(map[string]string),
@@ -62,33 +88,110 @@ func NewContext(dir string, version string) *Context {
 	}
 }
 
-// Log writes a message to the context output.
+// Log writes a message to the context output and emits it as a log event.
 func (c *Context) Log(format string, args ...interface{}) {
+	msg := c.write(format, args...)
+	c.events.logLines(c.step, c.rollback, msg)
+}
+
+// write writes a message to the context output without emitting an event.
+// The runner uses it for status lines that events already convey.
+func (c *Context) write(format string, args ...interface{}) string {
 	msg := fmt.Sprintf(format, args...)
 	c.Output.WriteString(msg)
 	if !strings.HasSuffix(msg, "\n") {
 		c.Output.WriteString("\n")
 	}
+	return msg
+}
+
+// context returns Ctx, or context.Background() if it is unset.
+func (c *Context) context() context.Context {
+	if c.Ctx == nil {
+		return context.Background()
+	}
+	return c.Ctx
+}
+
+// stepPending reports whether the workflow has a step registered under ref
+// that had not finished when the current step started.
+func (c *Context) stepPending(ref string) bool {
+	return c.pending[ref]
+}
+
+// git returns a git client for the working directory whose commands are
+// killed when the context is cancelled.
+func (c *Context) git() *git.Git {
+	return git.New(c.Dir).WithContext(c.context())
+}
+
+// withTimeout makes Ctx expire after d, until the returned function
+// restores it. Changes the step makes to the context, such as to Version,
+// are kept.
+func (c *Context) withTimeout(d time.Duration) (restore func()) {
+	parent := c.Ctx
+	var cancel context.CancelFunc
+	c.Ctx, cancel = context.WithTimeout(c.context(), d)
+	return func() {
+		cancel()
+		c.Ctx = parent
+	}
+}
+
+// fork returns a copy of the context with its own data map and output,
+// so a step can run concurrently with others.
+func (c *Context) fork() *Context {
+	child := *c
+	child.Data = copyData(c.Data)
+	child.Output = &strings.Builder{}
+	return &child
+}
+
+// merge applies the changes a forked step made relative to its starting
+// data and version.
+func (c *Context) merge(child *Context, baseData map[string]string, baseVersion string) {
+	for k, v := range child.Data {
+		if old, ok := baseData[k]; !ok || old != v {
+			c.Data[k] = v
+		}
+	}
+	for k := range baseData {
+		if _, ok := child.Data[k]; !ok {
+			delete(c.Data, k)
+		}
+	}
+	if child.Version != baseVersion {
+		c.Version = child.Version
+	}
 }
 
 // StepResult represents the result of a step execution.
 type StepResult struct {
-	Name     string
-	Success  bool
-	Skipped  bool
-	Error    error
-	Output   string
-	Duration time.Duration
-	SubSteps []StepResult // Results of sub-steps (for composite)
+	Name      string
+	Phase     string
+	Success   bool
+	Skipped   bool
+	Error     error
+	Output    string
+	Duration  time.Duration
+	Resumed   bool               // Completed in a previous run and not re-executed
+	Cancelled bool               // Interrupted before completing
+	Approval  string             // Answer at the approval gate: "proceed", "skip" or "abort"
+	Plan      []actions.Proposal // Changes the step would make (dry run only)
+	Attempts  []StepAttempt      // Each try under a retry policy, in order
+	SubSteps  []StepResult       // Results of sub-steps (for composite)
 }
 
 // WorkflowResult represents the result of a workflow execution.
 type WorkflowResult struct {
-	Name     string
-	Success  bool
-	Steps    []StepResult
-	Duration time.Duration
-	Output   string
+	Name      string
+	Success   bool
+	Cancelled bool  // Stopped by cancellation; completed steps remain checkpointed
+	Error     error // Why the workflow failed, if it did
+	Steps     []StepResult
+	Rollback  []StepResult // Compensations executed after a required step failed
+	Duration  time.Duration
+	Output    string
 }
 
 // Runner executes workflows.
@@ -97,11 +200,23 @@ type Runner struct {
 	Verbose     bool
 	Interactive bool
 	JSONOutput  bool
+	MaxWorkers  int                       // Maximum steps run concurrently (0 = unlimited)
+	NoRollback  bool                      // If true, don't run compensations on required-step failure
+	Hooks       map[string]config.HookSet // Hook scripts keyed by step ref, step name or "*"
+	StateFile   string                    // If set, checkpoint state is written here after each step
+	Team        string                    // Team definition the workflow was built from, saved in the checkpoint
+	Resume      *State                    // If set, steps completed in this state are not re-run
+	Events      EventSink                 // If set, receives progress events as the workflow runs
+	Prompter    interactive.Prompter      // Asks for approval of irreversible steps, and step questions, when Interactive
+
+	promptMu sync.Mutex // Serializes approval prompts from concurrent steps
 }
 
 // NewRunner creates a new workflow runner.
 func NewRunner() *Runner {
-	return &Runner{}
+	return &Runner{
+		MaxWorkers: DefaultMaxWorkers,
+	}
 }
 
 // Run executes a workflow and returns the results.
@@ -113,29 +228,61 @@ func (r *Runner) Run(w *Workflow, ctx *Context) *WorkflowResult {
 	ctx.Verbose = r.Verbose
 	ctx.Interactive = r.Interactive
 	ctx.JSONOutput = r.JSONOutput
+	ctx.runner = r
+	if r.Events != nil {
+		ctx.events = &emitter{sink: r.Events}
+	}
 
 	result := &WorkflowResult{
 		Name:    w.Name,
 		Success: true,
 	}
+	ctx.events.emit(Event{Type: EventWorkflowStarted, Workflow: w.Name, Status: "running", TotalSteps: len(w.Steps)})
+	defer func() {
+		ctx.events.emit(workflowFinishedEvent(result))
+	}()
 
-	ctx.Log("=== %s ===\n", w.Name)
+	ctx.write("=== %s ===\n", w.Name)
 	if w.Description != "" {
-		ctx.Log("%s\n", w.Description)
+		ctx.write("%s\n", w.Description)
+	}
+	ctx.write("")
+
+	state, err := r.prepareState(w, ctx)
+	if err != nil {
+		result.Success = false
+		result.Error = fmt.Errorf("cannot resume: %w", err)
+		ctx.write("❌ Cannot resume: %v\n", err)
+		result.Duration = time.Since(start)
+		result.Output = ctx.Output.String()
+		return result
 	}
-	ctx.Log("")
 
-	for _, step := range w.Steps {
-		stepResult := r.runStep(&step, ctx)
-		result.Steps = append(result.Steps, stepResult)
+	g, err := buildGraph(w.Steps)
+	if err != nil {
+		result.Success = false
+		result.Error = fmt.Errorf("invalid workflow: %w", err)
+		ctx.write("❌ Invalid workflow: %v\n", err)
+		result.Duration = time.Since(start)
+		result.Output = ctx.Output.String()
+		return result
+	}
 
-		if !stepResult.Success && !stepResult.Skipped {
-			if step.Required {
-				result.Success = false
-				ctx.Log("\n❌ Workflow failed at step: %s\n", step.Name)
-				break
-			}
-			ctx.Log("⚠ Step %s failed but is not required, continuing...\n", step.Name)
+	ex := r.execute(w, g, ctx, state)
+	result.Steps = ex.stepResults()
+
+	if ex.cancelled {
+		// Leave completed steps in place so the release can be resumed
+		result.Success = false
+		result.Cancelled = true
+		ctx.write("\n⏹ Workflow cancelled\n")
+	} else if ex.failed != nil {
+		result.Success = false
+		result.Error = fmt.Errorf("step %s failed", ex.failed.Name)
+		ctx.write("\n❌ Workflow failed at step: %s\n", ex.failed.Name)
+		if !r.NoRollback && !r.DryRun {
+			result.Rollback = r.rollback(ex.executed, ctx)
+			r.forgetUndone(state, result.Rollback, ctx)
 		}
 	}
 
@@ -143,23 +290,133 @@ func (r *Runner) Run(w *Workflow, ctx *Context) *WorkflowResult {
 	result.Output = ctx.Output.String()
 
 	if result.Success {
-		ctx.Log("\n✅ %s completed successfully\n", w.Name)
+		ctx.write("\n✅ %s completed successfully\n", w.Name)
+		if state != nil {
+			state.Completed = true
+			r.saveState(state, ctx)
+		}
 	}
 
 	return result
 }
 
+// rollback runs the Undo functions of executed steps in reverse order.
+// The failed step is included since it may have partially applied its changes,
+// so Undo functions must be safe to run when their step did nothing.
+func (r *Runner) rollback(executed []*Step, ctx *Context) []StepResult {
+	var results []StepResult
+
+	for i := len(executed) - 1; i >= 0; i-- {
+		step := executed[i]
+		if step.Undo == nil {
+			continue
+		}
+		if len(results) == 0 {
+			ctx.write("\n=== Rollback ===\n")
+		}
+		undo := Step{
+			Name:        step.Name,
+			Description: "Undo " + step.Name,
+			Type:        StepTypeFunc,
+			Func:        step.Undo,
+			Retry:       step.Retry, // Compensations talk to the same remotes
+		}
+		undoCtx := *ctx
+		undoCtx.step = step.Name
+		undoCtx.rollback = true
+		ctx.events.emit(Event{Type: EventStepStarted, Step: step.Name, Status: "running", Rollback: true})
+		res := r.runStep(&undo, &undoCtx)
+		ctx.events.emit(finishedEvent(res, "", true))
+		results = append(results, res)
+	}
+
+	return results
+}
+
+// forgetUndone drops rolled-back steps from the checkpoint so a resume re-runs them.
+func (r *Runner) forgetUndone(state *State, undone []StepResult, ctx *Context) {
+	if state == nil || len(undone) == 0 {
+		return
+	}
+	for _, u := range undone {
+		if u.Success {
+			state.Forget(u.Name)
+		}
+	}
+	if head, err := git.New(ctx.Dir).CurrentCommit(); err == nil {
+		state.Commit = head
+	}
+	r.saveState(state, ctx)
+}
+
+// prepareState restores context from a resumed state and returns the state
+// to checkpoint into, or nil if checkpointing is disabled.
+func (r *Runner) prepareState(w *Workflow, ctx *Context) (*State, error) {
+	if r.Resume != nil {
+		if r.Resume.Completed {
+			return nil, fmt.Errorf("%s already completed", r.Resume.Workflow)
+		}
+		if err := r.Resume.VerifyHead(ctx.Dir); err != nil {
+			return nil, err
+		}
+		if r.Resume.Version != "" {
+			ctx.Version = r.Resume.Version
+		}
+		for k, v := range r.Resume.Data {
+			ctx.Data[k] = v
+		}
+	}
+
+	// Dry runs make no changes, so there is nothing to resume
+	if r.StateFile == "" || r.DryRun {
+		return nil, nil
+	}
+
+	if r.Resume != nil {
+		return r.Resume, nil
+	}
+	return &State{
+		Workflow: w.Name,
+		Version:  ctx.Version,
+		Team:     r.Team,
+		Module:   ReleaseModule(ctx),
+		Data:     ctx.Data,
+	}, nil
+}
+
+// checkpoint records a step result and persists the state.
+func (r *Runner) checkpoint(state *State, stepResult StepResult, ctx *Context) {
+	if state == nil {
+		return
+	}
+	state.Record(stepResult)
+	state.Version = ctx.Version
+	state.Data = ctx.Data
+	if head, err := git.New(ctx.Dir).CurrentCommit(); err == nil {
+		state.Commit = head
+	}
+	r.saveState(state, ctx)
+}
+
+// saveState writes the state file, logging rather than failing on errors.
+func (r *Runner) saveState(state *State, ctx *Context) {
+	if err := state.Save(r.StateFile); err != nil {
+		ctx.Log("  Warning: failed to save checkpoint: %v", err)
+	}
+}
+
 // runStep executes a single step.
 func (r *Runner) runStep(step *Step, ctx *Context) StepResult {
 	start := time.Now()
 
 	result := StepResult{
-		Name: step.Name,
+		Name:  step.Name,
+		Phase: step.Phase,
 	}
 
-	ctx.Log("→ %s", step.Name)
+	ctx.write("→ %s", step.Name)
 	if step.Description != "" && ctx.Verbose {
-		ctx.Log("  %s", step.Description)
+		ctx.write("  %s", step.Description)
 	}
 
 	switch step.Type {
@@ -167,26 +424,62 @@ func (r *Runner) runStep(step *Step, ctx *Context) StepResult {
 		if step.Func == nil {
 			result.Skipped = true
 			result.Output = "No function defined"
-			ctx.Log(" [skipped]\n")
+			ctx.write(" [skipped]\n")
 			return result
 		}
 
-		err := step.Func(ctx)
-		if err != nil {
+		if r.needsApproval(step, ctx) {
+			action, err := r.approve(step, ctx)
+			result.Approval = action.String()
+			ctx.Log("  Approval: %s", action)
+			switch {
+			case err != nil:
+				result.Error = fmt.Errorf("%w: %v", ErrAborted, err)
+				result.Output = result.Error.Error()
+				ctx.write(" [aborted: %v]\n", err)
+				return result
+			case action == interactive.ApprovalSkip:
+				result.Skipped = true
+				result.Output = "Skipped at approval gate"
+				ctx.write(" [skipped]\n")
+				return result
+			case action == interactive.ApprovalAbort:
+				result.Error = ErrAborted
+				result.Output = ErrAborted.Error()
+				ctx.write(" [aborted]\n")
+				return result
+			}
+		}
+
+		if ctx.DryRun && step.Preview != nil {
+			planStep(step, ctx, &result)
+		}
+
+		err := runAttempts(step, ctx, &result)
+		if err != nil && ctx.context().Err() != nil {
+			result.Cancelled = true
+			result.Error = err
+			result.Output = err.Error()
+			ctx.write(" [cancelled]\n")
+		} else if err != nil {
 			result.Success = false
 			result.Error = err
 			result.Output = err.Error()
-			ctx.Log(" [failed: %v]\n", err)
+			ctx.write(" [failed: %v]\n", err)
 		} else {
 			result.Success = true
-			ctx.Log(" [done]\n")
+			ctx.write(" [done]\n")
 		}
 
 	case StepTypeComposite:
-		ctx.Log("\n")
+		ctx.write("\n")
 		allSuccess := true
 		for _, subStep := range step.SubSteps {
+			ctx.events.emit(Event{Type: EventStepStarted, Step: subStep.Name, Parent: step.Name, Phase: subStep.Phase, Status: "running", Rollback: ctx.rollback})
+			ctx.step = subStep.Name
 			subResult := r.runStep(&subStep, ctx)
+			ctx.step = step.Name
+			ctx.events.emit(finishedEvent(subResult, step.Name, ctx.rollback))
 			result.SubSteps = append(result.SubSteps, subResult)
 			if !subResult.Success && !subResult.Skipped && subStep.Required {
 				allSuccess = false
@@ -205,18 +498,46 @@ func (wr *WorkflowResult) Summary() string {
 	var sb strings.Builder
 
 	sb.WriteString(fmt.Sprintf("Workflow: %s\n", wr.Name))
-	sb.WriteString(fmt.Sprintf("Status: %s\n", statusEmoji(wr.Success)))
+	if wr.Cancelled {
+		sb.WriteString("Status: ⏹ Cancelled\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("Status: %s\n", statusEmoji(wr.Success)))
+	}
 	sb.WriteString(fmt.Sprintf("Duration: %s\n", wr.Duration.Round(time.Millisecond)))
 	sb.WriteString("\nSteps:\n")
 
+	phase := ""
 	for _, step := range wr.Steps {
+		if step.Phase != phase {
+			phase = step.Phase
+			sb.WriteString(fmt.Sprintf(" %s\n", phase))
+		}
 		status := "✓"
-		if step.Skipped {
+		if step.Resumed {
+			status = "↺"
+		} else if step.Cancelled {
+			status = "⏹"
+		} else if step.Skipped {
 			status = "⊘"
 		} else if !step.Success {
 			status = "✗"
 		}
-		sb.WriteString(fmt.Sprintf("  %s %s (%s)\n", status, step.Name, step.Duration.Round(time.Millisecond)))
+		approval := ""
+		if step.Approval != "" {
+			approval = " [approval: " + step.Approval + "]"
+		}
+		attempts := ""
+		if len(step.Attempts) > 1 {
+			attempts = fmt.Sprintf(" [%d attempts]", len(step.Attempts))
+		}
+		sb.WriteString(fmt.Sprintf("  %s %s (%s)%s%s\n", status, step.Name, step.Duration.Round(time.Millisecond), approval, attempts))
+		if len(step.Attempts) > 1 {
+			for _, a := range step.Attempts {
+				if a.Error != nil {
+					sb.WriteString(fmt.Sprintf("      attempt %d failed (%s): %v\n", a.Number, a.Duration.Round(time.Millisecond), a.Error))
+				}
+			}
+		}
 
 		for _, sub := range step.SubSteps {
 			subStatus := "✓"
@@ -229,6 +550,20 @@ func (wr *WorkflowResult) Summary() string {
 		}
 	}
 
+	if len(wr.Rollback) > 0 {
+		sb.WriteString("\nRollback:\n")
+		for _, undo := range wr.Rollback {
+			status := "✓"
+			if !undo.Success {
+				status = "✗"
+			}
+			sb.WriteString(fmt.Sprintf("  %s undo %s (%s)\n", status, undo.Name, undo.Duration.Round(time.Millisecond)))
+			if undo.Error != nil {
+				sb.WriteString(fmt.Sprintf("      %v\n", undo.Error))
+			}
+		}
+	}
+
 	return sb.String()
 }
 
@@ -244,18 +579,34 @@ type JSONResult struct {
 	Type         string           `json:"type" toon:"type"`
 	WorkflowName string           `json:"workflow_name" toon:"workflow_name"`
 	Success      bool             `json:"success" toon:"success"`
+	Cancelled    bool             `json:"cancelled,omitempty" toon:"cancelled,omitempty"`
 	Duration     string           `json:"duration" toon:"duration"`
 	Steps        []JSONStepResult `json:"steps" toon:"steps"`
+	Rollback     []JSONStepResult `json:"rollback,omitempty" toon:"rollback,omitempty"`
+	Plan         []PlanItem       `json:"plan,omitempty" toon:"plan,omitempty"`
 }
 
 // JSONStepResult represents a step result in structured format.
 type JSONStepResult struct {
-	Name     string           `json:"name" toon:"name"`
-	Success  bool             `json:"success" toon:"success"`
-	Skipped  bool             `json:"skipped,omitempty" toon:"skipped,omitempty"`
-	Error    string           `json:"error,omitempty" toon:"error,omitempty"`
-	Duration string           `json:"duration" toon:"duration"`
-	SubSteps []JSONStepResult `json:"sub_steps,omitempty" toon:"sub_steps,omitempty"`
+	Name      string           `json:"name" toon:"name"`
+	Phase     string           `json:"phase,omitempty" toon:"phase,omitempty"`
+	Success   bool             `json:"success" toon:"success"`
+	Skipped   bool             `json:"skipped,omitempty" toon:"skipped,omitempty"`
+	Resumed   bool             `json:"resumed,omitempty" toon:"resumed,omitempty"`
+	Cancelled bool             `json:"cancelled,omitempty" toon:"cancelled,omitempty"`
+	Approval  string           `json:"approval,omitempty" toon:"approval,omitempty"`
+	Error     string           `json:"error,omitempty" toon:"error,omitempty"`
+	Duration  string           `json:"duration" toon:"duration"`
+	Attempts  []JSONAttempt    `json:"attempts,omitempty" toon:"attempts,omitempty"`
+	SubSteps  []JSONStepResult `json:"sub_steps,omitempty" toon:"sub_steps,omitempty"`
+}
+
+// JSONAttempt represents one try of a retried step in structured format.
+type JSONAttempt struct {
+	Number   int    `json:"number" toon:"number"`
+	Success  bool   `json:"success" toon:"success"`
+	Error    string `json:"error,omitempty" toon:"error,omitempty"`
+	Duration string `json:"duration" toon:"duration"`
 }
 
 // ToJSON converts the workflow result to a JSON-serializable structure.
@@ -265,25 +616,47 @@ func (wr *WorkflowResult) ToJSON() JSONResult {
 		steps[i] = stepToJSON(step)
 	}
 
-	return JSONResult{
+	result := JSONResult{
 		Type:         "workflow_result",
 		WorkflowName: wr.Name,
 		Success:      wr.Success,
+		Cancelled:    wr.Cancelled,
 		Duration:     wr.Duration.Round(time.Millisecond).String(),
 		Steps:        steps,
 	}
+	for _, undo := range wr.Rollback {
+		result.Rollback = append(result.Rollback, stepToJSON(undo))
+	}
+	result.Plan = wr.Plan()
+
+	return result
 }
 
 func stepToJSON(step StepResult) JSONStepResult {
 	result := JSONStepResult{
-		Name:     step.Name,
-		Success:  step.Success,
-		Skipped:  step.Skipped,
-		Duration: step.Duration.Round(time.Millisecond).String(),
+		Name:      step.Name,
+		Phase:     step.Phase,
+		Success:   step.Success,
+		Skipped:   step.Skipped,
+		Resumed:   step.Resumed,
+		Cancelled: step.Cancelled,
+		Approval:  step.Approval,
+		Duration:  step.Duration.Round(time.Millisecond).String(),
 	}
 	if step.Error != nil {
 		result.Error = step.Error.Error()
 	}
+	for _, a := range step.Attempts {
+		attempt := JSONAttempt{
+			Number:   a.Number,
+			Success:  a.Error == nil,
+			Duration: a.Duration.Round(time.Millisecond).String(),
+		}
+		if a.Error != nil {
+			attempt.Error = a.Error.Error()
+		}
+		result.Attempts = append(result.Attempts, attempt)
+	}
 	if len(step.SubSteps) > 0 {
 		result.SubSteps = make([]JSONStepResult, len(step.SubSteps))
 		for i, sub := range step.SubSteps {
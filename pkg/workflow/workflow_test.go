@@ -1,9 +1,11 @@
 package workflow
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewContext(t *testing.T) {
@@ -293,3 +295,326 @@ func TestWorkflowResultSummary(t *testing.T) {
 		t.Error("Summary should contain step names")
 	}
 }
+
+func TestRunnerRun_Rollback(t *testing.T) {
+	var undone []string
+	undo := func(name string) StepFunc {
+		return func(ctx *Context) error {
+			undone = append(undone, name)
+			return nil
+		}
+	}
+
+	wf := &Workflow{
+		Name: "Test Workflow",
+		Steps: []Step{
+			{Name: "Commit", Type: StepTypeFunc, Required: true, Func: func(ctx *Context) error { return nil }, Undo: undo("Commit")},
+			{Name: "Optional", Type: StepTypeFunc, Required: false, Func: func(ctx *Context) error { return nil }},
+			{Name: "Push", Type: StepTypeFunc, Required: true, Func: func(ctx *Context) error { return nil }, Undo: undo("Push")},
+			{Name: "Tag", Type: StepTypeFunc, Required: true, Func: func(ctx *Context) error { return errors.New("tag failed") }, Undo: undo("Tag")},
+			{Name: "Never", Type: StepTypeFunc, Required: true, Func: func(ctx *Context) error { return nil }, Undo: undo("Never")},
+		},
+	}
+
+	result := NewRunner().Run(wf, NewContext("/tmp", "v1.0.0"))
+
+	if result.Success {
+		t.Fatal("Workflow should fail")
+	}
+	want := []string{"Tag", "Push", "Commit"}
+	if strings.Join(undone, ",") != strings.Join(want, ",") {
+		t.Errorf("undo order = %v, want %v", undone, want)
+	}
+	if len(result.Rollback) != 3 {
+		t.Errorf("Rollback results = %d, want 3", len(result.Rollback))
+	}
+	if !strings.Contains(result.Summary(), "Rollback:") {
+		t.Error("Summary should contain rollback section")
+	}
+	if len(result.ToJSON().Rollback) != 3 {
+		t.Error("JSON result should contain rollback steps")
+	}
+}
+
+func TestRunnerRun_NoRollback(t *testing.T) {
+	undoCalled := false
+	wf := &Workflow{
+		Name: "Test Workflow",
+		Steps: []Step{
+			{Name: "Commit", Type: StepTypeFunc, Required: true, Func: func(ctx *Context) error { return nil }, Undo: func(ctx *Context) error {
+				undoCalled = true
+				return nil
+			}},
+			{Name: "Tag", Type: StepTypeFunc, Required: true, Func: func(ctx *Context) error { return errors.New("tag failed") }},
+		},
+	}
+
+	runner := NewRunner()
+	runner.NoRollback = true
+	result := runner.Run(wf, NewContext("/tmp", "v1.0.0"))
+
+	if undoCalled {
+		t.Error("Undo should not run with NoRollback")
+	}
+	if len(result.Rollback) != 0 {
+		t.Error("Rollback should be empty with NoRollback")
+	}
+}
+
+func TestBuiltinWorkflows_ValidationNotConcurrentWithWrites(t *testing.T) {
+	writers := map[string]bool{StepChangelog: true, StepRoadmap: true, StepReadme: true, StepBump: true}
+	validators := map[string]bool{StepValidate: true, StepPMValidation: true, StepDocsValidation: true,
+		StepSecurityValidation: true, StepReleaseValidation: true}
+
+	for _, wf := range []*Workflow{
+		ReleaseWorkflow("v1.0.0"),
+		ReleaseBranchWorkflow("v1.0.0"),
+		TrainWorkflow("v1.0.0"),
+		HotfixWorkflow("v1.0.0"),
+	} {
+		g, err := buildGraph(wf.Steps)
+		if err != nil {
+			t.Fatalf("%s: %v", wf.Name, err)
+		}
+
+		// before[i][j] reports whether step j must finish before step i starts
+		before := make([]map[int]bool, len(wf.Steps))
+		var visit func(i int) map[int]bool
+		visit = func(i int) map[int]bool {
+			if before[i] == nil {
+				before[i] = make(map[int]bool)
+				for _, d := range g.deps[i] {
+					before[i][d] = true
+					for a := range visit(d) {
+						before[i][a] = true
+					}
+				}
+			}
+			return before[i]
+		}
+
+		for i, w := range wf.Steps {
+			for j, v := range wf.Steps {
+				if writers[w.Ref] && validators[v.Ref] && !visit(i)[j] && !visit(j)[i] {
+					t.Errorf("%s: %q can run concurrently with %q", wf.Name, w.Name, v.Name)
+				}
+			}
+		}
+	}
+}
+
+func TestBuildGraph_Errors(t *testing.T) {
+	noop := func(ctx *Context) error { return nil }
+	tests := []struct {
+		name  string
+		steps []Step
+		want  string
+	}{
+		{
+			name: "unknown dependency",
+			steps: []Step{
+				{Name: "A", Func: noop, DependsOn: []string{"Missing"}},
+			},
+			want: "unknown step",
+		},
+		{
+			name: "cycle",
+			steps: []Step{
+				{Name: "A", Func: noop, DependsOn: []string{"C"}},
+				{Name: "B", Func: noop, DependsOn: []string{"A"}},
+				{Name: "C", Func: noop, DependsOn: []string{"B"}},
+			},
+			want: "dependency cycle",
+		},
+		{
+			name: "duplicate name",
+			steps: []Step{
+				{Name: "A", Func: noop},
+				{Name: "A", Func: noop, DependsOn: []string{"A"}},
+			},
+			want: "duplicate step name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := buildGraph(tt.steps)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("buildGraph() error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunnerRun_InvalidGraph(t *testing.T) {
+	wf := &Workflow{
+		Name: "Cyclic",
+		Steps: []Step{
+			{Name: "A", Type: StepTypeFunc, DependsOn: []string{"B"}, Func: func(ctx *Context) error { return nil }},
+			{Name: "B", Type: StepTypeFunc, DependsOn: []string{"A"}, Func: func(ctx *Context) error { return nil }},
+		},
+	}
+
+	result := NewRunner().Run(wf, NewContext("/tmp", "v1.0.0"))
+	if result.Success {
+		t.Error("Workflow with a cycle should fail")
+	}
+	if len(result.Steps) != 0 {
+		t.Errorf("No steps should run, got %d", len(result.Steps))
+	}
+}
+
+func TestRunnerRun_Parallel(t *testing.T) {
+	// B and C both wait for each other to start, so they only finish if run concurrently
+	bStarted := make(chan struct{})
+	cStarted := make(chan struct{})
+	wait := func(own, other chan struct{}) StepFunc {
+		return func(ctx *Context) error {
+			close(own)
+			<-other
+			ctx.Log("ran %s", ctx.Data["a"])
+			return nil
+		}
+	}
+
+	var order []string
+	wf := &Workflow{
+		Name: "Parallel",
+		Steps: []Step{
+			{Name: "A", Type: StepTypeFunc, Required: true, Func: func(ctx *Context) error {
+				ctx.Data["a"] = "after A"
+				order = append(order, "A")
+				return nil
+			}},
+			{Name: "B", Type: StepTypeFunc, Required: true, DependsOn: []string{"A"}, Func: wait(bStarted, cStarted)},
+			{Name: "C", Type: StepTypeFunc, Required: true, DependsOn: []string{"A"}, Func: wait(cStarted, bStarted)},
+			{Name: "D", Type: StepTypeFunc, Required: true, DependsOn: []string{"B", "C"}, Func: func(ctx *Context) error {
+				order = append(order, "D")
+				return nil
+			}},
+		},
+	}
+
+	runner := NewRunner()
+	runner.MaxWorkers = 2
+	result := runner.Run(wf, NewContext("/tmp", "v1.0.0"))
+
+	if !result.Success {
+		t.Fatalf("Workflow should succeed: %s", result.Output)
+	}
+	names := make([]string, len(result.Steps))
+	for i, s := range result.Steps {
+		names[i] = s.Name
+	}
+	if strings.Join(names, ",") != "A,B,C,D" {
+		t.Errorf("results should be in definition order, got %v", names)
+	}
+	if strings.Join(order, ",") != "A,D" {
+		t.Errorf("A should run before D, got %v", order)
+	}
+
+	// Output is grouped per step in definition order
+	bIdx := strings.Index(result.Output, "→ B")
+	cIdx := strings.Index(result.Output, "→ C")
+	if bIdx < 0 || cIdx < 0 || bIdx > cIdx {
+		t.Errorf("output should list B before C, got: %s", result.Output)
+	}
+	if !strings.Contains(result.Output, "ran after A") {
+		t.Error("data from A should be visible to dependents")
+	}
+}
+
+func TestRunnerRun_StepTimeout(t *testing.T) {
+	wf := &Workflow{
+		Name: "Timeout",
+		Steps: []Step{
+			{Name: "Hang", Type: StepTypeFunc, Required: true, Timeout: 20 * time.Millisecond, Func: func(ctx *Context) error {
+				<-ctx.Ctx.Done()
+				return ctx.Ctx.Err()
+			}},
+		},
+	}
+
+	result := NewRunner().Run(wf, NewContext("/tmp", "v1.0.0"))
+
+	if result.Success || result.Cancelled {
+		t.Fatalf("timed out step should fail the workflow, got success=%v cancelled=%v", result.Success, result.Cancelled)
+	}
+	if err := result.Steps[0].Error; err == nil || !strings.Contains(err.Error(), "timed out after 20ms") {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+}
+
+func TestRunnerRun_StepTimeoutKeepsVersion(t *testing.T) {
+	wf := &Workflow{
+		Name: "Timeout",
+		Steps: []Step{
+			{Name: "Pick version", Type: StepTypeFunc, Required: true, Timeout: time.Minute, Func: func(ctx *Context) error {
+				ctx.Version = "v1.1.0"
+				return nil
+			}},
+			{Name: "Use version", Type: StepTypeFunc, Required: true, Func: func(ctx *Context) error {
+				ctx.Log("releasing %s", ctx.Version)
+				return nil
+			}},
+		},
+	}
+
+	ctx := NewContext("/tmp", "")
+	result := NewRunner().Run(wf, ctx)
+
+	if !result.Success {
+		t.Fatalf("workflow failed: %v", result.Error)
+	}
+	if ctx.Version != "v1.1.0" || !strings.Contains(result.Output, "releasing v1.1.0") {
+		t.Errorf("version set under a timeout was lost: %q\n%s", ctx.Version, result.Output)
+	}
+}
+
+func TestRunnerRun_Cancelled(t *testing.T) {
+	goCtx, cancel := context.WithCancel(context.Background())
+	undoCalled := false
+	ranAfter := false
+
+	wf := &Workflow{
+		Name: "Cancel",
+		Steps: []Step{
+			{Name: "Commit", Type: StepTypeFunc, Required: true, Func: func(ctx *Context) error { return nil }, Undo: func(ctx *Context) error {
+				undoCalled = true
+				return nil
+			}},
+			{Name: "Wait", Type: StepTypeFunc, Required: true, Func: func(ctx *Context) error {
+				cancel()
+				<-ctx.Ctx.Done()
+				return ctx.Ctx.Err()
+			}},
+			{Name: "Tag", Type: StepTypeFunc, Required: true, Func: func(ctx *Context) error {
+				ranAfter = true
+				return nil
+			}},
+		},
+	}
+
+	ctx := NewContext("/tmp", "v1.0.0")
+	ctx.Ctx = goCtx
+	result := NewRunner().Run(wf, ctx)
+
+	if result.Success || !result.Cancelled {
+		t.Fatalf("workflow should be cancelled, got success=%v cancelled=%v", result.Success, result.Cancelled)
+	}
+	if ranAfter {
+		t.Error("no steps should start after cancellation")
+	}
+	if undoCalled {
+		t.Error("cancellation should not roll back")
+	}
+	if len(result.Steps) != 2 || !result.Steps[1].Cancelled {
+		t.Errorf("Wait step should be marked cancelled: %+v", result.Steps)
+	}
+	if !strings.Contains(result.Summary(), "Cancelled") {
+		t.Error("Summary should report cancellation")
+	}
+	if !result.ToJSON().Steps[1].Cancelled {
+		t.Error("JSON result should mark the cancelled step")
+	}
+}